@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/tweet/countalert"
+	recentcount "github.com/0dayfall/ctw/internal/tweet/recentcount"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newAlertCommand())
+}
+
+func newAlertCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alert",
+		Short: "Notify when tweet volume changes",
+	}
+
+	cmd.AddCommand(newAlertCountsCommand())
+
+	return cmd
+}
+
+func newAlertCountsCommand() *cobra.Command {
+	var (
+		query     string
+		interval  time.Duration
+		threshold string
+		clearText string
+		baseline  time.Duration
+		statePath string
+		webhook   string
+		command   string
+		emailTo   []string
+		emailFrom string
+		smtpAddr  string
+		once      bool
+		dryRun    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "counts",
+		Short: "Alert when the per-minute tweet count of a query spikes",
+		Long: `Poll the per-minute counts of a query and notify when a minute spikes.
+
+Every --interval the new minutes are fetched from /2/tweets/counts/recent and
+compared with the --baseline minutes before them, which are kept in a state
+file so restarts keep the baseline. --threshold is either a z-score against
+the baseline (3sigma) or an absolute count per minute (500).
+
+An alert fires once when a minute reaches the threshold and resolves when a
+minute falls below --clear (half the threshold by default). A count that
+hovers around the threshold therefore does not page again and again. The
+first run only judges the newest minute, so old spikes are never reported.
+
+Notifications go to every configured target:
+  --webhook   receives the alert as a JSON POST with a Slack-compatible "text"
+  --exec      runs with the alert as JSON on stdin and CTW_ALERT_STATUS,
+              CTW_ALERT_QUERY, CTW_ALERT_COUNT and CTW_ALERT_Z set
+              (split on spaces, no shell quoting)
+  --email-to  sends a plain-text mail through --smtp-addr
+
+Examples:
+  # Page through a chat webhook when mentions of the brand spike
+  ctw alert counts --query '"Acme" OR @acme' --interval 5m --threshold 3sigma \
+    --webhook https://hooks.slack.com/services/...
+
+  # Mail the on-call address through the local MTA from cron
+  ctw alert counts --query acme --threshold 500 --email-to oncall@example.com --once`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(query) == "" {
+				return errors.New("--query is required")
+			}
+			level, err := countalert.ParseThreshold(threshold)
+			if err != nil {
+				return err
+			}
+			var clearLevel countalert.Threshold
+			if clearText != "" {
+				if clearLevel, err = countalert.ParseThreshold(clearText); err != nil {
+					return err
+				}
+			}
+			notifiers, err := alertNotifiers(webhook, command, emailTo, emailFrom, smtpAddr)
+			if err != nil {
+				return err
+			}
+			if len(notifiers) == 0 && !dryRun {
+				return errors.New("at least one of --webhook, --exec or --email-to is required (or use --dry-run)")
+			}
+
+			if statePath == "" {
+				if resolvedSettings.CacheDir == "" {
+					return errors.New("--state is required when no cache directory is available")
+				}
+				sum := sha256.Sum256([]byte(query))
+				statePath = filepath.Join(resolvedSettings.CacheDir, "alert-counts-"+hex.EncodeToString(sum[:6])+".json")
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			monitor, err := countalert.New(countalert.Config{
+				Query:     query,
+				StatePath: statePath,
+				Interval:  interval,
+				Baseline:  baseline,
+				Threshold: level,
+				Clear:     clearLevel,
+			}, recentcount.NewService(c), notifiers, logger)
+			if err != nil {
+				return err
+			}
+			monitor.DryRun = dryRun
+
+			if once {
+				result, rateLimits, err := monitor.Check(ctx)
+				if err != nil {
+					return err
+				}
+				logger.Info("counts poll", "evaluated", result.Evaluated, "latest", result.Latest, "z", result.Z, "firing", result.Firing, "alerts", len(result.Alerts))
+				printRateLimits(rateLimits)
+				return nil
+			}
+
+			logger.Info("count alert running; press Ctrl+C to stop", "query", query, "state", statePath, "interval", interval, "threshold", level.String())
+			return monitor.Run(ctx)
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "Query whose per-minute count to watch")
+	cmd.Flags().DurationVar(&interval, "interval", countalert.DefaultInterval, "Time between polls")
+	cmd.Flags().StringVar(&threshold, "threshold", "3sigma", "Alert level: a z-score such as 3sigma or a count per minute such as 500")
+	cmd.Flags().StringVar(&clearText, "clear", "", "Level below which a firing alert resolves, in the unit of --threshold (default: half of --threshold)")
+	cmd.Flags().DurationVar(&baseline, "baseline", countalert.DefaultBaseline, "Window of earlier minutes each minute is compared with")
+	cmd.Flags().StringVar(&statePath, "state", "", "State file (default: alert-counts-<query hash>.json in the cache directory)")
+	cmd.Flags().StringVar(&webhook, "webhook", "", "URL that receives each alert as a JSON POST")
+	cmd.Flags().StringVar(&command, "exec", "", "Command that receives each alert as JSON on stdin")
+	cmd.Flags().StringArrayVar(&emailTo, "email-to", nil, "Email address to notify (can be specified multiple times)")
+	cmd.Flags().StringVar(&emailFrom, "email-from", "", "Sender address of alert emails (default: ctw@<hostname>)")
+	cmd.Flags().StringVar(&smtpAddr, "smtp-addr", "localhost:25", "SMTP server for alert emails")
+	cmd.Flags().BoolVar(&once, "once", false, "Poll once and exit")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Log alerts without sending them or saving state")
+
+	return cmd
+}
+
+// alertNotifiers builds every notifier selected by the flags.
+func alertNotifiers(webhook, command string, emailTo []string, emailFrom, smtpAddr string) ([]countalert.Notifier, error) {
+	var notifiers []countalert.Notifier
+	if url := strings.TrimSpace(webhook); url != "" {
+		notifier, err := countalert.NewWebhookNotifier(url, nil)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	if strings.TrimSpace(command) != "" {
+		notifier, err := countalert.NewCommandNotifier(strings.Fields(command))
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	if len(emailTo) > 0 {
+		if emailFrom == "" {
+			host, err := os.Hostname()
+			if err != nil || host == "" {
+				host = "localhost"
+			}
+			emailFrom = "ctw@" + host
+		}
+		notifier, err := countalert.NewEmailNotifier(countalert.EmailConfig{Addr: smtpAddr, From: emailFrom, To: emailTo})
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
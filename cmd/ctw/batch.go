@@ -0,0 +1,510 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/handles"
+	"github.com/0dayfall/ctw/internal/ratelimit"
+	"github.com/0dayfall/ctw/internal/tweet/bookmarks"
+	"github.com/0dayfall/ctw/internal/tweet/likes"
+	"github.com/0dayfall/ctw/internal/tweet/lookup"
+	publish "github.com/0dayfall/ctw/internal/tweet/publish"
+	"github.com/0dayfall/ctw/internal/tweet/retweets"
+	lookupsvc "github.com/0dayfall/ctw/internal/users/lookup"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultBatchConcurrency = 4
+	// batchRateLimitAttempts bounds how often one item is retried after the
+	// client's own retries gave up on a 429.
+	batchRateLimitAttempts = 3
+)
+
+// batchItemFunc performs an action on one item, a tweet or user id.
+type batchItemFunc func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error)
+
+// batchAction is one command the batch runner can apply per input line.
+type batchAction struct {
+	group, name string
+	short       string
+	// users marks items as user ids, which may be given as @handles.
+	users bool
+	// actor is the flag naming the acting user, empty when there is none.
+	actor string
+	// query enables --param and tweet field flags.
+	query bool
+	bind  func(c *client.Client, actorID string, params map[string]string) batchItemFunc
+}
+
+func (a batchAction) slug() string {
+	return a.group + "-" + a.name
+}
+
+var batchActions = []batchAction{
+	{group: "likes", name: "add", short: "Like each tweet", actor: "user-id", bind: func(c *client.Client, actorID string, _ map[string]string) batchItemFunc {
+		service := likes.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.LikeTweet(ctx, actorID, item)
+		}
+	}},
+	{group: "likes", name: "remove", short: "Unlike each tweet", actor: "user-id", bind: func(c *client.Client, actorID string, _ map[string]string) batchItemFunc {
+		service := likes.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.UnlikeTweet(ctx, actorID, item)
+		}
+	}},
+	{group: "bookmarks", name: "add", short: "Bookmark each tweet", actor: "user-id", bind: func(c *client.Client, actorID string, _ map[string]string) batchItemFunc {
+		service := bookmarks.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.Add(ctx, actorID, item)
+		}
+	}},
+	{group: "bookmarks", name: "remove", short: "Remove each tweet from bookmarks", actor: "user-id", bind: func(c *client.Client, actorID string, _ map[string]string) batchItemFunc {
+		service := bookmarks.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.Remove(ctx, actorID, item)
+		}
+	}},
+	{group: "retweets", name: "add", short: "Retweet each tweet", actor: "user-id", bind: func(c *client.Client, actorID string, _ map[string]string) batchItemFunc {
+		service := retweets.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.Retweet(ctx, actorID, item)
+		}
+	}},
+	{group: "retweets", name: "remove", short: "Undo the retweet of each tweet", actor: "user-id", bind: func(c *client.Client, actorID string, _ map[string]string) batchItemFunc {
+		service := retweets.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.Unretweet(ctx, actorID, item)
+		}
+	}},
+	{group: "tweets", name: "get", short: "Fetch each tweet", query: true, bind: func(c *client.Client, _ string, params map[string]string) batchItemFunc {
+		service := lookup.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.GetTweet(ctx, item, params)
+		}
+	}},
+	{group: "tweets", name: "delete", short: "Delete each tweet", bind: func(c *client.Client, _ string, _ map[string]string) batchItemFunc {
+		service := publish.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return service.DeleteTweet(ctx, item)
+		}
+	}},
+	userBatchAction("block", "Block each user", (*lookupsvc.Service).Block),
+	userBatchAction("unblock", "Unblock each user", (*lookupsvc.Service).Unblock),
+	userBatchAction("follow", "Follow each user", (*lookupsvc.Service).Follow),
+	userBatchAction("unfollow", "Unfollow each user", (*lookupsvc.Service).Unfollow),
+	userBatchAction("mute", "Mute each user", (*lookupsvc.Service).Mute),
+	userBatchAction("unmute", "Unmute each user", (*lookupsvc.Service).Unmute),
+}
+
+func userBatchAction(name, short string, change relationshipFunc) batchAction {
+	return batchAction{group: "users", name: name, short: short, users: true, actor: "source-id", bind: func(c *client.Client, actorID string, _ map[string]string) batchItemFunc {
+		service := lookupsvc.NewService(c)
+		return func(ctx context.Context, item string) (any, client.RateLimitSnapshot, error) {
+			return change(service, ctx, actorID, item)
+		}
+	}}
+}
+
+func init() {
+	rootCmd.AddCommand(newBatchCommand())
+}
+
+func newBatchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Apply an action to every id in a file",
+		Long: `Apply an action to every tweet or user id listed in a file, one per line.
+
+Items run with bounded concurrency and are paced against the rate-limit
+headers of each response. One NDJSON result is written per item. Completed
+items are appended to a progress file, so rerunning the same command after an
+interruption or failure skips what already succeeded.`,
+	}
+
+	groups := map[string]*cobra.Command{}
+	for _, action := range batchActions {
+		group, ok := groups[action.group]
+		if !ok {
+			group = &cobra.Command{Use: action.group, Short: "Batch " + action.group + " actions"}
+			groups[action.group] = group
+			cmd.AddCommand(group)
+		}
+		group.AddCommand(newBatchActionCommand(action))
+	}
+
+	return cmd
+}
+
+func newBatchActionCommand(action batchAction) *cobra.Command {
+	var (
+		fromFile     string
+		progressPath string
+		resultsPath  string
+		concurrency  int
+		actorID      string
+		me           *bool
+		fieldOpts    *fieldFlags
+		paramsFlag   []string
+	)
+
+	itemKind := "tweet"
+	if action.users {
+		itemKind = "user"
+	}
+
+	cmd := &cobra.Command{
+		Use:   action.name,
+		Short: action.short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(fromFile) == "" {
+				return errors.New("--from-file is required (- for stdin)")
+			}
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams := map[string]string{}
+			if action.query {
+				var err error
+				if queryParams, err = parseKeyValuePairs(paramsFlag); err != nil {
+					return fmt.Errorf("parse params: %w", err)
+				}
+				if err := fieldOpts.apply(queryParams); err != nil {
+					return err
+				}
+			}
+
+			items, err := readRawLines(fromFile)
+			if err != nil {
+				return err
+			}
+			items = uniqueItems(items)
+
+			if progressPath == "" && fromFile != "-" {
+				progressPath = fromFile + "." + action.slug() + ".progress"
+			}
+			progress, err := openBatchProgress(progressPath)
+			if err != nil {
+				return err
+			}
+			defer progress.Close()
+
+			var pending []string
+			for _, item := range items {
+				if !progress.done(item) {
+					pending = append(pending, item)
+				}
+			}
+			if skipped := len(items) - len(pending); skipped > 0 {
+				logger.Info("skipping completed items", "skipped", skipped, "progress", progressPath)
+			}
+			if len(pending) == 0 {
+				return nil
+			}
+
+			var out io.Writer = os.Stdout
+			if resultsPath != "" {
+				f, err := os.OpenFile(resultsPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+				if err != nil {
+					return fmt.Errorf("open results: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+			if action.actor != "" {
+				if err := resolveUserFlags(ctx, c, *me, &actorID); err != nil {
+					return err
+				}
+				if actorID, err = c.ResolveUserID(ctx, actorID); err != nil {
+					return err
+				}
+			}
+
+			var ids map[string]string
+			if action.users {
+				if ids, err = resolveBatchHandles(ctx, c, pending); err != nil {
+					return err
+				}
+			}
+
+			runner := &batchRunner{
+				do:          action.bind(c, actorID, queryParams),
+				ids:         ids,
+				concurrency: concurrency,
+				pacer:       ratelimit.NewPacer(nil),
+				progress:    progress,
+				results:     json.NewEncoder(out),
+			}
+			failed, err := runner.run(ctx, pending)
+			if err != nil {
+				return err
+			}
+			logger.Info("batch complete", "items", len(pending), "failed", failed)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d items failed", failed, len(pending))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "File with one "+itemKind+" id per line (- for stdin)")
+	cmd.Flags().StringVar(&progressPath, "progress", "", "File recording completed items (defaults to <from-file>."+action.slug()+".progress; none for stdin)")
+	cmd.Flags().StringVar(&resultsPath, "results", "", "Append NDJSON results to this file instead of stdout")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultBatchConcurrency, "Maximum requests in flight")
+	if action.actor != "" {
+		cmd.Flags().StringVar(&actorID, action.actor, "", "ID or @handle of the user performing the action (defaults to the authenticated user)")
+		me = addMeFlag(cmd, action.actor)
+	}
+	if action.query {
+		cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+		fieldOpts = addFieldFlags(cmd, fields.Tweets)
+	}
+
+	return cmd
+}
+
+// uniqueItems drops repeated lines, keeping the first occurrence.
+func uniqueItems(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	unique := items[:0]
+	for _, item := range items {
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		unique = append(unique, item)
+	}
+	return unique
+}
+
+// resolveBatchHandles maps each @handle item to its user id in one batched,
+// cached lookup. Plain ids map to themselves and unknown handles are absent.
+func resolveBatchHandles(ctx context.Context, c *client.Client, items []string) (map[string]string, error) {
+	ids := make(map[string]string, len(items))
+	var usernames []string
+	for _, item := range items {
+		if handles.IsHandle(item) {
+			usernames = append(usernames, item)
+		} else {
+			ids[item] = item
+		}
+	}
+	if len(usernames) == 0 {
+		return ids, nil
+	}
+
+	resolved, err := resolveHandles(ctx, c, usernames)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range usernames {
+		if id, ok := resolved[handles.Normalize(item)]; ok {
+			ids[item] = id
+		}
+	}
+	return ids, nil
+}
+
+// batchResult is the NDJSON record written for each item.
+type batchResult struct {
+	Item   string `json:"item"`
+	ID     string `json:"id,omitempty"`
+	OK     bool   `json:"ok"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type batchRunner struct {
+	do batchItemFunc
+	// ids maps user items, which may be @handles, to user ids. It is nil
+	// when items are used as given.
+	ids         map[string]string
+	concurrency int
+	pacer       *ratelimit.Pacer
+	progress    *batchProgress
+
+	mu      sync.Mutex
+	results *json.Encoder
+	failed  int
+	pending int
+}
+
+// run processes items with at most concurrency requests in flight. Item
+// failures are reported and counted; an auth error stops the run because
+// every later request would fail the same way.
+func (r *batchRunner) run(ctx context.Context, items []string) (int, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	r.pending = len(items)
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for range min(r.concurrency, len(items)) {
+		wg.Go(func() {
+			for item := range queue {
+				if err := r.process(ctx, item); err != nil {
+					cancel(err)
+				}
+			}
+		})
+	}
+
+feed:
+	for _, item := range items {
+		select {
+		case queue <- item:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return r.failed, err
+	}
+	return r.failed, nil
+}
+
+// process runs one item and records its result. It returns an error only
+// when the whole run should stop.
+func (r *batchRunner) process(ctx context.Context, item string) error {
+	result := batchResult{Item: item}
+	id, ok := item, true
+	if r.ids != nil {
+		id, ok = r.ids[item]
+	}
+	if id != item {
+		result.ID = id
+	}
+
+	if !ok {
+		result.Error = "user not found"
+		return r.record(result)
+	}
+
+	var (
+		response any
+		err      error
+	)
+	for attempt := 1; ; attempt++ {
+		if err = r.pacer.Wait(ctx); err != nil {
+			return err
+		}
+		var limits client.RateLimitSnapshot
+		response, limits, err = r.do(ctx, id)
+		r.pacer.Observe(limits, r.remaining())
+		if !client.IsRateLimited(err) || attempt == batchRateLimitAttempts {
+			break
+		}
+		logger.Warn("rate limited; waiting for reset", "item", item, "attempt", attempt)
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		return context.Cause(ctx)
+	case client.IsAuth(err):
+		return err
+	case err != nil:
+		result.Error = err.Error()
+	default:
+		result.OK = true
+		result.Result = response
+	}
+	return r.record(result)
+}
+
+func (r *batchRunner) remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pending
+}
+
+func (r *batchRunner) record(result batchResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending--
+	if !result.OK {
+		r.failed++
+	}
+	if err := r.results.Encode(result); err != nil {
+		return fmt.Errorf("write result: %w", err)
+	}
+	if result.OK {
+		return r.progress.add(result.Item)
+	}
+	return nil
+}
+
+// batchProgress is the append-only record of items a batch has completed.
+// A nil progress records nothing.
+type batchProgress struct {
+	completed map[string]bool
+	file      *os.File
+}
+
+func openBatchProgress(path string) (*batchProgress, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	completed := map[string]bool{}
+	lines, err := readRawLines(path)
+	switch {
+	case err == nil:
+		for _, line := range lines {
+			completed[line] = true
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("read progress: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open progress: %w", err)
+	}
+	return &batchProgress{completed: completed, file: f}, nil
+}
+
+func (p *batchProgress) done(item string) bool {
+	return p != nil && p.completed[item]
+}
+
+func (p *batchProgress) add(item string) error {
+	if p == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintln(p.file, item); err != nil {
+		return fmt.Errorf("write progress: %w", err)
+	}
+	return nil
+}
+
+func (p *batchProgress) Close() error {
+	if p == nil {
+		return nil
+	}
+	return p.file.Close()
+}
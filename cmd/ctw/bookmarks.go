@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/0dayfall/ctw/internal/fields"
 	"github.com/0dayfall/ctw/internal/tweet/bookmarks"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +30,7 @@ func newBookmarksCommand() *cobra.Command {
 
 func newBookmarksAddCommand() *cobra.Command {
 	var (
+		me      *bool
 		userID  string
 		tweetID string
 	)
@@ -37,9 +39,6 @@ func newBookmarksAddCommand() *cobra.Command {
 		Use:   "add",
 		Short: "Bookmark a tweet",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 			if strings.TrimSpace(tweetID) == "" {
 				return errors.New("--tweet-id is required")
 			}
@@ -53,6 +52,9 @@ func newBookmarksAddCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := bookmarks.NewService(c)
 			response, rateLimits, err := service.Add(ctx, userID, tweetID)
@@ -60,7 +62,7 @@ func newBookmarksAddCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -68,7 +70,8 @@ func newBookmarksAddCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user bookmarking")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user bookmarking (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID of the tweet to bookmark")
 
 	return cmd
@@ -76,6 +79,7 @@ func newBookmarksAddCommand() *cobra.Command {
 
 func newBookmarksRemoveCommand() *cobra.Command {
 	var (
+		me      *bool
 		userID  string
 		tweetID string
 	)
@@ -84,9 +88,6 @@ func newBookmarksRemoveCommand() *cobra.Command {
 		Use:   "remove",
 		Short: "Remove a bookmark",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 			if strings.TrimSpace(tweetID) == "" {
 				return errors.New("--tweet-id is required")
 			}
@@ -100,6 +101,9 @@ func newBookmarksRemoveCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := bookmarks.NewService(c)
 			response, rateLimits, err := service.Remove(ctx, userID, tweetID)
@@ -107,7 +111,7 @@ func newBookmarksRemoveCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -115,7 +119,8 @@ func newBookmarksRemoveCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user removing the bookmark")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user removing the bookmark (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID of the tweet to unbookmark")
 
 	return cmd
@@ -123,6 +128,9 @@ func newBookmarksRemoveCommand() *cobra.Command {
 
 func newBookmarksListCommand() *cobra.Command {
 	var (
+		me         *bool
+		fieldOpts  *fieldFlags
+		storeOpts  *storeFlag
 		userID     string
 		paramsFlag []string
 	)
@@ -131,9 +139,6 @@ func newBookmarksListCommand() *cobra.Command {
 		Use:   "list",
 		Short: "List bookmarked tweets for a user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 
 			ctx := cmd.Context()
 			if ctx == nil {
@@ -144,11 +149,17 @@ func newBookmarksListCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("parse params: %w", err)
 			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := bookmarks.NewService(c)
 			response, rateLimits, err := service.List(ctx, userID, queryParams)
@@ -156,7 +167,11 @@ func newBookmarksListCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := storeOpts.save(ctx, "bookmarks list: "+userID, response); err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -164,8 +179,12 @@ func newBookmarksListCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user whose bookmarks to list")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user whose bookmarks to list (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+	storeOpts = addStoreFlag(cmd)
+
 	return cmd
 }
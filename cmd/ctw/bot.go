@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/tweet/mentionbot"
+	"github.com/0dayfall/ctw/internal/tweet/publish"
+	"github.com/0dayfall/ctw/internal/tweet/timelines"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newBotCommand())
+}
+
+func newBotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bot",
+		Short: "Run automated responders",
+	}
+
+	cmd.AddCommand(newBotMentionsCommand())
+
+	return cmd
+}
+
+func newBotMentionsCommand() *cobra.Command {
+	var (
+		templateText       string
+		command            string
+		webhook            string
+		statePath          string
+		interval           time.Duration
+		cooldown           time.Duration
+		maxPerConversation int
+		once               bool
+		dryRun             bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mentions",
+		Short: "Reply to tweets that mention the authenticated user",
+		Long: `Poll mentions of the authenticated user and reply to each new one.
+
+The reply comes from exactly one handler:
+  --reply-template  a Go text/template rendered against {{.Tweet}} and
+                    {{.Author}}
+  --command         a program that receives the mention as JSON on stdin, with
+                    CTW_TWEET_ID, CTW_AUTHOR_ID and CTW_AUTHOR_USERNAME set;
+                    its output is the reply (split on spaces, no shell quoting)
+  --webhook         a URL that receives the mention as a JSON POST; the
+                    response body, or its "text" field for JSON, is the reply
+An empty reply answers nothing.
+
+Replies are posted in reply to the mention. The newest handled mention
+(since_id) and the replies sent are kept in a state file, and the first run
+only records existing mentions so old ones are never answered. The bot skips
+its own tweets and retweets, never answers a mention twice, waits --cooldown
+between replies to the same author and stops replying in a conversation after
+--max-per-conversation replies, so two bots cannot reply to each other forever.
+
+Examples:
+  # Try a template without posting anything
+  ctw bot mentions --reply-template 'Thanks @{{.Author.Username}}!' --dry-run --log-level debug
+
+  # Let a script decide, at most one reply per author every 10 minutes
+  ctw bot mentions --command ./reply.sh --cooldown 10m
+
+  # Poll once per invocation from cron
+  ctw bot mentions --webhook http://localhost:8080/mention --once`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			handler, err := mentionHandler(templateText, command, webhook)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+			selfID, err := c.UserID(ctx)
+			if err != nil {
+				return fmt.Errorf("resolve authenticated user: %w", err)
+			}
+
+			if statePath == "" {
+				if resolvedSettings.CacheDir == "" {
+					return errors.New("--state is required when no cache directory is available")
+				}
+				statePath = filepath.Join(resolvedSettings.CacheDir, "mentions-"+selfID+".json")
+			}
+
+			cfg := mentionbot.Config{
+				StatePath:          statePath,
+				Interval:           interval,
+				Cooldown:           cooldown,
+				MaxPerConversation: maxPerConversation,
+			}
+			responder := mentionbot.New(cfg, timelines.NewService(c), publish.NewService(c), handler, selfID, logger)
+			responder.DryRun = dryRun
+
+			if once {
+				result, rateLimits, err := responder.Poll(ctx)
+				if err != nil {
+					return err
+				}
+				logger.Info("mentions poll", "mentions", result.Mentions, "replies", result.Replies, "skipped", result.Skipped, "failed", result.Failed)
+				printRateLimits(rateLimits)
+				return nil
+			}
+
+			logger.Info("mention bot running; press Ctrl+C to stop", "state", statePath, "interval", interval)
+			return responder.Run(ctx)
+		},
+	}
+
+	cmd.Flags().StringVar(&templateText, "reply-template", "", "Reply template, e.g. 'Thanks @{{.Author.Username}}!'")
+	cmd.Flags().StringVar(&command, "command", "", "Command that prints the reply for the mention JSON on stdin")
+	cmd.Flags().StringVar(&webhook, "webhook", "", "URL that returns the reply for the mention JSON it is POSTed")
+	cmd.Flags().StringVar(&statePath, "state", "", "State file (default: mentions-<user-id>.json in the cache directory)")
+	cmd.Flags().DurationVar(&interval, "interval", mentionbot.DefaultInterval, "Time between polls")
+	cmd.Flags().DurationVar(&cooldown, "cooldown", 10*time.Minute, "Minimum time between replies to the same author")
+	cmd.Flags().IntVar(&maxPerConversation, "max-per-conversation", mentionbot.DefaultMaxPerConversation, "Maximum replies in one conversation")
+	cmd.Flags().BoolVar(&once, "once", false, "Poll once and exit")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Log the replies that would be posted without posting them or saving state")
+
+	return cmd
+}
+
+// mentionHandler builds the single handler selected by the flags.
+func mentionHandler(templateText, command, webhook string) (mentionbot.Handler, error) {
+	set := 0
+	for _, value := range []string{templateText, command, webhook} {
+		if strings.TrimSpace(value) != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errors.New("exactly one of --reply-template, --command or --webhook is required")
+	}
+
+	switch {
+	case strings.TrimSpace(templateText) != "":
+		return mentionbot.NewTemplateHandler(templateText)
+	case strings.TrimSpace(command) != "":
+		return mentionbot.NewCommandHandler(strings.Fields(command))
+	default:
+		return mentionbot.NewWebhookHandler(strings.TrimSpace(webhook), nil)
+	}
+}
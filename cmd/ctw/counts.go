@@ -20,6 +20,7 @@ func newCountsCommand() *cobra.Command {
 
 	cmd.AddCommand(newCountsRecentCommand())
 	cmd.AddCommand(newCountsAllCommand())
+	cmd.AddCommand(newCountsAnalyzeCommand())
 	return cmd
 }
 
@@ -65,7 +66,7 @@ func newCountsRecentCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -85,11 +86,15 @@ func newCountsAllCommand() *cobra.Command {
 		query       string
 		granularity string
 		extraPairs  []string
+		maxPages    int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "all",
 		Short: "Call the /2/tweets/counts/all endpoint",
+		Long: `Call the /2/tweets/counts/all endpoint, following next_token until every
+bucket between start_time and end_time is fetched. The buckets are printed
+oldest first with the total summed across pages.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if query == "" {
 				return errors.New("query is required")
@@ -117,12 +122,12 @@ func newCountsAllCommand() *cobra.Command {
 			}
 
 			service := recentcount.NewService(c)
-			response, rateLimits, err := service.GetAllCount(ctx, query, granularity, params)
+			response, rateLimits, err := service.GetAllCountPages(ctx, query, granularity, params, maxPages)
 			if err != nil {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -133,6 +138,7 @@ func newCountsAllCommand() *cobra.Command {
 	cmd.Flags().StringVar(&query, "query", "", "Query to aggregate counts for")
 	cmd.Flags().StringVar(&granularity, "granularity", "day", "Granularity (minute|hour|day)")
 	cmd.Flags().StringArrayVar(&extraPairs, "param", nil, "Additional query parameter in key=value format")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop after this many pages (0 fetches every page)")
 
 	return cmd
 }
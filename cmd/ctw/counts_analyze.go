@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/tweet/countseries"
+	recentcount "github.com/0dayfall/ctw/internal/tweet/recentcount"
+	"github.com/spf13/cobra"
+)
+
+const (
+	chartNone      = "none"
+	chartSparkline = "sparkline"
+	chartBars      = "bars"
+)
+
+func newCountsAnalyzeCommand() *cobra.Command {
+	var (
+		queries     []string
+		granularity string
+		extraPairs  []string
+		fullArchive bool
+		maxPages    int
+		average     int
+		baseline    int
+		threshold   float64
+		chart       string
+		width       int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Compare tweet counts of several queries and detect spikes",
+		Long: `Fetch tweet counts for one or more queries, align them on the same time
+buckets and report totals, moving averages and spikes.
+
+A spike is a bucket whose count is --threshold standard deviations above the
+mean of the --baseline buckets before it. The deviation is at least the
+square root of the mean, so low, steady volumes do not flag every wobble.
+
+--chart prints a terminal chart instead of the structured output. Otherwise
+-o json prints the full analysis and -o table|csv|tsv print the aligned
+series: one row per bucket with a column of counts per query.
+
+Examples:
+  ctw counts analyze --query golang --query rust --granularity hour --chart sparkline
+  ctw counts analyze --query '#golang' --chart bars --granularity day
+  ctw counts analyze --all --query golang --param start_time=2024-01-01T00:00:00Z -o csv > golang.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(queries) == 0 {
+				return errors.New("at least one --query is required")
+			}
+			if err := validCountQueries(queries); err != nil {
+				return err
+			}
+			switch chart {
+			case chartNone, chartSparkline, chartBars:
+			default:
+				return fmt.Errorf("invalid --chart %q (expected %s|%s|%s)", chart, chartNone, chartSparkline, chartBars)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			params := map[string]string{}
+			if len(extraPairs) > 0 {
+				extras, err := parseKeyValuePairs(extraPairs)
+				if err != nil {
+					return err
+				}
+				for k, v := range extras {
+					params[k] = v
+				}
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := recentcount.NewService(c)
+			inputs := make([]countseries.Input, 0, len(queries))
+			var rateLimits client.RateLimitSnapshot
+			for _, query := range queries {
+				var response recentcount.CountResponse
+				if fullArchive {
+					response, rateLimits, err = service.GetAllCountPages(ctx, query, granularity, params, maxPages)
+				} else {
+					response, rateLimits, err = service.GetRecentCount(ctx, query, granularity, params)
+				}
+				if err != nil {
+					printRateLimits(rateLimits)
+					return fmt.Errorf("counts for %q: %w", query, err)
+				}
+				inputs = append(inputs, countseries.Input{Query: query, Counts: response.Data})
+			}
+
+			analysis := countseries.Align(inputs)
+			analysis.Detect(average, baseline, threshold)
+
+			switch {
+			case chart == chartSparkline:
+				err = analysis.WriteSparklines(os.Stdout)
+			case chart == chartBars:
+				err = analysis.WriteBars(os.Stdout, width)
+			case outputFormat == formatTable || outputFormat == formatCSV || outputFormat == formatTSV:
+				err = writeCountSeries(analysis)
+			default:
+				err = printOutput(analysis)
+			}
+			if err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&queries, "query", nil, "Query to count (repeat to compare several)")
+	cmd.Flags().StringVar(&granularity, "granularity", "day", "Granularity (minute|hour|day)")
+	cmd.Flags().StringArrayVar(&extraPairs, "param", nil, "Additional query parameter in key=value format, e.g. start_time=2024-01-01T00:00:00Z")
+	cmd.Flags().BoolVar(&fullArchive, "all", false, "Use the full-archive /2/tweets/counts/all endpoint, following pagination")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "With --all, stop after this many pages per query (0 fetches every page)")
+	cmd.Flags().IntVar(&average, "moving-average", 3, "Buckets in the trailing moving average (0 disables)")
+	cmd.Flags().IntVar(&baseline, "baseline", 24, "Earlier buckets a spike is measured against")
+	cmd.Flags().Float64Var(&threshold, "threshold", 3, "Z-score at which a bucket counts as a spike")
+	cmd.Flags().StringVar(&chart, "chart", chartNone, "Print a terminal chart: none|sparkline|bars")
+	cmd.Flags().IntVar(&width, "width", 40, "Longest bar of --chart bars, in characters")
+
+	return cmd
+}
+
+// writeCountSeries prints one row per bucket with a count column per query.
+func writeCountSeries(analysis countseries.Analysis) error {
+	columns := []string{"start", "end"}
+	for _, series := range analysis.Series {
+		columns = append(columns, series.Query)
+	}
+	if len(outputColumns) > 0 {
+		columns = outputColumns
+	}
+
+	rows := make([]any, len(analysis.Buckets))
+	for i, bucket := range analysis.Buckets {
+		row := map[string]any{
+			"start": bucket.Start.Format(time.RFC3339),
+			"end":   bucket.End.Format(time.RFC3339),
+		}
+		for _, series := range analysis.Series {
+			row[series.Query] = series.Counts[i]
+		}
+		rows[i] = row
+	}
+	return writeTabular(os.Stdout, outputFormat, rows, columns)
+}
+
+// validCountQueries rejects repeated queries, which would share a column.
+func validCountQueries(queries []string) error {
+	seen := make(map[string]bool, len(queries))
+	for _, query := range queries {
+		if strings.TrimSpace(query) == "" {
+			return errors.New("--query must not be empty")
+		}
+		if seen[query] {
+			return fmt.Errorf("--query %q is repeated", query)
+		}
+		seen[query] = true
+	}
+	return nil
+}
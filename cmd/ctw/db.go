@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newDBCommand())
+}
+
+// storeFlag is the --store option of the commands that collect tweets.
+type storeFlag struct {
+	path string
+}
+
+func addStoreFlag(cmd *cobra.Command) *storeFlag {
+	f := &storeFlag{}
+	cmd.Flags().StringVar(&f.path, "store", "", "Also upsert the tweets, users and media into this SQLite archive (see ctw db)")
+	return f
+}
+
+// open opens the archive, or returns nil when --store is not set.
+func (f *storeFlag) open(ctx context.Context) (*store.Store, error) {
+	if f == nil || strings.TrimSpace(f.path) == "" {
+		return nil, nil
+	}
+	return store.Open(ctx, f.path)
+}
+
+// save archives response as collected by source when --store is set.
+func (f *storeFlag) save(ctx context.Context, source string, response model.TweetsResponse) error {
+	s, err := f.open(ctx)
+	if err != nil || s == nil {
+		return err
+	}
+	defer s.Close()
+
+	result, err := s.SaveTweets(ctx, response, source)
+	if err != nil {
+		return err
+	}
+	logger.Debug("stored response", "store", f.path, "source", source, "tweets", result.Tweets, "users", result.Users, "media", result.Media)
+	return nil
+}
+
+func newDBCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Query and export a --store archive",
+		Long: `Query and export the SQLite archive written by --store.
+
+Commands that collect tweets (search recent, timelines, likes list, bookmarks
+list and watch) accept --store path.db. Every response is upserted, so running
+the same collection again refreshes metrics instead of duplicating rows.
+
+Tables:
+  tweets            one row per tweet with metrics and the full JSON in raw
+  users             authors and other expanded users
+  media             expanded photos, videos and GIFs
+  tweet_media       tweet_id, media_key, position
+  tweet_references  tweet_id, type (quoted|replied_to|retweeted), referenced_tweet_id
+  tweet_entities    tweet_id, type (hashtag|cashtag|mention|url), value
+  collected         tweet_id, source (e.g. "search recent: golang"), collected_at
+
+Timestamps are RFC 3339 text in UTC.`,
+	}
+
+	cmd.AddCommand(newDBQueryCommand())
+	cmd.AddCommand(newDBExportCommand())
+
+	return cmd
+}
+
+func newDBQueryCommand() *cobra.Command {
+	var storePath string
+
+	cmd := &cobra.Command{
+		Use:   "query SQL",
+		Short: "Run a read-only SQL query against the archive",
+		Long: `Run a read-only SQL query against the archive and print the rows in the
+selected --output format.
+
+Examples:
+  # Most liked tweets collected by a search
+  ctw db query --store tweets.db -o table \
+    "SELECT t.id, u.username, t.like_count, t.text FROM tweets t
+     JOIN users u ON u.id = t.author_id
+     JOIN collected c ON c.tweet_id = t.id AND c.source = 'search recent: golang'
+     ORDER BY t.like_count DESC LIMIT 20"
+
+  # Top hashtags
+  ctw db query --store tweets.db -o csv \
+    "SELECT value, count(*) AS tweets FROM tweet_entities
+     WHERE type = 'hashtag' GROUP BY value ORDER BY tweets DESC LIMIT 10"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(storePath) == "" {
+				return errors.New("--store is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			s, err := store.OpenReadOnly(ctx, storePath)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			rows, err := s.Query(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			columns, records, err := store.ScanRows(rows)
+			if err != nil {
+				return fmt.Errorf("db query: %w", err)
+			}
+
+			switch outputFormat {
+			case formatTable, formatCSV, formatTSV:
+				// Keep the column order of the SELECT.
+				if len(outputColumns) > 0 {
+					columns = outputColumns
+				}
+				rows := make([]any, len(records))
+				for i, record := range records {
+					rows[i] = record
+				}
+				return writeTabular(os.Stdout, outputFormat, rows, columns)
+			default:
+				if records == nil {
+					records = []map[string]any{}
+				}
+				return printOutput(records)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&storePath, "store", "", "Path to the SQLite archive")
+
+	return cmd
+}
+
+func newDBExportCommand() *cobra.Command {
+	var (
+		storePath string
+		table     string
+		query     string
+		format    string
+		outPath   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a table or query result as CSV or Parquet",
+		Long: `Export a whole table or the result of a SQL query as CSV or Parquet.
+
+The format defaults to the --out extension (.parquet or .csv), or CSV on
+stdout. Parquet columns are typed: counts are INT64, timestamps such as
+created_at are TIMESTAMP(MILLIS) and everything else is a UTF-8 string, so
+the files load directly into DuckDB, pandas or Spark.
+
+Examples:
+  ctw db export --store tweets.db --table tweets --out tweets.parquet
+  ctw db export --store tweets.db --sql "SELECT * FROM users WHERE followers_count > 1000" > users.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(storePath) == "" {
+				return errors.New("--store is required")
+			}
+			switch {
+			case table != "" && query != "":
+				return errors.New("use either --table or --sql, not both")
+			case table != "":
+				if !slices.Contains(store.Tables, table) {
+					return fmt.Errorf("unknown table %q (available: %s)", table, strings.Join(store.Tables, ", "))
+				}
+				query = "SELECT * FROM " + table
+			case strings.TrimSpace(query) == "":
+				return errors.New("--table or --sql is required")
+			}
+			if format == "" {
+				format = store.FormatCSV
+				if strings.EqualFold(filepath.Ext(outPath), ".parquet") {
+					format = store.FormatParquet
+				}
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			s, err := store.OpenReadOnly(ctx, storePath)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			var w io.Writer = os.Stdout
+			var file *os.File
+			if outPath != "" && outPath != "-" {
+				if file, err = os.Create(outPath); err != nil {
+					return fmt.Errorf("create %s: %w", outPath, err)
+				}
+				defer file.Close()
+				w = file
+			}
+
+			n, err := s.Export(ctx, w, format, query)
+			if err != nil {
+				return err
+			}
+			if file != nil {
+				if err := file.Close(); err != nil {
+					return fmt.Errorf("write %s: %w", outPath, err)
+				}
+				logger.Info("exported rows", "rows", n, "format", format, "file", outPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&storePath, "store", "", "Path to the SQLite archive")
+	cmd.Flags().StringVar(&table, "table", "", "Table to export: "+strings.Join(store.Tables, ", "))
+	cmd.Flags().StringVar(&query, "sql", "", "SQL query whose result to export")
+	cmd.Flags().StringVar(&format, "format", "", "Export format: csv or parquet (default: from --out extension, else csv)")
+	cmd.Flags().StringVar(&outPath, "out", "", "Output file (default: stdout)")
+
+	return cmd
+}
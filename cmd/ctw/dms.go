@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/0dayfall/ctw/internal/client"
 	"github.com/0dayfall/ctw/internal/dm"
+	"github.com/0dayfall/ctw/internal/dm/bot"
+	"github.com/0dayfall/ctw/internal/media"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +28,10 @@ func newDMsCommand() *cobra.Command {
 
 	cmd.AddCommand(newDMsSendCommand())
 	cmd.AddCommand(newDMsListCommand())
+	cmd.AddCommand(newDMsConversationCommand())
+	cmd.AddCommand(newDMsGroupCommand())
+	cmd.AddCommand(newDMsSyncCommand())
+	cmd.AddCommand(newDMsBotCommand())
 	cmd.AddCommand(newDMsDeleteCommand())
 
 	return cmd
@@ -31,8 +39,7 @@ func newDMsCommand() *cobra.Command {
 
 func newDMsSendCommand() *cobra.Command {
 	var (
-		text           string
-		filePath       string
+		message        *dmMessageFlags
 		participantID  string
 		conversationID string
 	)
@@ -47,23 +54,8 @@ func newDMsSendCommand() *cobra.Command {
 			if participantID != "" && conversationID != "" {
 				return errors.New("use either --user-id or --conversation-id, not both")
 			}
-			if text == "" && filePath == "" {
-				return errors.New("provide --text or --file")
-			}
-			if text != "" && filePath != "" {
-				return errors.New("use either --text or --file, not both")
-			}
-
-			if filePath != "" {
-				contents, err := os.ReadFile(filePath)
-				if err != nil {
-					return fmt.Errorf("read file: %w", err)
-				}
-				text = strings.TrimSpace(string(contents))
-			}
-
-			if strings.TrimSpace(text) == "" {
-				return errors.New("message text is empty")
+			if err := message.validate(); err != nil {
+				return err
 			}
 
 			ctx := cmd.Context()
@@ -75,10 +67,17 @@ func newDMsSendCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, false, &participantID); err != nil {
+				return err
+			}
+
+			req, err := message.request(ctx)
+			if err != nil {
+				return err
+			}
 
 			service := dm.NewService(c)
 
-			req := dm.SendDMRequest{Text: text}
 			var (
 				response   dm.SendDMResponse
 				rateLimits client.RateLimitSnapshot
@@ -93,7 +92,7 @@ func newDMsSendCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -101,14 +100,198 @@ func newDMsSendCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&participantID, "user-id", "", "Participant user ID for 1:1 messages")
+	cmd.Flags().StringVar(&participantID, "user-id", "", "Participant user ID or @handle for 1:1 messages")
 	cmd.Flags().StringVar(&conversationID, "conversation-id", "", "Existing conversation ID")
-	cmd.Flags().StringVar(&text, "text", "", "Direct message text")
-	cmd.Flags().StringVar(&filePath, "file", "", "Path to file containing DM text")
+	message = addDMMessageFlags(cmd)
 
 	return cmd
 }
 
+func newDMsGroupCommand() *cobra.Command {
+	var (
+		message        *dmMessageFlags
+		participantIDs []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "group",
+		Short: "Start a group conversation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(participantIDs) < 2 {
+				return errors.New("provide at least two --user-id values")
+			}
+			if err := message.validate(); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			refs := make([]*string, len(participantIDs))
+			for i := range participantIDs {
+				participantIDs[i] = strings.TrimSpace(participantIDs[i])
+				refs[i] = &participantIDs[i]
+			}
+			if err := resolveUserFlags(ctx, c, false, nil, refs...); err != nil {
+				return err
+			}
+
+			req, err := message.request(ctx)
+			if err != nil {
+				return err
+			}
+
+			service := dm.NewService(c)
+			response, rateLimits, err := service.CreateGroupConversation(ctx, participantIDs, req)
+			if err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&participantIDs, "user-id", nil, "Participant user IDs or @handles (repeatable or comma-separated)")
+	message = addDMMessageFlags(cmd)
+
+	return cmd
+}
+
+func newDMsConversationCommand() *cobra.Command {
+	var (
+		conversationID string
+		participantID  string
+		paramsFlag     []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "conversation",
+		Short: "List the events of one conversation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if conversationID == "" && participantID == "" {
+				return errors.New("provide --conversation-id or --user-id")
+			}
+			if conversationID != "" && participantID != "" {
+				return errors.New("use either --conversation-id or --user-id, not both")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return err
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+			if err := resolveUserFlags(ctx, c, false, &participantID); err != nil {
+				return err
+			}
+
+			service := dm.NewService(c)
+
+			var (
+				response   dm.DMEventsResponse
+				rateLimits client.RateLimitSnapshot
+			)
+			if participantID != "" {
+				response, rateLimits, err = service.ListEventsWithUser(ctx, participantID, queryParams)
+			} else {
+				response, rateLimits, err = service.ListConversationEvents(ctx, conversationID, queryParams)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&conversationID, "conversation-id", "", "Conversation ID, 1:1 or group")
+	cmd.Flags().StringVar(&participantID, "user-id", "", "Participant user ID or @handle of a 1:1 conversation")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+
+	return cmd
+}
+
+// dmMessageFlags holds the --text, --file, --media and --media-id flags that
+// make up a direct message.
+type dmMessageFlags struct {
+	text      string
+	filePath  string
+	mediaPath string
+	mediaID   string
+}
+
+func addDMMessageFlags(cmd *cobra.Command) *dmMessageFlags {
+	m := &dmMessageFlags{}
+	cmd.Flags().StringVar(&m.text, "text", "", "Direct message text")
+	cmd.Flags().StringVar(&m.filePath, "file", "", "Path to file containing DM text")
+	cmd.Flags().StringVar(&m.mediaPath, "media", "", "Image, GIF or video to upload and attach")
+	cmd.Flags().StringVar(&m.mediaID, "media-id", "", "ID of media already uploaded with a dm_* category")
+	cmd.MarkFlagsMutuallyExclusive("text", "file")
+	cmd.MarkFlagsMutuallyExclusive("media", "media-id")
+	return m
+}
+
+func (m *dmMessageFlags) validate() error {
+	if m.text == "" && m.filePath == "" && m.mediaPath == "" && m.mediaID == "" {
+		return errors.New("provide --text, --file, --media or --media-id")
+	}
+	return nil
+}
+
+// request reads the message text and uploads --media, in the dm_image,
+// dm_gif or dm_video category its extension implies.
+func (m *dmMessageFlags) request(ctx context.Context) (dm.SendDMRequest, error) {
+	req := dm.SendDMRequest{Text: m.text}
+	if m.filePath != "" {
+		contents, err := os.ReadFile(m.filePath)
+		if err != nil {
+			return dm.SendDMRequest{}, fmt.Errorf("read file: %w", err)
+		}
+		req.Text = strings.TrimSpace(string(contents))
+	}
+
+	mediaID := m.mediaID
+	if m.mediaPath != "" {
+		service := media.NewService(resolvedSettings.BearerToken)
+		id, err := service.UploadFile(ctx, m.mediaPath, media.DMCategory(m.mediaPath))
+		if err != nil {
+			return dm.SendDMRequest{}, fmt.Errorf("upload failed: %w", err)
+		}
+		mediaID = id
+	}
+	if mediaID != "" {
+		req.Attachments = []dm.Attachment{{MediaID: mediaID}}
+	}
+
+	if strings.TrimSpace(req.Text) == "" && len(req.Attachments) == 0 {
+		return dm.SendDMRequest{}, errors.New("message text is empty")
+	}
+	return req, nil
+}
+
 func newDMsListCommand() *cobra.Command {
 	var paramsFlag []string
 
@@ -137,7 +320,7 @@ func newDMsListCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -150,6 +333,135 @@ func newDMsListCommand() *cobra.Command {
 	return cmd
 }
 
+func newDMsSyncCommand() *cobra.Command {
+	var (
+		dir       string
+		maxPages  int
+		skipMedia bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Export direct messages to a local directory, fetching only new events",
+		Long: `Export the account's direct messages to a directory.
+
+Events are appended to conversations/<conversation id>.ndjson with their sender,
+media and referenced tweets resolved, and attachments are saved under media/.
+state.json remembers the newest event, so later runs fetch only what is new.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(dir) == "" {
+				return errors.New("--dir is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := dm.NewService(c)
+			result, rateLimits, err := service.SyncInbox(ctx, dir, dm.SyncOptions{MaxPages: maxPages, SkipMedia: skipMedia})
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+			for _, mediaErr := range result.MediaErrors {
+				logger.Warn("media not downloaded", "error", mediaErr)
+			}
+
+			if err := printOutput(result); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory holding the exported inbox")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop after this many pages of events (0 for no limit)")
+	cmd.Flags().BoolVar(&skipMedia, "no-media", false, "Do not download attachments")
+
+	return cmd
+}
+
+func newDMsBotCommand() *cobra.Command {
+	var (
+		rulesPath string
+		once      bool
+		dryRun    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bot",
+		Short: "Answer incoming direct messages from a rules file",
+		Long: `Poll for new direct messages and reply according to keyword and regex rules.
+
+Replies are either fixed text or the output of a command that receives the
+message on stdin. The rules file also sets the poll interval, per-user
+cooldowns and quiet hours; see bot.example.toml. Processed events are tracked
+in a state file next to the rules, and the first run only records the existing
+inbox so old messages are never answered.
+
+Examples:
+  # Try the rules without sending anything
+  ctw dms bot --rules bot.toml --dry-run --log-level debug
+
+  # Run continuously (e.g. as a systemd service)
+  ctw dms bot --rules bot.toml
+
+  # Poll once per invocation from cron
+  ctw dms bot --rules bot.toml --once`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(rulesPath) == "" {
+				return errors.New("--rules is required")
+			}
+
+			cfg, err := bot.LoadConfig(rulesPath)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+			selfID, err := c.UserID(ctx)
+			if err != nil {
+				return fmt.Errorf("resolve authenticated user: %w", err)
+			}
+
+			responder := bot.New(cfg, dm.NewService(c), selfID, logger)
+			responder.DryRun = dryRun
+
+			if once {
+				result, rateLimits, err := responder.Poll(ctx)
+				if err != nil {
+					return err
+				}
+				logger.Info("dm poll", "events", result.Events, "replies", result.Replies)
+				printRateLimits(rateLimits)
+				return nil
+			}
+
+			logger.Info("dm bot running; press Ctrl+C to stop", "rules", len(cfg.Rules), "interval", cfg.Interval.Std())
+			return responder.Run(ctx)
+		},
+	}
+
+	cmd.Flags().StringVar(&rulesPath, "rules", "", "Path to the bot rules file (TOML)")
+	cmd.Flags().BoolVar(&once, "once", false, "Poll once and exit")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Log the replies that would be sent without sending them or saving state")
+
+	return cmd
+}
+
 func newDMsDeleteCommand() *cobra.Command {
 	var eventID string
 
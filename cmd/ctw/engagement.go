@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/tweet/engagement"
+	"github.com/0dayfall/ctw/internal/tweet/lookup"
+	"github.com/spf13/cobra"
+)
+
+// tweetListFunc fetches one page of a list about a tweet, or with all every
+// page up to maxPages (0 for no limit).
+type tweetListFunc func(ctx context.Context, c *client.Client, tweetID string, params map[string]string, all bool, maxPages int) (any, client.RateLimitSnapshot, error)
+
+// newTweetListCommand builds the commands that list the retweeters, liking
+// users or quote tweets of a tweet.
+func newTweetListCommand(use, short, subject string, resource fields.Resource, list tweetListFunc) *cobra.Command {
+	var (
+		fieldOpts  *fieldFlags
+		tweetID    string
+		fetchAll   bool
+		maxPages   int
+		paramsFlag []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(tweetID) == "" {
+				return errors.New("--tweet-id is required")
+			}
+			id, err := lookup.ParseTweetID(tweetID)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return fmt.Errorf("parse params: %w", err)
+			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			response, rateLimits, err := list(ctx, c, id, queryParams, fetchAll, maxPages)
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID or URL of the tweet to list "+subject+" for")
+	cmd.Flags().BoolVar(&fetchAll, "all", false, "Follow pagination and return every page")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop --all after this many pages (0 for no limit)")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+
+	fieldOpts = addFieldFlags(cmd, resource)
+
+	return cmd
+}
+
+func newTweetsEngagementCommand() *cobra.Command {
+	var (
+		tweetID  string
+		maxPages int
+		top      int
+		text     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "engagement",
+		Short: "Summarize who retweeted, liked and quoted a tweet",
+		Long: `Collect a tweet's retweeters, liking users and quote tweets and summarize them.
+
+Each list is paged through to the end, or --max-pages pages. Users who
+engaged in several ways are counted once in "accounts", and the top accounts
+are ranked by follower count with the ways each one engaged.
+
+The liking users endpoint may only return likes of the authenticated user's
+own tweets, depending on the access level.
+
+Examples:
+  ctw tweets engagement --id https://x.com/golang/status/1234567890 --text
+  ctw tweets engagement --id 1234567890 --top 25 | jq -r '.top_accounts[].username'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tweetID == "" {
+				return errors.New("--id is required")
+			}
+			id, err := lookup.ParseTweetID(tweetID)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := engagement.NewService(c)
+			summary, rateLimits, err := service.Collect(ctx, id, engagement.Options{MaxPages: maxPages, Top: top})
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+
+			if text {
+				fmt.Fprint(os.Stdout, summary.Text())
+			} else if err := printOutput(summary); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tweetID, "id", "", "ID or URL of the tweet")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop each list after this many pages (0 fetches every page)")
+	cmd.Flags().IntVar(&top, "top", engagement.DefaultTop, "Number of top accounts to show")
+	cmd.Flags().BoolVar(&text, "text", false, "Print the summary as text instead of JSON")
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0dayfall/ctw/internal/client"
+)
+
+// Exit codes returned by ctw. They are part of the scripting contract and are
+// documented in README.md; do not renumber existing values.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitAuth        = 3
+	exitForbidden   = 4
+	exitNotFound    = 5
+	exitRateLimited = 6
+	exitAPIError    = 7
+	exitInterrupted = 130
+)
+
+// exitCodeFor maps an error returned by a command to the process exit code.
+func exitCodeFor(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case client.IsAuth(err):
+		return exitAuth
+	case client.IsForbiddenTier(err):
+		return exitForbidden
+	case client.IsRateLimited(err):
+		return exitRateLimited
+	case client.IsNotFound(err):
+		return exitNotFound
+	}
+
+	if isAPIError(err) {
+		return exitAPIError
+	}
+	return exitError
+}
+
+// isAPIError reports whether err carries an API error, wrapped as a value or
+// as a pointer.
+func isAPIError(err error) bool {
+	_, ok := client.AsAPIError(err)
+	return ok
+}
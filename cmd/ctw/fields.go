@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/spf13/cobra"
+)
+
+// fieldFlags holds the --preset, --tweet-fields, --user-fields,
+// --media-fields and --expansions flags shared by commands that return
+// tweets or users.
+type fieldFlags struct {
+	resource   fields.Resource
+	preset     string
+	tweet      []string
+	user       []string
+	media      []string
+	expansions []string
+}
+
+func addFieldFlags(cmd *cobra.Command, resource fields.Resource) *fieldFlags {
+	f := &fieldFlags{resource: resource}
+	presets := strings.Join(fields.Presets, "|")
+	cmd.Flags().StringVar(&f.preset, "preset", "", "Field preset to request ("+presets+")")
+	cmd.Flags().StringSliceVar(&f.tweet, "tweet-fields", nil, "Comma-separated tweet.fields; preset names expand in place")
+	cmd.Flags().StringSliceVar(&f.user, "user-fields", nil, "Comma-separated user.fields; preset names expand in place")
+	if resource == fields.Tweets {
+		cmd.Flags().StringSliceVar(&f.media, "media-fields", nil, "Comma-separated media.fields; preset names expand in place")
+	}
+	cmd.Flags().StringSliceVar(&f.expansions, "expansions", nil, "Comma-separated expansions; preset names expand in place")
+	return f
+}
+
+// apply validates the selection and adds it to params. Values already present
+// in params, typically from --param, take precedence.
+func (f *fieldFlags) apply(params map[string]string) error {
+	var set fields.Set
+	if f.preset != "" {
+		preset, err := fields.Preset(f.resource, f.preset)
+		if err != nil {
+			return err
+		}
+		set = preset
+	}
+
+	var explicit fields.Set
+	var err error
+	if explicit.Tweet, err = fields.Parse(f.resource, fields.TweetParam, f.tweet); err != nil {
+		return err
+	}
+	if explicit.User, err = fields.Parse(f.resource, fields.UserParam, f.user); err != nil {
+		return err
+	}
+	if explicit.Media, err = fields.Parse(f.resource, fields.MediaParam, f.media); err != nil {
+		return err
+	}
+	if explicit.Expansions, err = fields.Parse(f.resource, fields.ExpansionParam, f.expansions); err != nil {
+		return err
+	}
+
+	set = set.Merge(explicit)
+	if err := set.Validate(f.resource); err != nil {
+		return err
+	}
+	for key, value := range set.Params() {
+		if _, ok := params[key]; !ok {
+			params[key] = value
+		}
+	}
+	return nil
+}
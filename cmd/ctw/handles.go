@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/handles"
+	lookupsvc "github.com/0dayfall/ctw/internal/users/lookup"
+	"github.com/spf13/cobra"
+)
+
+// addMeFlag registers --me, which fills the acting-user flag idFlag with the
+// authenticated user's id.
+func addMeFlag(cmd *cobra.Command, idFlag string) *bool {
+	var me bool
+	cmd.Flags().BoolVar(&me, "me", false, "Use the authenticated user instead of --"+idFlag)
+	cmd.MarkFlagsMutuallyExclusive("me", idFlag)
+	return &me
+}
+
+// resolveUserFlags prepares user-id flag values for a request. With me set,
+// self becomes the authenticated user's id, cached per token. Every @handle
+// in self and others is then replaced by its id using one batched, cached
+// lookup.
+func resolveUserFlags(ctx context.Context, c *client.Client, me bool, self *string, others ...*string) error {
+	if me {
+		id, err := c.UserID(ctx)
+		if err != nil {
+			return fmt.Errorf("resolve --me: %w", err)
+		}
+		*self = id
+	}
+
+	refs := append([]*string{self}, others...)
+	var usernames []string
+	for _, ref := range refs {
+		if ref != nil && handles.IsHandle(*ref) {
+			usernames = append(usernames, *ref)
+		}
+	}
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	ids, err := resolveHandles(ctx, c, usernames)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref == nil || !handles.IsHandle(*ref) {
+			continue
+		}
+		id, ok := ids[handles.Normalize(*ref)]
+		if !ok {
+			return fmt.Errorf("unknown user %s", strings.TrimSpace(*ref))
+		}
+		*ref = id
+	}
+	return nil
+}
+
+// resolveHandles looks usernames up through the handle cache and returns the
+// ids found, keyed by handles.Normalize. Unknown or suspended accounts are
+// absent.
+func resolveHandles(ctx context.Context, c *client.Client, usernames []string) (map[string]string, error) {
+	cache, err := openHandleCache()
+	if err != nil {
+		logger.Warn("handle cache unavailable", "error", err)
+	}
+
+	resolver := handles.NewResolver(lookupsvc.NewService(c), cache)
+	ids, rateLimits, err := resolver.Resolve(ctx, usernames)
+	if err != nil {
+		printRateLimits(rateLimits)
+		return nil, err
+	}
+	if err := cache.Save(); err != nil {
+		logger.Warn("handle cache not saved", "error", err)
+	}
+	return ids, nil
+}
+
+// openHandleCache opens the handle cache in the cache directory. It returns
+// nil, which caches nothing, when handle_ttl is 0.
+func openHandleCache() (*handles.Cache, error) {
+	if resolvedSettings.HandleTTL <= 0 {
+		return nil, nil
+	}
+	if resolvedSettings.CacheDir == "" {
+		return nil, errors.New("no cache directory")
+	}
+	return handles.OpenCache(filepath.Join(resolvedSettings.CacheDir, handles.CacheFile), resolvedSettings.HandleTTL)
+}
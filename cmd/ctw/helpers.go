@@ -1,15 +1,115 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"text/template"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/columnar"
+	"github.com/0dayfall/ctw/internal/model"
+	stream "github.com/0dayfall/ctw/internal/tweet/filteredstream"
 )
 
+const (
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatTable    = "table"
+	formatCSV      = "csv"
+	formatTSV      = "tsv"
+	formatTemplate = "template"
+	formatParquet  = "parquet"
+)
+
+var outputFormats = []string{formatJSON, formatNDJSON, formatTable, formatCSV, formatTSV, formatTemplate, formatParquet}
+
+// tableCellWidth caps cells in --output table so long tweet text does not
+// wrap the terminal. CSV and TSV always carry the full value.
+const tableCellWidth = 80
+
+// resourceColumns picks default columns for tabular output. The first entry
+// whose marker field appears in the first row wins, so more specific shapes
+// (DM events also carry "text") are listed before generic ones.
+var resourceColumns = []struct {
+	name    string
+	marker  string
+	columns []string
+}{
+	{name: "counts", marker: "tweet_count", columns: []string{"start", "end", "tweet_count"}},
+	{name: "dms", marker: "event_type", columns: []string{"id", "created_at", "sender_id", "event_type", "text"}},
+	{name: "users", marker: "username", columns: []string{"id", "username", "name"}},
+	{name: "rules", marker: "value", columns: []string{"id", "value", "tag"}},
+	{name: "tweets", marker: "text", columns: []string{"id", "created_at", "author_id", "text"}},
+}
+
+// printOutput renders an API response in the format selected by --output.
+// Non-JSON formats operate on the response's data rows: the elements of a
+// Data slice, a lone Data object, or the value itself when it has no Data.
+func printOutput(v any) error {
+	switch outputFormat {
+	case "", formatJSON:
+		return printJSON(v)
+	case formatNDJSON:
+		return writeNDJSON(os.Stdout, dataRows(v))
+	case formatTable, formatCSV, formatTSV:
+		return writeTabular(os.Stdout, outputFormat, dataRows(v), outputColumns)
+	case formatTemplate:
+		return writeTemplate(os.Stdout, outputTemplate, dataRows(v))
+	case formatParquet:
+		return writeParquet(os.Stdout, v)
+	default:
+		return fmt.Errorf("unsupported output format %q", outputFormat)
+	}
+}
+
+// writeParquet writes tweet results with the columnar schema. Other
+// resources have no fixed schema and are rejected.
+func writeParquet(w io.Writer, v any) error {
+	var rows []columnar.Tweet
+	switch response := v.(type) {
+	case model.TweetsResponse:
+		rows = columnar.FromResponse(response)
+	case *model.TweetsResponse:
+		rows = columnar.FromResponse(*response)
+	case stream.StreamEnvelope:
+		rows = columnar.FromResponse(model.TweetsResponse{Data: response.Data, Includes: response.Includes})
+		for i := range rows {
+			rows[i].MatchingRules = matchingRuleNames(response.MatchingRules)
+		}
+	default:
+		return errors.New("parquet output supports tweet results only")
+	}
+
+	writer := columnar.NewWriter(w)
+	if err := writer.Write(rows...); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// matchingRuleNames identifies rules by tag, or by ID when they have none.
+func matchingRuleNames(rules []stream.MatchingRule) []string {
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Tag != "" {
+			names = append(names, rule.Tag)
+		} else {
+			names = append(names, rule.ID)
+		}
+	}
+	return names
+}
+
 func printJSON(v any) error {
 	var (
 		data []byte
@@ -28,11 +128,265 @@ func printJSON(v any) error {
 	return err
 }
 
+// dataRows extracts the records a response carries. Rows keep their Go types
+// so templates can address fields as {{.ID}}.
+func dataRows(v any) []any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Struct {
+		if field := rv.FieldByName("Data"); field.IsValid() {
+			rv = field
+			for rv.Kind() == reflect.Pointer {
+				if rv.IsNil() {
+					return nil
+				}
+				rv = rv.Elem()
+			}
+		}
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		rows := make([]any, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, rv.Index(i).Interface())
+		}
+		return rows
+	}
+	return []any{rv.Interface()}
+}
+
+func writeNDJSON(w io.Writer, rows []any) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTemplate(w io.Writer, text string, rows []any) error {
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parse --template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, row := range rows {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, row); err != nil {
+			return fmt.Errorf("execute --template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTabular(w io.Writer, format string, rows []any, columns []string) error {
+	records := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		record, err := toRecord(row)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+	if len(columns) == 0 {
+		columns = defaultColumns(records)
+	}
+
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = strings.ToUpper(column)
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, record := range records {
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = truncateCell(flattenCell(formatCell(lookupPath(record, column))), tableCellWidth)
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	case formatTSV:
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+		for _, record := range records {
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = flattenCell(formatCell(lookupPath(record, column)))
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		for _, record := range records {
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = formatCell(lookupPath(record, column))
+			}
+			if err := cw.Write(cells); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+// toRecord converts a row to its JSON shape so columns use the same names the
+// API and --output json use. Scalar rows become a single "value" column.
+func toRecord(row any) (map[string]any, error) {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	if record, ok := generic.(map[string]any); ok {
+		return record, nil
+	}
+	return map[string]any{"value": generic}, nil
+}
+
+func defaultColumns(records []map[string]any) []string {
+	if len(records) == 0 {
+		return []string{"id"}
+	}
+	first := records[0]
+	for _, resource := range resourceColumns {
+		if _, ok := first[resource.marker]; ok {
+			return resource.columns
+		}
+	}
+
+	columns := make([]string, 0, len(first))
+	for key, value := range first {
+		switch value.(type) {
+		case map[string]any, []any:
+			continue
+		}
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// lookupPath resolves dotted column names such as public_metrics.like_count.
+func lookupPath(record map[string]any, path string) any {
+	// Keys that contain dots themselves, such as query strings, match first.
+	if value, ok := record[path]; ok {
+		return value
+	}
+	var current any = record
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+	return current
+}
+
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		// Models without omitempty on time fields serialise unset
+		// timestamps as the zero time; show them as empty cells.
+		if v == "0001-01-01T00:00:00Z" {
+			return ""
+		}
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func flattenCell(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func truncateCell(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// validateOutputSettings normalises the output format and checks that the
+// options it depends on are present and none it would ignore are set.
+func validateOutputSettings(settings *Settings) error {
+	settings.OutputFormat = strings.ToLower(strings.TrimSpace(settings.OutputFormat))
+	if settings.OutputFormat == "" {
+		settings.OutputFormat = formatJSON
+	}
+	valid := false
+	for _, format := range outputFormats {
+		if settings.OutputFormat == format {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid output format %q (expected %s)", settings.OutputFormat, strings.Join(outputFormats, "|"))
+	}
+	if settings.OutputFormat == formatTemplate && strings.TrimSpace(settings.OutputTemplate) == "" {
+		return errors.New("--output template requires --template")
+	}
+	if settings.OutputFormat != formatTemplate && settings.OutputTemplate != "" {
+		return fmt.Errorf("--template cannot be used with --output %s", settings.OutputFormat)
+	}
+	return nil
+}
+
 func printRateLimits(snapshot client.RateLimitSnapshot) {
 	if snapshot.Limit < 0 && snapshot.Remaining < 0 && snapshot.Reset < 0 {
 		return
 	}
-	fmt.Fprintf(os.Stderr, "rate-limit limit=%d remaining=%d reset=%d\n", snapshot.Limit, snapshot.Remaining, snapshot.Reset)
+	logger.Info("rate-limit", "limit", snapshot.Limit, "remaining", snapshot.Remaining, "reset", snapshot.Reset)
 }
 
 func parseKeyValuePairs(pairs []string) (map[string]string, error) {
@@ -51,3 +405,57 @@ func parseKeyValuePairs(pairs []string) (map[string]string, error) {
 	}
 	return values, nil
 }
+
+// readLinesFile returns the non-empty lines of path, or of stdin when path is
+// "-". Lines starting with # are comments, and a leading @ is dropped so
+// handle lists can be pasted as-is.
+func readLinesFile(path string) ([]string, error) {
+	lines, err := readRawLines(path)
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "@")
+	}
+	return lines, nil
+}
+
+// readRawLines is readLinesFile without the @ stripping, for inputs where a
+// leading @ marks a handle rather than decoration.
+func readRawLines(path string) ([]string, error) {
+	return scanLines(path, func(line string) bool { return strings.HasPrefix(line, "#") })
+}
+
+// readKeywordLines reads a keyword list. Since #hashtags are keywords, only
+// a lone # or one followed by a space starts a comment.
+func readKeywordLines(path string) ([]string, error) {
+	return scanLines(path, func(line string) bool { return line == "#" || strings.HasPrefix(line, "# ") })
+}
+
+// scanLines returns the trimmed lines of path, or of stdin when path is "-",
+// without blank lines and comments.
+func scanLines(path string, comment func(line string) bool) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || comment(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return lines, nil
+}
@@ -77,7 +77,7 @@ func newInitCommand() *cobra.Command {
 				source = "unknown"
 			}
 			fmt.Printf("✔ Bearer token: found (from %s)\n", source)
-			fmt.Printf("✔ Auth: OK (user: @%s, id: %s)\n", user.UserName, user.ID)
+			fmt.Printf("✔ Auth: OK (user: @%s, id: %s)\n", user.Data.Username, user.Data.ID)
 			fmt.Printf("✔ API access: OK\n")
 
 			if writeConfig {
@@ -177,6 +177,21 @@ func writeConfigFile(token, source string, allowPlaintext, force bool) error {
 		userAgent = "CERN-LineMode/2.15 libwww/2.17b3"
 	}
 
+	// A template needs its --template text, which the config does not store.
+	configFormat := resolvedSettings.OutputFormat
+	if configFormat == "" || configFormat == formatTemplate {
+		configFormat = formatJSON
+	}
+
+	logLevel := strings.TrimSpace(resolvedSettings.LogLevel)
+	if logLevel == "" {
+		logLevel = "info"
+	}
+	logFormat := strings.TrimSpace(resolvedSettings.LogFormat)
+	if logFormat == "" {
+		logFormat = "text"
+	}
+
 	content := fmt.Sprintf(`[auth]
 bearer_token = "%s"
 
@@ -185,12 +200,23 @@ user_agent = "%s"
 timeout = "%s"
 retry = %d
 
+[api]
+tier = "%s"   # basic|pro|enterprise, checked by ctw query lint
+
 [output]
 pretty = %t
+format = "%s"
 
 [stream]
 backoff_max = "%s"
-`, tokenValue, userAgent, resolvedSettings.Timeout, resolvedSettings.Retry, resolvedSettings.PrettyOutput, resolvedSettings.StreamBackoffMax)
+
+[log]
+level = "%s"
+format = "%s"
+
+[cache]
+handle_ttl = "%s"
+`, tokenValue, userAgent, resolvedSettings.Timeout, resolvedSettings.Retry, resolvedSettings.Tier, resolvedSettings.PrettyOutput, configFormat, resolvedSettings.StreamBackoffMax, logLevel, logFormat, resolvedSettings.HandleTTL)
 
 	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
 		return err
@@ -6,13 +6,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/columnar"
+	"github.com/parquet-go/parquet-go"
 )
 
 var ctwBinPath string
@@ -131,8 +138,8 @@ func TestSearchRecentGzipError(t *testing.T) {
 		t.Fatalf("expected error, got success")
 	}
 	var exitErr *exec.ExitError
-	if !errors.As(err, &exitErr) || exitErr.ExitCode() == 0 {
-		t.Fatalf("expected non-zero exit, got: %v", err)
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != exitAuth {
+		t.Fatalf("expected exit code %d, got: %v", exitAuth, err)
 	}
 	if !strings.Contains(stderr, "twitter api error: status 401") {
 		t.Fatalf("unexpected stderr output: %s", stderr)
@@ -192,6 +199,144 @@ func TestSearchRecentPipeJQ(t *testing.T) {
 	drainErrors(t, errCh)
 }
 
+func TestSearchRecentOutputFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := `{"data":[{"id":"1","text":"hello, world","author_id":"9"},{"id":"2","text":"bye"}],"meta":{"result_count":2}}`
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "csv",
+			args: []string{"-o", "csv"},
+			want: "id,created_at,author_id,text\n1,,9,\"hello, world\"\n2,,,bye\n",
+		},
+		{
+			name: "columns",
+			args: []string{"--output", "tsv", "--columns", "text,id"},
+			want: "text\tid\nhello, world\t1\nbye\t2\n",
+		},
+		{
+			name: "ndjson",
+			args: []string{"-o", "ndjson"},
+			want: "",
+		},
+		{
+			name: "template",
+			args: []string{"--template", "{{.ID}} {{.Text}}"},
+			want: "1 hello, world\n2 bye\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			args := append([]string{
+				"--base-url", server.URL,
+				"--bearer-token", "test-token",
+				"search", "recent",
+				"--query", "golang",
+			}, tc.args...)
+			stdout, stderr, err := runCTW(t, args...)
+			if err != nil {
+				t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+			}
+			if tc.name == "ndjson" {
+				lines := strings.Split(strings.TrimSpace(stdout), "\n")
+				if len(lines) != 2 || !strings.Contains(lines[0], `"id":"1"`) || !strings.Contains(lines[1], `"id":"2"`) {
+					t.Fatalf("unexpected ndjson output: %q", stdout)
+				}
+				return
+			}
+			if stdout != tc.want {
+				t.Fatalf("unexpected output:\n got: %q\nwant: %q", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutputFormatRejectsUnknown(t *testing.T) {
+	_, stderr, err := runCTW(t, "--bearer-token", "test-token", "search", "recent", "--query", "golang", "-o", "xml")
+	if err == nil {
+		t.Fatalf("expected error for unknown output format")
+	}
+	if !strings.Contains(stderr, `invalid output format "xml"`) {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestOutputTemplateRejectsOtherFormat(t *testing.T) {
+	_, stderr, err := runCTW(t, "--bearer-token", "test-token", "search", "recent", "--query", "golang", "-o", "json", "--template", "{{.id}}")
+	if err == nil {
+		t.Fatalf("expected error for --template with --output json")
+	}
+	if !strings.Contains(stderr, "--template cannot be used with --output json") {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestSearchRecentFieldFlags(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if got := query.Get("tweet.fields"); got != "author_id,created_at,public_metrics" {
+			recordError(errCh, fmt.Errorf("unexpected tweet.fields: %q", got))
+		}
+		if got := query.Get("expansions"); got != "author_id" {
+			recordError(errCh, fmt.Errorf("unexpected expansions: %q", got))
+		}
+		if got := query.Get("user.fields"); got != "verified" {
+			recordError(errCh, fmt.Errorf("--param should override user.fields, got %q", got))
+		}
+
+		payload := `{"data":[{"id":"1","text":"hello","public_metrics":{"like_count":7}}],"includes":{"users":[{"id":"9","username":"gopher"}]},"meta":{"result_count":1}}`
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"search", "recent",
+		"--query", "golang",
+		"--tweet-fields", "minimal,public_metrics",
+		"--expansions", "author_id",
+		"--user-fields", "username",
+		"--param", "user.fields=verified",
+		"-o", "csv", "--columns", "id,public_metrics.like_count",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if stdout != "id,public_metrics.like_count\n1,7\n" {
+		t.Fatalf("unexpected output: %q", stdout)
+	}
+
+	drainErrors(t, errCh)
+
+	_, stderr, err = runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"search", "recent",
+		"--query", "golang",
+		"--tweet-fields", "likes",
+	)
+	if err == nil {
+		t.Fatalf("expected validation error for unknown field")
+	}
+	if !strings.Contains(stderr, `unknown tweet.fields value "likes"`) {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+}
+
 func TestUsersLookupGzipSuccess(t *testing.T) {
 	errCh := make(chan error, 4)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -228,14 +373,16 @@ func TestUsersLookupGzipSuccess(t *testing.T) {
 		t.Fatalf("expected no stderr output, got: %s", stderr)
 	}
 
-	var payload []struct {
-		ID       string `json:"id"`
-		Username string `json:"username"`
+	var payload struct {
+		Data []struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"data"`
 	}
 	if err := json.Unmarshal([]byte(stdout), &payload); err != nil {
 		t.Fatalf("failed to decode stdout JSON: %v\nstdout: %s", err, stdout)
 	}
-	if len(payload) != 1 || payload[0].ID != "42" || payload[0].Username != "twitter" {
+	if len(payload.Data) != 1 || payload.Data[0].ID != "42" || payload.Data[0].Username != "twitter" {
 		t.Fatalf("unexpected response payload: %+v", payload)
 	}
 
@@ -347,77 +494,1360 @@ func TestTimelinesUserGzipSuccess(t *testing.T) {
 	drainErrors(t, errCh)
 }
 
-func runCTW(t *testing.T, args ...string) (string, string, error) {
-	t.Helper()
-	cmd := exec.Command(ctwBinPath, args...)
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	cmd.Env = append(os.Environ(), "BEARER_TOKEN=")
-	err := cmd.Run()
-	return stdout.String(), stderr.String(), err
-}
+func TestListsMembersAddUsernamesFile(t *testing.T) {
+	errCh := make(chan error, 4)
+	var added []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/by":
+			if got := r.URL.Query().Get("usernames"); got != "gopher,rustacean,ghost" {
+				recordError(errCh, fmt.Errorf("unexpected usernames query: %q", got))
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","username":"Gopher","name":"Go"},{"id":"2","username":"rustacean","name":"Rust"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2/lists/99/members":
+			var body struct {
+				UserID string `json:"user_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				recordError(errCh, err)
+			}
+			added = append(added, body.UserID)
+			_, _ = w.Write([]byte(`{"data":{"is_member":true}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
 
-func runJQ(t *testing.T, input string, args ...string) (string, string, error) {
-	t.Helper()
-	cmd := exec.Command("jq", args...)
-	cmd.Stdin = strings.NewReader(input)
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	err := cmd.Run()
-	return stdout.String(), stderr.String(), err
+	usernamesFile := filepath.Join(t.TempDir(), "members.txt")
+	if err := os.WriteFile(usernamesFile, []byte("# monitored accounts\n@gopher\n\nrustacean\nghost\n"), 0o600); err != nil {
+		t.Fatalf("write usernames file: %v", err)
+	}
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"lists", "members", "add",
+		"--id", "99",
+		"--usernames-file", usernamesFile,
+		"-o", "ndjson",
+	)
+	if err == nil {
+		t.Fatalf("expected failure for unresolved username")
+	}
+	if !strings.Contains(stderr, "1 of 3 users failed") {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+
+	want := `{"user_id":"1","username":"gopher","is_member":true}
+{"user_id":"2","username":"rustacean","is_member":true}
+{"username":"ghost","is_member":false,"error":"user not found"}
+`
+	if stdout != want {
+		t.Fatalf("unexpected output: %q", stdout)
+	}
+	if strings.Join(added, ",") != "1,2" {
+		t.Fatalf("unexpected members added: %v", added)
+	}
+
+	drainErrors(t, errCh)
 }
 
-func gzipPayload(t *testing.T, payload string) []byte {
-	t.Helper()
-	var buffer bytes.Buffer
-	gz := gzip.NewWriter(&buffer)
-	if _, err := gz.Write([]byte(payload)); err != nil {
-		t.Fatalf("failed to gzip payload: %v", err)
+func TestListsMembersAddAuthFailurePrintsResults(t *testing.T) {
+	errCh := make(chan error, 4)
+	var added []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2/lists/99/members":
+			var body struct {
+				UserID string `json:"user_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				recordError(errCh, err)
+			}
+			added = append(added, body.UserID)
+			if len(added) > 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"title":"Unauthorized","detail":"Unauthorized","type":"about:blank","status":401}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"is_member":true}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	idsFile := filepath.Join(t.TempDir(), "members.txt")
+	if err := os.WriteFile(idsFile, []byte("1\n2\n3\n"), 0o600); err != nil {
+		t.Fatalf("write ids file: %v", err)
 	}
-	if err := gz.Close(); err != nil {
-		t.Fatalf("failed to close gzip writer: %v", err)
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"lists", "members", "add",
+		"--id", "99",
+		"--ids-file", idsFile,
+		"-o", "csv", "--columns", "user_id,is_member,error",
+	)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != exitAuth {
+		t.Fatalf("expected exit code %d, got: %v\nstderr: %s", exitAuth, err, stderr)
 	}
-	return buffer.Bytes()
+	if !strings.HasPrefix(stdout, "user_id,is_member,error\n1,true,\n2,false,") {
+		t.Fatalf("unexpected output: %q", stdout)
+	}
+	if !strings.HasSuffix(stdout, "\n3,false,not attempted: unauthorized\n") {
+		t.Fatalf("expected the last member to be reported as not attempted: %q", stdout)
+	}
+	if strings.Join(added, ",") != "1,2" {
+		t.Fatalf("unexpected members added: %v", added)
+	}
+
+	drainErrors(t, errCh)
 }
 
-func recordError(errCh chan error, err error) {
-	if err == nil {
-		return
+func TestUsersMuteByUsernameAndListMuted(t *testing.T) {
+	errCh := make(chan error, 4)
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/by":
+			lookups++
+			if got := r.URL.Query().Get("usernames"); got != "spammer" {
+				recordError(errCh, fmt.Errorf("unexpected usernames query: %q", got))
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"66","username":"Spammer","name":"Spam"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2/users/1/muting":
+			body, _ := io.ReadAll(r.Body)
+			if strings.TrimSpace(string(body)) != `{"target_user_id":"66"}` {
+				recordError(errCh, fmt.Errorf("unexpected mute body: %s", body))
+			}
+			_, _ = w.Write([]byte(`{"data":{"muting":true}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/1/muting":
+			if r.URL.Query().Get("pagination_token") == "" {
+				_, _ = w.Write([]byte(`{"data":[{"id":"66","username":"spammer","name":"Spam"}],"meta":{"result_count":1,"next_token":"n1"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"67","username":"troll","name":"Troll"}],"meta":{"result_count":1}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cacheEnv := []string{"CTW_CACHE_DIR=" + t.TempDir()}
+	for range 2 {
+		stdout, stderr, err := runCTWEnv(t, cacheEnv,
+			"--base-url", server.URL,
+			"--bearer-token", "test-token",
+			"users", "mute",
+			"--source-id", "1",
+			"--username", "@spammer",
+		)
+		if err != nil {
+			t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stdout, `"muting": true`) {
+			t.Fatalf("unexpected mute output: %s", stdout)
+		}
 	}
-	select {
-	case errCh <- err:
-	default:
+	if lookups != 1 {
+		t.Fatalf("expected the second run to use the handle cache, got %d lookups", lookups)
+	}
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"users", "muted",
+		"--user-id", "1",
+		"--all",
+		"-o", "csv", "--columns", "id,username",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if stdout != "id,username\n66,spammer\n67,troll\n" {
+		t.Fatalf("unexpected muted output: %q", stdout)
 	}
+
+	drainErrors(t, errCh)
 }
 
-func drainErrors(t *testing.T, errCh chan error) {
-	t.Helper()
-	for {
-		select {
-		case err := <-errCh:
-			if err != nil {
-				t.Error(err)
+func TestUsersMuteUnknownUsernameFails(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/by":
+			_, _ = w.Write([]byte(`{"errors":[{"value":"nosuchuser","detail":"Could not find user with usernames: [nosuchuser].","title":"Not Found Error","resource_type":"user","parameter":"usernames","type":"https://api.twitter.com/2/problems/resource-not-found"}]}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, use := range []string{"mute", "unmute"} {
+		stdout, stderr, err := runCTW(t,
+			"--base-url", server.URL,
+			"--bearer-token", "test-token",
+			"users", use,
+			"--source-id", "1",
+			"--username", "nosuchuser",
+		)
+		if err == nil {
+			t.Fatalf("%s: expected an error for an unknown username, got output: %s", use, stdout)
+		}
+		if !strings.Contains(stderr, "user not found") {
+			t.Fatalf("%s: expected a user not found error, got stderr: %s", use, stderr)
+		}
+		if strings.Contains(stdout, "muting") {
+			t.Fatalf("%s: expected no result output, got: %s", use, stdout)
+		}
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestUsersMuteRateLimitedPrintsResults(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/by":
+			_, _ = w.Write([]byte(`{"data":[{"id":"66","username":"spammer","name":"Spam"},{"id":"67","username":"troll","name":"Troll"},{"id":"68","username":"bot","name":"Bot"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2/users/1/muting":
+			body, _ := io.ReadAll(r.Body)
+			switch strings.TrimSpace(string(body)) {
+			case `{"target_user_id":"66"}`:
+				_, _ = w.Write([]byte(`{"data":{"muting":true}}`))
+			case `{"target_user_id":"67"}`:
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"title":"Too Many Requests","detail":"Too Many Requests","type":"about:blank","status":429}`))
+			default:
+				recordError(errCh, fmt.Errorf("unexpected mute after the rate limit: %s", body))
 			}
 		default:
-			return
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
 		}
+	}))
+	defer server.Close()
+
+	usernamesFile := filepath.Join(t.TempDir(), "spam.txt")
+	if err := os.WriteFile(usernamesFile, []byte("spammer\ntroll\nbot\n"), 0o600); err != nil {
+		t.Fatalf("write usernames file: %v", err)
+	}
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"--retry", "0",
+		"users", "mute",
+		"--source-id", "1",
+		"--usernames-file", usernamesFile,
+		"-o", "ndjson",
+	)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != exitRateLimited {
+		t.Fatalf("expected exit code %d, got: %v\nstderr: %s", exitRateLimited, err, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected one result per user, got: %q", stdout)
+	}
+	if lines[0] != `{"user_id":"66","username":"spammer","muting":true}` {
+		t.Fatalf("unexpected result for the muted user: %s", lines[0])
 	}
+	if !strings.Contains(lines[1], `"user_id":"67"`) || !strings.Contains(lines[1], "429") {
+		t.Fatalf("unexpected result for the rate-limited user: %s", lines[1])
+	}
+	if lines[2] != `{"user_id":"68","username":"bot","muting":false,"error":"not attempted: rate limited"}` {
+		t.Fatalf("unexpected result for the skipped user: %s", lines[2])
+	}
+
+	drainErrors(t, errCh)
 }
 
-func findRepoRoot(start string) (string, error) {
-	dir := start
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
-			return dir, nil
+func TestLikesAddResolvesMeAndHandles(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/me":
+			_, _ = w.Write([]byte(`{"data":{"id":"7","username":"me","name":"Me"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/by":
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2/users/7/likes":
+			_, _ = w.Write([]byte(`{"data":{"liked":true}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
 		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return "", fmt.Errorf("go.mod not found from %s", start)
+	}))
+	defer server.Close()
+
+	_, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"likes", "add", "--me", "--tweet-id", "100",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	_, stderr, err = runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"likes", "add", "--user-id", "@nobody", "--tweet-id", "100",
+	)
+	if err == nil || !strings.Contains(stderr, "unknown user @nobody") {
+		t.Fatalf("expected unknown user error, got %v\nstderr: %s", err, stderr)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestMeIsCachedAcrossRuns(t *testing.T) {
+	errCh := make(chan error, 4)
+	meCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/users/me":
+			meCalls++
+			_, _ = w.Write([]byte(`{"data":{"id":"7","username":"me","name":"Me"}}`))
+		case "/2/users/7/bookmarks":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","text":"saved"}],"meta":{"result_count":1}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
 		}
-		dir = parent
+	}))
+	defer server.Close()
+
+	cacheEnv := []string{"CTW_CACHE_DIR=" + t.TempDir()}
+	stdout, stderr, err := runCTWEnv(t, cacheEnv,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"me", "-o", "csv", "--columns", "id,username",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
 	}
+	if stdout != "id,username\n7,me\n" {
+		t.Fatalf("unexpected me output: %q", stdout)
+	}
+
+	_, stderr, err = runCTWEnv(t, cacheEnv,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"bookmarks", "list",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if meCalls != 1 {
+		t.Fatalf("expected bookmarks list to reuse the cached user id, got %d /2/users/me calls", meCalls)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestBatchLikesAddResumesFromProgress(t *testing.T) {
+	errCh := make(chan error, 4)
+	var (
+		mu      sync.Mutex
+		calls   = map[string]int{}
+		failing = "101"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost || r.URL.Path != "/2/users/7/likes" {
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body struct {
+			TweetID string `json:"tweet_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			recordError(errCh, fmt.Errorf("decode body: %w", err))
+		}
+
+		mu.Lock()
+		calls[body.TweetID]++
+		fail := body.TweetID == failing
+		mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"title":"Invalid Request","detail":"tweet unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"liked":true}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	idsPath := filepath.Join(dir, "ids.txt")
+	if err := os.WriteFile(idsPath, []byte("100\n101\n# skipped\n102\n100\n"), 0o644); err != nil {
+		t.Fatalf("write ids: %v", err)
+	}
+	args := []string{
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"batch", "likes", "add", "--user-id", "7", "--from-file", idsPath,
+	}
+
+	stdout, stderr, err := runCTW(t, args...)
+	if err == nil || !strings.Contains(stderr, "1 of 3 items failed") {
+		t.Fatalf("expected one failed item, got %v\nstderr: %s", err, stderr)
+	}
+	results := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var result struct {
+			Item string `json:"item"`
+			OK   bool   `json:"ok"`
+		}
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", line, err)
+		}
+		results[result.Item] = result.OK
+	}
+	if len(results) != 3 || !results["100"] || results["101"] || !results["102"] {
+		t.Fatalf("unexpected results: %v\n%s", results, stdout)
+	}
+
+	mu.Lock()
+	failing = ""
+	mu.Unlock()
+
+	stdout, stderr, err = runCTW(t, args...)
+	if err != nil {
+		t.Fatalf("expected rerun to succeed, got %v\nstderr: %s", err, stderr)
+	}
+	if strings.Count(stdout, "\n") != 1 || !strings.Contains(stdout, `"item":"101"`) {
+		t.Fatalf("expected rerun to process only 101, got %q", stdout)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["100"] != 1 || calls["101"] != 2 || calls["102"] != 1 {
+		t.Fatalf("unexpected call counts: %v", calls)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestSearchRecentStoreAndDBQuery(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		likes = "3"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		payload := `{"data":[{"id":"1","text":"hello #golang","author_id":"9","public_metrics":{"like_count":` + likes + `}},{"id":"2","text":"bye","author_id":"9"}],` +
+			`"includes":{"users":[{"id":"9","username":"gopher","name":"Gopher"}]},"meta":{"result_count":2}}`
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	dbPath := filepath.Join(t.TempDir(), "tweets.db")
+	args := []string{
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"search", "recent", "--query", "golang", "--store", dbPath,
+	}
+	if _, stderr, err := runCTW(t, args...); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	mu.Lock()
+	likes = "7"
+	mu.Unlock()
+	if _, stderr, err := runCTW(t, args...); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	stdout, stderr, err := runCTW(t, "db", "query", "--store", dbPath, "-o", "csv",
+		"SELECT t.id, u.username, t.like_count FROM tweets t JOIN users u ON u.id = t.author_id ORDER BY t.id")
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if want := "id,username,like_count\n1,gopher,7\n2,gopher,\n"; stdout != want {
+		t.Fatalf("unexpected query output:\n got: %q\nwant: %q", stdout, want)
+	}
+
+	if _, _, err := runCTW(t, "db", "query", "--store", dbPath, "DELETE FROM tweets"); err == nil {
+		t.Fatal("expected db query to reject writes")
+	}
+
+	outPath := filepath.Join(t.TempDir(), "tweets.parquet")
+	if _, stderr, err := runCTW(t, "db", "export", "--store", dbPath, "--table", "tweets", "--out", outPath); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("PAR1")) {
+		t.Fatalf("expected a Parquet file, got %q", data[:min(len(data), 16)])
+	}
+}
+
+func TestDMsGroupAndConversationResolveHandles(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/by":
+			_, _ = w.Write([]byte(`{"data":[{"id":"2","username":"alice","name":"Alice"},{"id":"3","username":"bob","name":"Bob"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2/dm_conversations":
+			var body struct {
+				ConversationType string   `json:"conversation_type"`
+				ParticipantIDs   []string `json:"participant_ids"`
+				Message          struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				recordError(errCh, fmt.Errorf("decode body: %w", err))
+			}
+			if body.ConversationType != "Group" || strings.Join(body.ParticipantIDs, ",") != "2,3,4" || body.Message.Text != "kickoff" {
+				recordError(errCh, fmt.Errorf("unexpected group request: %+v", body))
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data":{"dm_conversation_id":"g1","dm_event_id":"e1"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/2/dm_conversations/with/2/dm_events":
+			_, _ = w.Write([]byte(`{"data":[{"id":"e2","event_type":"MessageCreate","text":"hi","sender_id":"2"}],"meta":{"result_count":1}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"dms", "group", "--user-id", "@alice,@bob,4", "--text", "kickoff",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"g1"`) {
+		t.Fatalf("unexpected group output: %s", stdout)
+	}
+
+	stdout, stderr, err = runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"dms", "conversation", "--user-id", "@alice", "-o", "csv", "--columns", "id,text",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if stdout != "id,text\ne2,hi\n" {
+		t.Fatalf("unexpected conversation output: %q", stdout)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestDMsBotRepliesOnlyToNewMessages(t *testing.T) {
+	errCh := make(chan error, 4)
+	var (
+		mu      sync.Mutex
+		events  = `{"id":"1","event_type":"MessageCreate","text":"refund?","dm_conversation_id":"5-7","sender_id":"5"}`
+		replies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/me":
+			_, _ = w.Write([]byte(`{"data":{"id":"7","username":"support","name":"Support"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/2/dm_events":
+			_, _ = w.Write([]byte(`{"data":[` + events + `]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2/dm_conversations/5-7/messages":
+			var body struct {
+				Text string `json:"text"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				recordError(errCh, fmt.Errorf("decode body: %w", err))
+			}
+			replies = append(replies, body.Text)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data":{"dm_event_id":"9"}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	rulesPath := filepath.Join(t.TempDir(), "bot.toml")
+	rules := "[[rule]]\nkeywords = [\"refund\"]\nreply = \"See /refunds\"\n"
+	if err := os.WriteFile(rulesPath, []byte(rules), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	args := []string{
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"dms", "bot", "--rules", rulesPath, "--once",
+	}
+
+	if _, stderr, err := runCTW(t, args...); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	mu.Lock()
+	events = `{"id":"3","event_type":"MessageCreate","text":"REFUND now","dm_conversation_id":"5-7","sender_id":"5"},` +
+		`{"id":"2","event_type":"MessageCreate","text":"refund sent","dm_conversation_id":"5-7","sender_id":"7"},` + events
+	mu.Unlock()
+
+	if _, stderr, err := runCTW(t, args...); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(replies) != 1 || replies[0] != "See /refunds" {
+		t.Fatalf("expected a single reply to the new message, got %q", replies)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestBotMentionsRepliesInThread(t *testing.T) {
+	errCh := make(chan error, 4)
+	var (
+		mu       sync.Mutex
+		mentions = `{"id":"10","text":"@support hi","author_id":"5","conversation_id":"10"}`
+		sinceIDs []string
+		replies  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/me":
+			_, _ = w.Write([]byte(`{"data":{"id":"7","username":"support","name":"Support"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/2/users/7/mentions":
+			sinceIDs = append(sinceIDs, r.URL.Query().Get("since_id"))
+			_, _ = w.Write([]byte(`{"data":[` + mentions + `],"includes":{"users":[{"id":"5","username":"alice","name":"Alice"}]}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2/tweets":
+			var body struct {
+				Text  string `json:"text"`
+				Reply struct {
+					InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+				} `json:"reply"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				recordError(errCh, fmt.Errorf("decode body: %w", err))
+			}
+			replies = append(replies, body.Reply.InReplyToTweetID+": "+body.Text)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data":{"id":"99","text":"` + body.Text + `"}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	args := []string{
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"bot", "mentions", "--reply-template", "Thanks @{{.Author.Username}}!",
+		"--state", filepath.Join(t.TempDir(), "mentions.json"), "--once",
+	}
+
+	if _, stderr, err := runCTW(t, args...); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	mu.Lock()
+	mentions = `{"id":"12","text":"@support again","author_id":"5","conversation_id":"10"},` +
+		`{"id":"11","text":"@support thanks","author_id":"7","conversation_id":"10"}`
+	mu.Unlock()
+
+	for range 2 {
+		if _, stderr, err := runCTW(t, args...); err != nil {
+			t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(replies) != 1 || replies[0] != "12: Thanks @alice!" {
+		t.Fatalf("expected a single threaded reply to the new mention, got %q", replies)
+	}
+	if want := []string{"", "10", "12"}; strings.Join(sinceIDs, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected since_id %q, got %q", want, sinceIDs)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func runCTW(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	return runCTWEnv(t, []string{"CTW_CACHE_DIR=" + t.TempDir()}, args...)
+}
+
+// runCTWEnv runs ctw with extra environment variables, such as a
+// CTW_CACHE_DIR shared between invocations.
+func runCTWEnv(t *testing.T, env []string, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := exec.Command(ctwBinPath, args...)
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(append(os.Environ(), "BEARER_TOKEN="), env...)
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func runJQ(t *testing.T, input string, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := exec.Command("jq", args...)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func gzipPayload(t *testing.T, payload string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	gz := gzip.NewWriter(&buffer)
+	if _, err := gz.Write([]byte(payload)); err != nil {
+		t.Fatalf("failed to gzip payload: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func recordError(errCh chan error, err error) {
+	if err == nil {
+		return
+	}
+	select {
+	case errCh <- err:
+	default:
+	}
+}
+
+func drainErrors(t *testing.T, errCh chan error) {
+	t.Helper()
+	for {
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Error(err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func findRepoRoot(start string) (string, error) {
+	dir := start
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod not found from %s", start)
+		}
+		dir = parent
+	}
+}
+
+func TestSearchRecentParquetOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := `{"data":[{"id":"1","text":"hello #golang","author_id":"9","created_at":"2024-05-01T13:00:00.000Z","entities":{"hashtags":[{"tag":"golang"}]}}],` +
+			`"includes":{"users":[{"id":"9","username":"gopher","name":"Gopher"}]},"meta":{"result_count":1}}`
+		if strings.HasPrefix(r.URL.Path, "/2/users") {
+			payload = `{"data":{"id":"9","username":"gopher","name":"Gopher"}}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"search", "recent", "--query", "golang", "-o", "parquet",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	file, err := parquet.OpenFile(strings.NewReader(stdout), int64(len(stdout)))
+	if err != nil {
+		t.Fatalf("expected a Parquet file: %v", err)
+	}
+	rows := make([]columnar.Tweet, file.NumRows())
+	reader := parquet.NewGenericReader[columnar.Tweet](file)
+	_, _ = reader.Read(rows)
+	if err := reader.Close(); err != nil {
+		t.Fatalf("read parquet: %v", err)
+	}
+	if len(rows) != 1 || rows[0].AuthorUsername != "gopher" || len(rows[0].Entities.Hashtags) != 1 {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+
+	_, stderr, err = runCTW(t, "--base-url", server.URL, "--bearer-token", "test-token", "users", "lookup", "--username", "gopher", "-o", "parquet")
+	if err == nil || !strings.Contains(stderr, "tweet results only") {
+		t.Fatalf("expected parquet output to reject non-tweet results, got %v\nstderr: %s", err, stderr)
+	}
+}
+
+func TestCountsAnalyzeAlignsQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := `{"data":[` +
+			`{"start":"2024-05-01T00:00:00.000Z","end":"2024-05-02T00:00:00.000Z","tweet_count":4},` +
+			`{"start":"2024-05-02T00:00:00.000Z","end":"2024-05-03T00:00:00.000Z","tweet_count":6}],"meta":{"total_tweet_count":10}}`
+		if r.URL.Query().Get("query") == "go.dev" {
+			payload = `{"data":[{"start":"2024-05-02T00:00:00.000Z","end":"2024-05-03T00:00:00.000Z","tweet_count":1}],"meta":{"total_tweet_count":1}}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	base := []string{"--base-url", server.URL, "--bearer-token", "test-token", "counts", "analyze", "--query", "golang", "--query", "go.dev"}
+	stdout, stderr, err := runCTW(t, append(base, "-o", "csv")...)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	want := "start,end,golang,go.dev\n" +
+		"2024-05-01T00:00:00Z,2024-05-02T00:00:00Z,4,0\n" +
+		"2024-05-02T00:00:00Z,2024-05-03T00:00:00Z,6,1\n"
+	if stdout != want {
+		t.Fatalf("unexpected csv:\n got: %q\nwant: %q", stdout, want)
+	}
+
+	stdout, stderr, err = runCTW(t, append(base, "--chart", "sparkline")...)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "golang  ▅█  total 10") || !strings.Contains(stdout, "go.dev  ▁▂  total 1") {
+		t.Fatalf("unexpected sparkline output:\n%s", stdout)
+	}
+}
+
+func TestAlertCountsNotifiesWebhookOnSpike(t *testing.T) {
+	errCh := make(chan error, 4)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2/tweets/counts/recent" || r.URL.Query().Get("granularity") != "minute" {
+			recordError(errCh, fmt.Errorf("unexpected request: %s", r.URL))
+		}
+		start, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_time"))
+		if err != nil {
+			recordError(errCh, fmt.Errorf("bad start_time: %v", err))
+			return
+		}
+		now := time.Now().UTC()
+		var data []string
+		for minute := start; minute.Before(now); minute = minute.Add(time.Minute) {
+			count := 10
+			if minute.After(now.Add(-3 * time.Minute)) {
+				count = 500
+			}
+			data = append(data, fmt.Sprintf(`{"start":%q,"end":%q,"tweet_count":%d}`,
+				minute.Format(time.RFC3339), minute.Add(time.Minute).Format(time.RFC3339), count))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+	defer api.Close()
+
+	alerts := make(chan string, 4)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		alerts <- string(body)
+	}))
+	defer hook.Close()
+
+	statePath := filepath.Join(t.TempDir(), "alert.json")
+	args := []string{
+		"--base-url", api.URL,
+		"--bearer-token", "test-token",
+		"alert", "counts", "--query", "acme", "--threshold", "3sigma",
+		"--webhook", hook.URL, "--state", statePath, "--once",
+	}
+	if _, stderr, err := runCTW(t, args...); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	select {
+	case body := <-alerts:
+		if !strings.Contains(body, `"status":"firing"`) || !strings.Contains(body, `"count":500`) {
+			t.Fatalf("unexpected alert: %s", body)
+		}
+	default:
+		t.Fatal("expected a firing alert")
+	}
+
+	// The state file remembers the firing alert, so the next poll is quiet.
+	if _, stderr, err := runCTW(t, args...); err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	select {
+	case body := <-alerts:
+		t.Fatalf("unexpected second alert: %s", body)
+	default:
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestQueryLintAndSearchRejectsInvalidQuery(t *testing.T) {
+	stdout, stderr, err := runCTW(t, "query", "lint", "--print", "normalized", "(golang  OR #go)   LANG:EN")
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if stdout != "(golang OR #go) lang:en\n" {
+		t.Fatalf("unexpected normalized query: %q", stdout)
+	}
+
+	_, stderr, err = runCTWEnv(t, []string{"CTW_TIER=basic"}, "query", "lint", "--endpoint", "stream", "gopher bio:developer")
+	if err == nil || !strings.Contains(stderr, "bio: needs pro access or higher") {
+		t.Fatalf("expected a tier error, got %v\nstderr: %s", err, stderr)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[],"meta":{"result_count":0}}`))
+	}))
+	defer server.Close()
+
+	base := []string{"--base-url", server.URL, "--bearer-token", "test-token", "search", "recent", "--query", "golang lnag:en"}
+	_, stderr, err = runCTW(t, base...)
+	if err == nil || !strings.Contains(stderr, "did you mean lang:?") {
+		t.Fatalf("expected the linter to reject the query, got %v\nstderr: %s", err, stderr)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("expected no request for a rejected query, got %d", n)
+	}
+
+	if _, stderr, err := runCTW(t, append(base, "--skip-lint")...); err != nil {
+		t.Fatalf("expected --skip-lint to send the query, got %v\nstderr: %s", err, stderr)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("expected one request with --skip-lint, got %d", n)
+	}
+}
+
+func TestStreamRulesApplyPacksKeywords(t *testing.T) {
+	errCh := make(chan error, 4)
+	posts := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"data":[` +
+				`{"id":"1","value":"(golang OR \"machine learning\") lang:en","tag":"pack-1"},` +
+				`{"id":"2","value":"old keyword","tag":"pack-2"},` +
+				`{"id":"3","value":"cats","tag":"mine"}]}`))
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			recordError(errCh, err)
+		}
+		posts <- string(body)
+		_, _ = w.Write([]byte(`{"data":[],"meta":{"sent":"2024-05-01T00:00:00Z"}}`))
+	}))
+	defer server.Close()
+
+	keywordFile := filepath.Join(t.TempDir(), "keywords.txt")
+	if err := os.WriteFile(keywordFile, []byte("# brands\ngolang\n\nrust\n#gophercon\nmachine learning\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"stream", "rules", "apply", "--keyword-file", keywordFile, "--filter", "lang:en",
+		"--max-length", "40",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	var result struct {
+		Rules []struct {
+			Value    string   `json:"value"`
+			Tag      string   `json:"tag"`
+			Keywords []string `json:"keywords"`
+		} `json:"rules"`
+		Kept    int `json:"kept"`
+		Added   int `json:"added"`
+		Deleted int `json:"deleted"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if len(result.Rules) != 2 || result.Rules[0].Value != `(golang OR "machine learning") lang:en` ||
+		result.Rules[1].Value != "(rust OR #gophercon) lang:en" || result.Rules[1].Tag != "pack-2" {
+		t.Fatalf("unexpected rules: %+v", result.Rules)
+	}
+	if result.Kept != 1 || result.Added != 1 || result.Deleted != 1 {
+		t.Fatalf("unexpected changes: %+v", result)
+	}
+
+	if body := <-posts; !strings.Contains(body, `"ids":["2"]`) {
+		t.Fatalf("expected the stale rule to be deleted first, got %s", body)
+	}
+	if body := <-posts; !strings.Contains(body, `"tag":"pack-2"`) || !strings.Contains(body, `#gophercon`) {
+		t.Fatalf("expected the new rule to be added, got %s", body)
+	}
+	select {
+	case body := <-posts:
+		t.Fatalf("unexpected request: %s", body)
+	default:
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestStreamRulesApplyKeepsKeywordFlagsAsWritten(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			recordError(errCh, fmt.Errorf("unexpected request in dry run: %s %s", r.Method, r.URL.Path))
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"stream", "rules", "apply", "--dry-run",
+		"--keyword", "machine learning", "--keyword", `"deep learning"`,
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"value": "((machine learning) OR \"deep learning\")"`) {
+		t.Fatalf("expected --keyword values kept as written, got: %s", stdout)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestTweetsGetBatchesIDsFileAndReportsMissing(t *testing.T) {
+	errCh := make(chan error, 4)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/2/tweets" {
+			recordError(errCh, fmt.Errorf("unexpected path: %s", r.URL.Path))
+		}
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		if len(ids) > 100 {
+			recordError(errCh, fmt.Errorf("request with %d ids", len(ids)))
+		}
+		var data, errs []string
+		for _, id := range ids {
+			switch id {
+			case "42":
+				errs = append(errs, `{"value":"42","resource_id":"42","resource_type":"tweet","detail":"Could not find tweet with ids: [42].","type":"https://api.twitter.com/2/problems/resource-not-found"}`)
+			case "43":
+				errs = append(errs, `{"value":"43","resource_id":"43","resource_type":"tweet","type":"https://api.twitter.com/2/problems/not-authorized-for-resource"}`)
+			default:
+				data = append(data, fmt.Sprintf(`{"id":%q,"text":"tweet %s"}`, id, id))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[%s],"errors":[%s]}`, strings.Join(data, ","), strings.Join(errs, ","))
+	}))
+	defer server.Close()
+
+	var lines []string
+	for i := 1; i <= 150; i++ {
+		lines = append(lines, fmt.Sprintf("https://x.com/gopher/status/%d?s=20", i))
+	}
+	dir := t.TempDir()
+	idsFile := filepath.Join(dir, "ids.txt")
+	if err := os.WriteFile(idsFile, []byte("# tweets\n"+strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "missing.ndjson")
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"tweets", "get", "--ids-file", idsFile, "--missing-report", report,
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 requests, got %d", got)
+	}
+
+	var payload struct {
+		Data   []struct{ ID string } `json:"data"`
+		Errors []json.RawMessage     `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &payload); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if len(payload.Data) != 148 || payload.Data[0].ID != "1" || payload.Data[147].ID != "150" || len(payload.Errors) != 2 {
+		t.Fatalf("unexpected merged response: %d tweets, %d errors", len(payload.Data), len(payload.Errors))
+	}
+
+	contents, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"42","reason":"not_found","detail":"Could not find tweet with ids: [42]."}` + "\n" +
+		`{"id":"43","reason":"protected"}` + "\n"
+	if string(contents) != want {
+		t.Fatalf("unexpected missing report:\n%s", contents)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestTweetsConversationPrintsReplyTree(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		users := `"includes":{"users":[{"id":"10","username":"gopher"},{"id":"20","username":"alice"}]}`
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/2/tweets/3":
+			fmt.Fprintf(w, `{"data":{"id":"3","text":"thanks","author_id":"10","conversation_id":"1","referenced_tweets":[{"type":"replied_to","id":"2"}]},%s}`, users)
+		case r.URL.Path == "/2/tweets/search/recent":
+			if q := r.URL.Query().Get("query"); q != "conversation_id:1" {
+				recordError(errCh, fmt.Errorf("unexpected query: %q", q))
+			}
+			fmt.Fprintf(w, `{"data":[`+
+				`{"id":"3","text":"thanks","author_id":"10","conversation_id":"1","created_at":"2024-05-01T12:02:00Z","referenced_tweets":[{"type":"replied_to","id":"2"}]},`+
+				`{"id":"2","text":"congrats!","author_id":"20","conversation_id":"1","created_at":"2024-05-01T12:01:00Z","referenced_tweets":[{"type":"replied_to","id":"1"}]}`+
+				`],%s,"meta":{"result_count":2}}`, users)
+		case r.URL.Path == "/2/tweets" && r.URL.Query().Get("ids") == "1":
+			fmt.Fprintf(w, `{"data":[{"id":"1","text":"Go 1.25 is out","author_id":"10","conversation_id":"1","created_at":"2024-05-01T12:00:00Z"}],%s}`, users)
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s", r.URL))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"tweets", "conversation", "--id", "https://x.com/gopher/status/3", "--text",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+	want := "@gopher: Go 1.25 is out [1]\n  @alice: congrats! [2]\n    @gopher: thanks [3]\n"
+	if stdout != want {
+		t.Fatalf("unexpected tree:\n%s", stdout)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestTweetsEngagementSummarizesAccounts(t *testing.T) {
+	errCh := make(chan error, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/tweets/42/retweeted_by":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","username":"alice","public_metrics":{"followers_count":100}}],"meta":{"result_count":1}}`))
+		case "/2/tweets/42/liking_users":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","username":"alice","public_metrics":{"followers_count":100}},{"id":"2","username":"bob","public_metrics":{"followers_count":5000}}],"meta":{"result_count":2}}`))
+		case "/2/tweets/42/quote_tweets":
+			_, _ = w.Write([]byte(`{"data":[{"id":"9","text":"look","author_id":"3"}],"includes":{"users":[{"id":"3","username":"carol","public_metrics":{"followers_count":7}}]},"meta":{"result_count":1}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s", r.URL))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"tweets", "engagement", "--id", "https://x.com/gopher/status/42",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	var summary struct {
+		Retweeters  int `json:"retweeters"`
+		Likers      int `json:"likers"`
+		Quoters     int `json:"quoters"`
+		Accounts    int `json:"accounts"`
+		Repeat      int `json:"repeat_accounts"`
+		TopAccounts []struct {
+			Username   string   `json:"username"`
+			Followers  int      `json:"followers"`
+			Engagement []string `json:"engagement"`
+		} `json:"top_accounts"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if summary.Retweeters != 1 || summary.Likers != 2 || summary.Quoters != 1 || summary.Accounts != 3 || summary.Repeat != 1 {
+		t.Fatalf("unexpected counts: %+v", summary)
+	}
+	if len(summary.TopAccounts) != 3 || summary.TopAccounts[0].Username != "bob" ||
+		strings.Join(summary.TopAccounts[1].Engagement, ",") != "retweeted,liked" {
+		t.Fatalf("unexpected top accounts: %+v", summary.TopAccounts)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestTweetsHideReplySweepHidesMatches(t *testing.T) {
+	errCh := make(chan error, 4)
+	var hidden []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		users := `"includes":{"users":[{"id":"10","username":"acme"},{"id":"20","username":"alice"},{"id":"30","username":"spammer"}]}`
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if string(bytes.TrimSpace(body)) != `{"hidden":true}` {
+				recordError(errCh, fmt.Errorf("unexpected hide body: %s", body))
+			}
+			mu.Lock()
+			hidden = append(hidden, r.URL.Path)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"data":{"hidden":true}}`))
+		case r.URL.Path == "/2/tweets/1":
+			fmt.Fprintf(w, `{"data":{"id":"1","text":"Launch day!","author_id":"10","conversation_id":"1"},%s}`, users)
+		case r.URL.Path == "/2/tweets/search/recent":
+			fmt.Fprintf(w, `{"data":[`+
+				`{"id":"4","text":"FREE AIRDROP click here","author_id":"20","conversation_id":"1","created_at":"2024-05-01T12:03:00Z","referenced_tweets":[{"type":"replied_to","id":"1"}]},`+
+				`{"id":"3","text":"nice","author_id":"30","conversation_id":"1","created_at":"2024-05-01T12:02:00Z","referenced_tweets":[{"type":"replied_to","id":"1"}]},`+
+				`{"id":"2","text":"congrats!","author_id":"20","conversation_id":"1","created_at":"2024-05-01T12:01:00Z","referenced_tweets":[{"type":"replied_to","id":"1"}]}`+
+				`],%s,"meta":{"result_count":3}}`, users)
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(blocklist, []byte("# spam accounts\n@Spammer\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"tweets", "hide-reply", "--sweep", "1", "--blocklist", blocklist, "--block", "(?i)airdrop",
+	)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v\nstderr: %s", err, stderr)
+	}
+
+	var payload struct {
+		Data []struct {
+			ID     string `json:"id"`
+			Reason string `json:"reason"`
+			Hidden bool   `json:"hidden"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &payload); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if len(payload.Data) != 2 || payload.Data[0].ID != "3" || payload.Data[0].Reason != "author @spammer" ||
+		payload.Data[1].ID != "4" || !payload.Data[1].Hidden {
+		t.Fatalf("unexpected sweep results: %+v", payload.Data)
+	}
+	if strings.Join(hidden, ",") != "/2/tweets/3/hidden,/2/tweets/4/hidden" {
+		t.Fatalf("unexpected hide requests: %v", hidden)
+	}
+
+	drainErrors(t, errCh)
+}
+
+func TestTweetsHideReplySweepRateLimitedPrintsResults(t *testing.T) {
+	errCh := make(chan error, 4)
+	var hidden []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPut:
+			hidden = append(hidden, r.URL.Path)
+			if len(hidden) > 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"title":"Too Many Requests","detail":"Too Many Requests","type":"about:blank","status":429}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"hidden":true}}`))
+		case r.URL.Path == "/2/tweets/1":
+			_, _ = w.Write([]byte(`{"data":{"id":"1","text":"Launch day!","author_id":"10","conversation_id":"1"}}`))
+		case r.URL.Path == "/2/tweets/search/recent":
+			_, _ = w.Write([]byte(`{"data":[` +
+				`{"id":"4","text":"airdrop 3","author_id":"20","conversation_id":"1","created_at":"2024-05-01T12:03:00Z","referenced_tweets":[{"type":"replied_to","id":"1"}]},` +
+				`{"id":"3","text":"airdrop 2","author_id":"20","conversation_id":"1","created_at":"2024-05-01T12:02:00Z","referenced_tweets":[{"type":"replied_to","id":"1"}]},` +
+				`{"id":"2","text":"airdrop 1","author_id":"20","conversation_id":"1","created_at":"2024-05-01T12:01:00Z","referenced_tweets":[{"type":"replied_to","id":"1"}]}` +
+				`],"meta":{"result_count":3}}`))
+		default:
+			recordError(errCh, fmt.Errorf("unexpected request: %s %s", r.Method, r.URL))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	stdout, stderr, err := runCTW(t,
+		"--base-url", server.URL,
+		"--bearer-token", "test-token",
+		"--retry", "0",
+		"tweets", "hide-reply", "--sweep", "1", "--block", "airdrop",
+		"-o", "csv", "--columns", "id,hidden,error",
+	)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != exitRateLimited {
+		t.Fatalf("expected exit code %d, got: %v\nstderr: %s", exitRateLimited, err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "id,hidden,error\n2,true,\n3,false,") {
+		t.Fatalf("unexpected output: %q", stdout)
+	}
+	if !strings.HasSuffix(stdout, "\n4,false,not attempted: rate limited\n") {
+		t.Fatalf("expected the last reply to be reported as not attempted: %q", stdout)
+	}
+	if strings.Join(hidden, ",") != "/2/tweets/2/hidden,/2/tweets/3/hidden" {
+		t.Fatalf("unexpected hide requests: %v", hidden)
+	}
+
+	drainErrors(t, errCh)
 }
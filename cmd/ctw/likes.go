@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/fields"
 	"github.com/0dayfall/ctw/internal/tweet/likes"
 	"github.com/spf13/cobra"
 )
@@ -23,12 +25,14 @@ func newLikesCommand() *cobra.Command {
 	cmd.AddCommand(newLikesAddCommand())
 	cmd.AddCommand(newLikesRemoveCommand())
 	cmd.AddCommand(newLikesListCommand())
+	cmd.AddCommand(newLikesUsersCommand())
 
 	return cmd
 }
 
 func newLikesAddCommand() *cobra.Command {
 	var (
+		me      *bool
 		userID  string
 		tweetID string
 	)
@@ -37,9 +41,6 @@ func newLikesAddCommand() *cobra.Command {
 		Use:   "add",
 		Short: "Like a tweet",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 			if strings.TrimSpace(tweetID) == "" {
 				return errors.New("--tweet-id is required")
 			}
@@ -53,6 +54,9 @@ func newLikesAddCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := likes.NewService(c)
 			response, rateLimits, err := service.LikeTweet(ctx, userID, tweetID)
@@ -60,7 +64,7 @@ func newLikesAddCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -68,7 +72,8 @@ func newLikesAddCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user liking the tweet")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user liking the tweet (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID of the tweet to like")
 
 	return cmd
@@ -76,6 +81,7 @@ func newLikesAddCommand() *cobra.Command {
 
 func newLikesRemoveCommand() *cobra.Command {
 	var (
+		me      *bool
 		userID  string
 		tweetID string
 	)
@@ -84,9 +90,6 @@ func newLikesRemoveCommand() *cobra.Command {
 		Use:   "remove",
 		Short: "Unlike a tweet",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 			if strings.TrimSpace(tweetID) == "" {
 				return errors.New("--tweet-id is required")
 			}
@@ -100,6 +103,9 @@ func newLikesRemoveCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := likes.NewService(c)
 			response, rateLimits, err := service.UnlikeTweet(ctx, userID, tweetID)
@@ -107,7 +113,7 @@ func newLikesRemoveCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -115,7 +121,8 @@ func newLikesRemoveCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user unliking the tweet")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user unliking the tweet (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID of the tweet to unlike")
 
 	return cmd
@@ -123,6 +130,9 @@ func newLikesRemoveCommand() *cobra.Command {
 
 func newLikesListCommand() *cobra.Command {
 	var (
+		me         *bool
+		fieldOpts  *fieldFlags
+		storeOpts  *storeFlag
 		userID     string
 		paramsFlag []string
 	)
@@ -131,9 +141,6 @@ func newLikesListCommand() *cobra.Command {
 		Use:   "list",
 		Short: "List liked tweets for a user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 
 			ctx := cmd.Context()
 			if ctx == nil {
@@ -144,11 +151,17 @@ func newLikesListCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("parse params: %w", err)
 			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := likes.NewService(c)
 			response, rateLimits, err := service.ListLikedTweets(ctx, userID, queryParams)
@@ -156,7 +169,11 @@ func newLikesListCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := storeOpts.save(ctx, "likes list: "+userID, response); err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -164,8 +181,25 @@ func newLikesListCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user whose likes to list")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user whose likes to list (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+	storeOpts = addStoreFlag(cmd)
+
 	return cmd
 }
+
+func newLikesUsersCommand() *cobra.Command {
+	return newTweetListCommand("users", "List users who liked a tweet", "liking users", fields.Users,
+		func(ctx context.Context, c *client.Client, tweetID string, params map[string]string, all bool, maxPages int) (any, client.RateLimitSnapshot, error) {
+			service := likes.NewService(c)
+			if !all {
+				response, rateLimits, err := service.ListLikingUsers(ctx, tweetID, params)
+				return response, rateLimits, err
+			}
+			response, rateLimits, err := service.AllLikingUsers(ctx, tweetID, params, maxPages)
+			return response, rateLimits, err
+		})
+}
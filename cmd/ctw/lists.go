@@ -0,0 +1,517 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/lists"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newListsCommand())
+}
+
+func newListsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lists",
+		Short: "Manage and read Lists",
+	}
+
+	cmd.AddCommand(newListsGetCommand())
+	cmd.AddCommand(newListsUserCommand("owned", "Lists owned by a user", (*lists.Service).Owned))
+	cmd.AddCommand(newListsUserCommand("followed", "Lists a user follows", (*lists.Service).Followed))
+	cmd.AddCommand(newListsUserCommand("pinned", "Lists a user has pinned", (*lists.Service).Pinned))
+	cmd.AddCommand(newListsCreateCommand())
+	cmd.AddCommand(newListsUpdateCommand())
+	cmd.AddCommand(newListsDeleteCommand())
+	cmd.AddCommand(newListsTweetsCommand())
+	cmd.AddCommand(newListsMembersCommand())
+
+	return cmd
+}
+
+func newListsGetCommand() *cobra.Command {
+	var (
+		listID     string
+		paramsFlag []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Lookup a list by id",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(listID) == "" {
+				return errors.New("--id is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return fmt.Errorf("parse params: %w", err)
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := lists.NewService(c)
+			response, rateLimits, err := service.Get(ctx, listID, queryParams)
+			if err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listID, "id", "", "List id")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+
+	return cmd
+}
+
+type userListsFunc func(*lists.Service, context.Context, string, map[string]string) (lists.ListsResponse, client.RateLimitSnapshot, error)
+
+func newListsUserCommand(use, short string, fetch userListsFunc) *cobra.Command {
+	var (
+		me         *bool
+		userID     string
+		paramsFlag []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(userID) == "" && !*me {
+				return errors.New("--user-id or --me is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return fmt.Errorf("parse params: %w", err)
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
+
+			response, rateLimits, err := fetch(lists.NewService(c), ctx, userID, queryParams)
+			if err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user whose lists to fetch")
+	me = addMeFlag(cmd, "user-id")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+
+	return cmd
+}
+
+func newListsCreateCommand() *cobra.Command {
+	var (
+		name        string
+		description string
+		private     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(name) == "" {
+				return errors.New("--name is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := lists.NewService(c)
+			response, rateLimits, err := service.Create(ctx, lists.CreateRequest{
+				Name:        name,
+				Description: description,
+				Private:     private,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name of the list")
+	cmd.Flags().StringVar(&description, "description", "", "Description of the list")
+	cmd.Flags().BoolVar(&private, "private", false, "Create the list as private")
+
+	return cmd
+}
+
+func newListsUpdateCommand() *cobra.Command {
+	var (
+		listID      string
+		name        string
+		description string
+		private     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update a list's name, description or visibility",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(listID) == "" {
+				return errors.New("--id is required")
+			}
+
+			var req lists.UpdateRequest
+			if cmd.Flags().Changed("name") {
+				req.Name = &name
+			}
+			if cmd.Flags().Changed("description") {
+				req.Description = &description
+			}
+			if cmd.Flags().Changed("private") {
+				req.Private = &private
+			}
+			if req.Name == nil && req.Description == nil && req.Private == nil {
+				return errors.New("provide --name, --description or --private")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := lists.NewService(c)
+			response, rateLimits, err := service.Update(ctx, listID, req)
+			if err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listID, "id", "", "List id")
+	cmd.Flags().StringVar(&name, "name", "", "New name")
+	cmd.Flags().StringVar(&description, "description", "", "New description")
+	cmd.Flags().BoolVar(&private, "private", false, "Make the list private (--private=false to make it public)")
+
+	return cmd
+}
+
+func newListsDeleteCommand() *cobra.Command {
+	var listID string
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(listID) == "" {
+				return errors.New("--id is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := lists.NewService(c)
+			response, rateLimits, err := service.Delete(ctx, listID)
+			if err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listID, "id", "", "List id")
+
+	return cmd
+}
+
+func newListsTweetsCommand() *cobra.Command {
+	var (
+		fieldOpts  *fieldFlags
+		listID     string
+		all        bool
+		maxPages   int
+		paramsFlag []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tweets",
+		Short: "Read a list's tweet timeline",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(listID) == "" {
+				return errors.New("--id is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return fmt.Errorf("parse params: %w", err)
+			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := lists.NewService(c)
+
+			var (
+				response   model.TweetsResponse
+				rateLimits client.RateLimitSnapshot
+			)
+			if all {
+				response, rateLimits, err = service.AllTweets(ctx, listID, queryParams, maxPages)
+			} else {
+				response, rateLimits, err = service.Tweets(ctx, listID, queryParams)
+			}
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listID, "id", "", "List id")
+	cmd.Flags().BoolVar(&all, "all", false, "Follow pagination and return every page")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop --all after this many pages (0 for no limit)")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+
+	return cmd
+}
+
+func newListsMembersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "List, add or remove list members",
+	}
+
+	cmd.AddCommand(newListsMembersListCommand())
+	cmd.AddCommand(newListsMembersChangeCommand("add", "Add users to a list", (*lists.Service).AddMember))
+	cmd.AddCommand(newListsMembersChangeCommand("remove", "Remove users from a list", (*lists.Service).RemoveMember))
+
+	return cmd
+}
+
+func newListsMembersListCommand() *cobra.Command {
+	var (
+		fieldOpts  *fieldFlags
+		listID     string
+		all        bool
+		maxPages   int
+		paramsFlag []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the members of a list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(listID) == "" {
+				return errors.New("--id is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return fmt.Errorf("parse params: %w", err)
+			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := lists.NewService(c)
+
+			var (
+				response   model.UsersResponse
+				rateLimits client.RateLimitSnapshot
+			)
+			if all {
+				response, rateLimits, err = service.AllMembers(ctx, listID, queryParams, maxPages)
+			} else {
+				response, rateLimits, err = service.Members(ctx, listID, queryParams)
+			}
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listID, "id", "", "List id")
+	cmd.Flags().BoolVar(&all, "all", false, "Follow pagination and return every page")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop --all after this many pages (0 for no limit)")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+
+	fieldOpts = addFieldFlags(cmd, fields.Users)
+
+	return cmd
+}
+
+type memberChangeFunc func(*lists.Service, context.Context, string, string) (lists.MemberResponse, client.RateLimitSnapshot, error)
+
+// memberResult reports the outcome of one user in a bulk members add or
+// remove, so a partially applied file can be retried from the output.
+type memberResult struct {
+	UserID   string `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	IsMember bool   `json:"is_member"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newListsMembersChangeCommand(use, short string, change memberChangeFunc) *cobra.Command {
+	var (
+		targets *targetFlags
+		listID  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(listID) == "" {
+				return errors.New("--id is required")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			users, err := targets.resolve(ctx, c)
+			if err != nil {
+				return err
+			}
+
+			service := lists.NewService(c)
+			results := make([]memberResult, len(users))
+			errs, failed, rateLimits, applyErr := applyToTargets(users, func(i int) (client.RateLimitSnapshot, error) {
+				response, limits, err := change(service, ctx, listID, users[i].UserID)
+				results[i].IsMember = response.Data.IsMember
+				return limits, err
+			})
+			for i, user := range users {
+				results[i].UserID = user.UserID
+				results[i].Username = user.Username
+				results[i].Error = errs[i]
+			}
+
+			if err := printOutput(struct {
+				Data []memberResult `json:"data"`
+			}{Data: results}); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+
+			if applyErr != nil {
+				return applyErr
+			}
+			return errTargetsFailed(failed, len(results))
+		},
+	}
+
+	cmd.Flags().StringVar(&listID, "id", "", "List id")
+	targets = addTargetFlags(cmd, "user-id", "User id or @handle")
+
+	return cmd
+}
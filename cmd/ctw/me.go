@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/handles"
+	lookupsvc "github.com/0dayfall/ctw/internal/users/lookup"
+	"github.com/spf13/cobra"
+)
+
+// meCacheFile holds the authenticated user's id per token, next to the
+// handle cache.
+const meCacheFile = "me.json"
+
+func init() {
+	rootCmd.AddCommand(newMeCommand())
+}
+
+func newMeCommand() *cobra.Command {
+	var (
+		fieldOpts  *fieldFlags
+		paramsFlag []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "me",
+		Short: "Show the user the bearer token acts for",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return err
+			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			response, rateLimits, err := lookupsvc.NewService(c).Me(ctx, queryParams)
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+			saveCachedUserID(response.Data.ID)
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&paramsFlag, "param", nil, "Additional query parameter in key=value format")
+
+	fieldOpts = addFieldFlags(cmd, fields.Users)
+
+	return cmd
+}
+
+// meCacheKey identifies the account a token acts for without storing the
+// token itself. The base URL is included so test or proxy endpoints do not
+// share entries with the real API.
+func meCacheKey() string {
+	sum := sha256.Sum256([]byte(resolvedSettings.BaseURL + "\x00" + resolvedSettings.BearerToken))
+	return hex.EncodeToString(sum[:16])
+}
+
+func openMeCache() (*handles.Cache, error) {
+	if resolvedSettings.HandleTTL <= 0 || resolvedSettings.CacheDir == "" || resolvedSettings.BearerToken == "" {
+		return nil, nil
+	}
+	return handles.OpenCache(filepath.Join(resolvedSettings.CacheDir, meCacheFile), resolvedSettings.HandleTTL)
+}
+
+// cachedUserID returns the authenticated user's id from an earlier run.
+func cachedUserID() string {
+	cache, err := openMeCache()
+	if err != nil {
+		logger.Debug("me cache unavailable", "error", err)
+		return ""
+	}
+	id, _ := cache.Get(meCacheKey())
+	return id
+}
+
+// saveCachedUserID remembers the authenticated user's id for later runs.
+func saveCachedUserID(id string) {
+	cache, err := openMeCache()
+	if err != nil || id == "" {
+		return
+	}
+	cache.Put(meCacheKey(), id)
+	if err := cache.Save(); err != nil {
+		logger.Warn("me cache not saved", "error", err)
+	}
+}
@@ -77,11 +77,11 @@ Videos are processed asynchronously and this command will wait for completion.`,
 				"status":          "uploaded",
 			}
 
-			if err := printJSON(result); err != nil {
+			if err := printOutput(result); err != nil {
 				return err
 			}
 
-			fmt.Fprintf(os.Stderr, "Media uploaded successfully. Use media_id_string in tweets/DMs.\n")
+			logger.Info("media uploaded; use media_id_string in tweets/DMs", "media_id", mediaID)
 			return nil
 		},
 	}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0dayfall/ctw/internal/searchquery"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newQueryCommand())
+}
+
+func newQueryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Check and format search queries and stream rules",
+	}
+
+	cmd.AddCommand(newQueryLintCommand())
+
+	return cmd
+}
+
+func newQueryLintCommand() *cobra.Command {
+	var (
+		endpoint string
+		tier     string
+		format   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint QUERY",
+		Short: "Check a query against the operators and limits of an endpoint",
+		Long: `Parse a search query or stream rule and report what the API would reject.
+
+The query is checked for syntax (balanced parentheses and quotes, OR with a
+term on both sides), unknown or misspelled operators, operators the endpoint
+or access tier does not offer, operators that cannot stand alone, and the
+length limit. Warnings flag queries that are accepted but probably mean
+something else, such as a lower-case "or".
+
+The tier comes from --tier, CTW_TIER or [api] tier in the config file. Without
+one, tier-restricted operators are allowed.
+
+The result lists the issues and the normalized query. With --print the query
+is printed instead: normalized on one line or indented across lines. The
+command exits non-zero when the query has errors.
+
+The same checks run before search recent, stream rules add and
+watch --auto-setup; pass --skip-lint there to send a query anyway.
+
+Examples:
+  ctw query lint '(golang OR #go) -is:retweet lang:en'
+  ctw query lint --endpoint stream --tier basic 'gopher bio:developer'
+  ctw query lint --print indented '(a OR (b c)) -from:spam'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := searchquery.ParseEndpoint(endpoint)
+			if err != nil {
+				return err
+			}
+			level := resolvedSettings.Tier
+			if cmd.Flags().Changed("tier") {
+				if level, err = searchquery.ParseTier(tier); err != nil {
+					return err
+				}
+			}
+
+			result := searchquery.Lint(args[0], searchquery.Options{Endpoint: target, Tier: level})
+			switch format {
+			case "":
+				if err := printOutput(result); err != nil {
+					return err
+				}
+			case "normalized", "indented":
+				for _, issue := range result.Issues {
+					fmt.Fprintln(os.Stderr, issue)
+				}
+				if result.Tree() != nil {
+					text := result.Normalized
+					if format == "indented" {
+						text = searchquery.Indent(result.Tree())
+					}
+					fmt.Fprintln(os.Stdout, text)
+				}
+			default:
+				return fmt.Errorf("unknown --print %q (expected normalized or indented)", format)
+			}
+			return result.Err()
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", string(searchquery.EndpointSearch), "Endpoint the query is for: search or stream")
+	cmd.Flags().StringVar(&tier, "tier", "", "Access tier to check against: basic, pro or enterprise (default: configured tier)")
+	cmd.Flags().StringVar(&format, "print", "", "Print only the query: normalized or indented")
+
+	return cmd
+}
+
+// lintFlag is the --skip-lint option of the commands that send queries.
+type lintFlag struct {
+	endpoint searchquery.Endpoint
+	skip     bool
+}
+
+func addLintFlag(cmd *cobra.Command, endpoint searchquery.Endpoint) *lintFlag {
+	f := &lintFlag{endpoint: endpoint}
+	cmd.Flags().BoolVar(&f.skip, "skip-lint", false, "Send the query even when ctw query lint rejects it")
+	return f
+}
+
+// check lints query for the flag's endpoint and configured tier. Warnings are
+// logged; errors are returned unless --skip-lint is set.
+func (f *lintFlag) check(query string) error {
+	if f == nil || f.skip {
+		return nil
+	}
+	result := searchquery.Lint(query, searchquery.Options{Endpoint: f.endpoint, Tier: resolvedSettings.Tier})
+	for _, issue := range result.Warnings() {
+		logger.Warn("query lint", "query", query, "column", issue.Pos, "warning", issue.Message)
+	}
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("%w (use --skip-lint to send it anyway)", err)
+	}
+	return nil
+}
@@ -3,9 +3,10 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/fields"
 	"github.com/0dayfall/ctw/internal/tweet/retweets"
 	"github.com/spf13/cobra"
 )
@@ -23,12 +24,14 @@ func newRetweetsCommand() *cobra.Command {
 	cmd.AddCommand(newRetweetsAddCommand())
 	cmd.AddCommand(newRetweetsRemoveCommand())
 	cmd.AddCommand(newRetweetsListCommand())
+	cmd.AddCommand(newRetweetsQuotesCommand())
 
 	return cmd
 }
 
 func newRetweetsAddCommand() *cobra.Command {
 	var (
+		me      *bool
 		userID  string
 		tweetID string
 	)
@@ -37,9 +40,6 @@ func newRetweetsAddCommand() *cobra.Command {
 		Use:   "add",
 		Short: "Retweet a tweet",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 			if strings.TrimSpace(tweetID) == "" {
 				return errors.New("--tweet-id is required")
 			}
@@ -53,6 +53,9 @@ func newRetweetsAddCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := retweets.NewService(c)
 			response, rateLimits, err := service.Retweet(ctx, userID, tweetID)
@@ -60,7 +63,7 @@ func newRetweetsAddCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -68,7 +71,8 @@ func newRetweetsAddCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user retweeting")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user retweeting (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID of the tweet to retweet")
 
 	return cmd
@@ -76,6 +80,7 @@ func newRetweetsAddCommand() *cobra.Command {
 
 func newRetweetsRemoveCommand() *cobra.Command {
 	var (
+		me      *bool
 		userID  string
 		tweetID string
 	)
@@ -84,9 +89,6 @@ func newRetweetsRemoveCommand() *cobra.Command {
 		Use:   "remove",
 		Short: "Remove a retweet",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 			if strings.TrimSpace(tweetID) == "" {
 				return errors.New("--tweet-id is required")
 			}
@@ -100,6 +102,9 @@ func newRetweetsRemoveCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := retweets.NewService(c)
 			response, rateLimits, err := service.Unretweet(ctx, userID, tweetID)
@@ -107,7 +112,7 @@ func newRetweetsRemoveCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -115,57 +120,35 @@ func newRetweetsRemoveCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user removing the retweet")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user removing the retweet (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID of the tweet to unretweet")
 
 	return cmd
 }
 
 func newRetweetsListCommand() *cobra.Command {
-	var (
-		tweetID    string
-		paramsFlag []string
-	)
-
-	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "List users who retweeted a tweet",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(tweetID) == "" {
-				return errors.New("--tweet-id is required")
-			}
-
-			ctx := cmd.Context()
-			if ctx == nil {
-				ctx = context.Background()
-			}
-
-			queryParams, err := parseKeyValuePairs(paramsFlag)
-			if err != nil {
-				return fmt.Errorf("parse params: %w", err)
-			}
-
-			c, err := newClientFromFlags()
-			if err != nil {
-				return err
-			}
-
+	return newTweetListCommand("list", "List users who retweeted a tweet", "retweeters", fields.Users,
+		func(ctx context.Context, c *client.Client, tweetID string, params map[string]string, all bool, maxPages int) (any, client.RateLimitSnapshot, error) {
 			service := retweets.NewService(c)
-			response, rateLimits, err := service.ListRetweeters(ctx, tweetID, queryParams)
-			if err != nil {
-				return err
+			if !all {
+				response, rateLimits, err := service.ListRetweeters(ctx, tweetID, params)
+				return response, rateLimits, err
 			}
+			response, rateLimits, err := service.AllRetweeters(ctx, tweetID, params, maxPages)
+			return response, rateLimits, err
+		})
+}
 
-			if err := printJSON(response); err != nil {
-				return err
+func newRetweetsQuotesCommand() *cobra.Command {
+	return newTweetListCommand("quotes", "List tweets that quote a tweet", "quote tweets", fields.Tweets,
+		func(ctx context.Context, c *client.Client, tweetID string, params map[string]string, all bool, maxPages int) (any, client.RateLimitSnapshot, error) {
+			service := retweets.NewService(c)
+			if !all {
+				response, rateLimits, err := service.ListQuoteTweets(ctx, tweetID, params)
+				return response, rateLimits, err
 			}
-			printRateLimits(rateLimits)
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVar(&tweetID, "tweet-id", "", "ID of the tweet to list retweeters for")
-	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
-
-	return cmd
+			response, rateLimits, err := service.AllQuoteTweets(ctx, tweetID, params, maxPages)
+			return response, rateLimits, err
+		})
 }
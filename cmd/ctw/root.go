@@ -37,6 +37,12 @@ func init() {
 	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", 0, "HTTP timeout (e.g. 15s)")
 	rootCmd.PersistentFlags().IntVar(&retryFlag, "retry", 0, "HTTP retry attempts for transient failures")
 	rootCmd.PersistentFlags().BoolVar(&prettyFlag, "pretty", false, "Pretty-print JSON output")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "", "Output format (json|ndjson|table|csv|tsv|template|parquet)")
+	rootCmd.PersistentFlags().StringVar(&templateFlag, "template", "", "Go template applied to each record, e.g. '{{.ID}} {{.Text}}' (implies --output template)")
+	rootCmd.PersistentFlags().StringSliceVar(&columnsFlag, "columns", nil, "Comma-separated columns for table/csv/tsv output (JSON field names; dots reach nested fields)")
+	rootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", "", "Log level for stderr diagnostics (debug|info|warn|error)")
+	rootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", "", "Log format for stderr diagnostics (text|json)")
+	rootCmd.PersistentFlags().BoolVar(&traceFlag, "trace", false, "Log redacted HTTP request/response headers, timings and retry decisions")
 
 	// Set custom version output
 	rootCmd.SetVersionTemplate(fmt.Sprintf("ctw version %s\nCommit: %s\nBuilt:  %s\n", Version, Commit, Date))
@@ -45,7 +51,7 @@ func init() {
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
@@ -60,6 +66,10 @@ func newClientFromFlags() (*client.Client, error) {
 		UserAgent:   resolvedSettings.UserAgent,
 		Timeout:     resolvedSettings.Timeout,
 		Retry:       resolvedSettings.Retry,
+		Logger:      logger,
+		Trace:       resolvedSettings.Trace,
+		UserID:      cachedUserID(),
+		OnUserID:    saveCachedUserID,
 	}
 	return client.New(cfg)
 }
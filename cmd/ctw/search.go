@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/searchquery"
 	recentsearch "github.com/0dayfall/ctw/internal/tweet/recentsearch"
 	"github.com/spf13/cobra"
 )
@@ -24,6 +26,9 @@ func newSearchCommand() *cobra.Command {
 
 func newSearchRecentCommand() *cobra.Command {
 	var (
+		fieldOpts  *fieldFlags
+		storeOpts  *storeFlag
+		lintOpts   *lintFlag
 		query      string
 		nextToken  string
 		extraPairs []string
@@ -36,6 +41,9 @@ func newSearchRecentCommand() *cobra.Command {
 			if query == "" {
 				return errors.New("query is required")
 			}
+			if err := lintOpts.check(query); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if ctx == nil {
@@ -56,6 +64,9 @@ func newSearchRecentCommand() *cobra.Command {
 					params[k] = v
 				}
 			}
+			if err := fieldOpts.apply(params); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
@@ -69,7 +80,11 @@ func newSearchRecentCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := storeOpts.save(ctx, "search recent: "+query, response); err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -81,5 +96,9 @@ func newSearchRecentCommand() *cobra.Command {
 	cmd.Flags().StringVar(&nextToken, "next-token", "", "Pagination token to continue a previous search")
 	cmd.Flags().StringArrayVar(&extraPairs, "param", nil, "Additional query parameter in key=value format")
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+	storeOpts = addStoreFlag(cmd)
+	lintOpts = addLintFlag(cmd, searchquery.EndpointSearch)
+
 	return cmd
 }
@@ -3,11 +3,13 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/0dayfall/ctw/internal/config"
+	"github.com/0dayfall/ctw/internal/searchquery"
 	"github.com/spf13/cobra"
 )
 
@@ -17,8 +19,17 @@ type Settings struct {
 	UserAgent        string
 	Timeout          time.Duration
 	Retry            int
+	Tier             searchquery.Tier
 	PrettyOutput     bool
+	OutputFormat     string
+	OutputTemplate   string
+	OutputColumns    []string
 	StreamBackoffMax time.Duration
+	LogLevel         string
+	LogFormat        string
+	Trace            bool
+	CacheDir         string
+	HandleTTL        time.Duration
 	ConfigPath       string
 	ConfigLoaded     bool
 }
@@ -28,10 +39,19 @@ var (
 	timeoutFlag    time.Duration
 	retryFlag      int
 	prettyFlag     bool
+	outputFlag     string
+	templateFlag   string
+	columnsFlag    []string
+	logLevelFlag   string
+	logFormatFlag  string
+	traceFlag      bool
 
 	resolvedSettings Settings
 	settingsLoaded   bool
 	prettyOutput     bool
+	outputFormat     string
+	outputTemplate   string
+	outputColumns    []string
 )
 
 func ensureSettings(cmd *cobra.Command) error {
@@ -62,22 +82,47 @@ func ensureSettings(cmd *cobra.Command) error {
 		UserAgent:        strings.TrimSpace(cfg.HTTP.UserAgent),
 		Timeout:          cfg.HTTP.Timeout.Std(),
 		Retry:            cfg.HTTP.Retry,
+		Tier:             searchquery.Tier(strings.TrimSpace(cfg.API.Tier)),
 		PrettyOutput:     cfg.Output.Pretty,
+		OutputFormat:     strings.TrimSpace(cfg.Output.Format),
 		StreamBackoffMax: cfg.Stream.BackoffMax.Std(),
+		LogLevel:         strings.TrimSpace(cfg.Log.Level),
+		LogFormat:        strings.TrimSpace(cfg.Log.Format),
+		CacheDir:         strings.TrimSpace(cfg.Cache.Dir),
+		HandleTTL:        cfg.Cache.HandleTTL.Std(),
 		ConfigPath:       cfgPath,
 		ConfigLoaded:     loaded,
 	}
 
+	if settings.CacheDir == "" {
+		settings.CacheDir = filepath.Dir(cfgPath)
+	}
+
 	if err := applyEnvOverrides(&settings); err != nil {
 		return err
 	}
 	if err := applyFlagOverrides(cmd, &settings); err != nil {
 		return err
 	}
+	if err := validateOutputSettings(&settings); err != nil {
+		return err
+	}
+	if settings.Tier, err = searchquery.ParseTier(string(settings.Tier)); err != nil {
+		return err
+	}
+
+	configured, err := newLogger(os.Stderr, settings.LogLevel, settings.LogFormat, settings.Trace)
+	if err != nil {
+		return err
+	}
 
 	resolvedSettings = settings
 	settingsLoaded = true
 	prettyOutput = settings.PrettyOutput
+	outputFormat = settings.OutputFormat
+	outputTemplate = settings.OutputTemplate
+	outputColumns = settings.OutputColumns
+	logger = configured
 	return nil
 }
 
@@ -105,6 +150,9 @@ func applyEnvOverrides(settings *Settings) error {
 		}
 		settings.Retry = retry
 	}
+	if value := strings.TrimSpace(os.Getenv("CTW_TIER")); value != "" {
+		settings.Tier = searchquery.Tier(value)
+	}
 	if value := strings.TrimSpace(os.Getenv("CTW_PRETTY")); value != "" {
 		pretty, err := strconv.ParseBool(value)
 		if err != nil {
@@ -112,6 +160,9 @@ func applyEnvOverrides(settings *Settings) error {
 		}
 		settings.PrettyOutput = pretty
 	}
+	if value := strings.TrimSpace(os.Getenv("CTW_OUTPUT")); value != "" {
+		settings.OutputFormat = value
+	}
 	if value := strings.TrimSpace(os.Getenv("CTW_STREAM_BACKOFF_MAX")); value != "" {
 		backoff, err := time.ParseDuration(value)
 		if err != nil {
@@ -119,6 +170,22 @@ func applyEnvOverrides(settings *Settings) error {
 		}
 		settings.StreamBackoffMax = backoff
 	}
+	if value := strings.TrimSpace(os.Getenv("CTW_LOG_LEVEL")); value != "" {
+		settings.LogLevel = value
+	}
+	if value := strings.TrimSpace(os.Getenv("CTW_LOG_FORMAT")); value != "" {
+		settings.LogFormat = value
+	}
+	if value := strings.TrimSpace(os.Getenv("CTW_CACHE_DIR")); value != "" {
+		settings.CacheDir = value
+	}
+	if value := strings.TrimSpace(os.Getenv("CTW_TRACE")); value != "" {
+		trace, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("parse CTW_TRACE: %w", err)
+		}
+		settings.Trace = trace
+	}
 	return nil
 }
 
@@ -145,6 +212,32 @@ func applyFlagOverrides(cmd *cobra.Command, settings *Settings) error {
 	if cmd.Flags().Changed("pretty") {
 		settings.PrettyOutput = prettyFlag
 	}
+	if cmd.Flags().Changed("output") {
+		settings.OutputFormat = strings.TrimSpace(outputFlag)
+	}
+	if cmd.Flags().Changed("template") {
+		settings.OutputTemplate = templateFlag
+		if !cmd.Flags().Changed("output") {
+			settings.OutputFormat = formatTemplate
+		}
+	}
+	if cmd.Flags().Changed("columns") {
+		settings.OutputColumns = nil
+		for _, column := range columnsFlag {
+			if column = strings.TrimSpace(column); column != "" {
+				settings.OutputColumns = append(settings.OutputColumns, column)
+			}
+		}
+	}
+	if cmd.Flags().Changed("log-level") {
+		settings.LogLevel = strings.TrimSpace(logLevelFlag)
+	}
+	if cmd.Flags().Changed("log-format") {
+		settings.LogFormat = strings.TrimSpace(logFormatFlag)
+	}
+	if cmd.Flags().Changed("trace") {
+		settings.Trace = traceFlag
+	}
 
 	return nil
 }
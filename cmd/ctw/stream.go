@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/searchquery"
 	stream "github.com/0dayfall/ctw/internal/tweet/filteredstream"
 	"github.com/spf13/cobra"
 )
@@ -13,7 +15,10 @@ func init() {
 }
 
 func newStreamCommand() *cobra.Command {
-	var fieldPairs []string
+	var (
+		fieldOpts  *fieldFlags
+		fieldPairs []string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "stream",
@@ -24,10 +29,13 @@ func newStreamCommand() *cobra.Command {
 				ctx = context.Background()
 			}
 
-			fields, err := parseKeyValuePairs(fieldPairs)
+			params, err := parseKeyValuePairs(fieldPairs)
 			if err != nil {
 				return err
 			}
+			if err := fieldOpts.apply(params); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
@@ -35,12 +43,12 @@ func newStreamCommand() *cobra.Command {
 			}
 
 			service := stream.NewService(c)
-			response, rateLimits, err := service.Stream(ctx, fields)
+			response, rateLimits, err := service.Stream(ctx, params)
 			if err != nil {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -51,6 +59,8 @@ func newStreamCommand() *cobra.Command {
 	cmd.Flags().StringArrayVar(&fieldPairs, "field", nil, "Query parameter to include in the request (key=value)")
 	cmd.AddCommand(newStreamRulesCommand())
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+
 	return cmd
 }
 
@@ -63,15 +73,17 @@ func newStreamRulesCommand() *cobra.Command {
 	rulesCmd.AddCommand(newStreamRulesAddCommand())
 	rulesCmd.AddCommand(newStreamRulesListCommand())
 	rulesCmd.AddCommand(newStreamRulesDeleteCommand())
+	rulesCmd.AddCommand(newStreamRulesApplyCommand())
 
 	return rulesCmd
 }
 
 func newStreamRulesAddCommand() *cobra.Command {
 	var (
-		value string
-		tag   string
-		dry   bool
+		value    string
+		tag      string
+		dry      bool
+		lintOpts *lintFlag
 	)
 
 	cmd := &cobra.Command{
@@ -81,6 +93,9 @@ func newStreamRulesAddCommand() *cobra.Command {
 			if value == "" {
 				return errors.New("rule value is required")
 			}
+			if err := lintOpts.check(value); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if ctx == nil {
@@ -103,7 +118,7 @@ func newStreamRulesAddCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -114,6 +129,7 @@ func newStreamRulesAddCommand() *cobra.Command {
 	cmd.Flags().StringVar(&value, "value", "", "Rule value to add (e.g. 'cats has:images')")
 	cmd.Flags().StringVar(&tag, "tag", "", "Optional rule tag")
 	cmd.Flags().BoolVar(&dry, "dry-run", false, "Send the request as a dry run to validate the rule")
+	lintOpts = addLintFlag(cmd, searchquery.EndpointStream)
 
 	return cmd
 }
@@ -139,7 +155,7 @@ func newStreamRulesListCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -182,7 +198,7 @@ func newStreamRulesDeleteCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
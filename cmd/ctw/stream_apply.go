@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/searchquery"
+	stream "github.com/0dayfall/ctw/internal/tweet/filteredstream"
+	"github.com/spf13/cobra"
+)
+
+func newStreamRulesApplyCommand() *cobra.Command {
+	var (
+		keywords    []string
+		keywordFile string
+		filters     string
+		tagPrefix   string
+		maxLength   int
+		maxRules    int
+		dry         bool
+		lintOpts    *lintFlag
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Pack a keyword list into as few stream rules as possible and sync them",
+		Long: `Pack keywords into the fewest filtered stream rules that fit the rule length
+and rule count limits, and make the stream's rules match.
+
+Each rule ORs as many keywords as fit and ends with --filter, e.g.
+  (golang OR "machine learning" OR #rust) lang:en -is:retweet
+A --keyword is kept as written: --keyword "machine learning" matches tweets
+with both words anywhere, and --keyword '"machine learning"' the exact
+phrase. In --keyword-file, lines of plain words separated by spaces become an
+exact phrase; other lines, such as #hashtags, @mentions or (several terms),
+are kept as written.
+
+Rules are tagged <tag-prefix>-1, <tag-prefix>-2, ... Applying again keeps the
+rules that did not change, deletes the other rules with the prefix and adds
+the new ones; rules with other tags are left alone and count against the
+limit. The output lists each rule's keywords so matches can be mapped back.
+
+The limits come from the configured tier (pro when unset). Keyword files hold
+one entry per line; blank lines and lines starting with "# " are skipped.
+
+Examples:
+  ctw stream rules apply --keyword-file brands.txt --filter "lang:en -is:retweet"
+  ctw stream rules apply --keyword golang --keyword '"machine learning"' --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, err := collectKeywords(keywords, keywordFile)
+			if err != nil {
+				return err
+			}
+			if len(all) == 0 {
+				return errors.New("at least one keyword is required (use --keyword or --keyword-file)")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			result, rateLimits, err := applyPackedRules(ctx, stream.NewService(c), all, searchquery.PackOptions{
+				Filters:   filters,
+				Tier:      resolvedSettings.Tier,
+				MaxLength: maxLength,
+				MaxRules:  maxRules,
+				TagPrefix: tagPrefix,
+			}, false, dry, lintOpts)
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+
+			if err := printOutput(result); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&keywords, "keyword", nil, "Keyword or query fragment to match, as written (can be specified multiple times)")
+	cmd.Flags().StringVar(&keywordFile, "keyword-file", "", "File with one keyword or exact phrase per line (- for stdin)")
+	cmd.Flags().StringVar(&filters, "filter", "", "Operators added to every rule, e.g. 'lang:en -is:retweet'")
+	cmd.Flags().StringVar(&tagPrefix, "tag-prefix", searchquery.DefaultTagPrefix, "Tag prefix of the managed rules")
+	cmd.Flags().IntVar(&maxLength, "max-length", 0, "Rule length limit (default: the tier's limit)")
+	cmd.Flags().IntVar(&maxRules, "max-rules", 0, "Rule count limit, including unmanaged rules (default: the tier's limit)")
+	cmd.Flags().BoolVar(&dry, "dry-run", false, "Print the packed rules and changes without applying them")
+	lintOpts = addLintFlag(cmd, searchquery.EndpointStream)
+
+	return cmd
+}
+
+// ruleApplyResult reports what applyPackedRules changed.
+type ruleApplyResult struct {
+	Rules   []searchquery.PackedRule `json:"rules"`
+	Kept    int                      `json:"kept"`
+	Added   int                      `json:"added"`
+	Deleted int                      `json:"deleted"`
+	DryRun  bool                     `json:"dry_run,omitempty"`
+}
+
+// collectKeywords merges --keyword values with the entries of --keyword-file.
+// A --keyword of several words keeps matching the words anywhere in a tweet,
+// as a rule written by hand would; only keyword file lines become exact
+// phrases.
+func collectKeywords(keywords []string, path string) ([]string, error) {
+	var all []string
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			all = append(all, searchquery.AllWords(keyword))
+		}
+	}
+	if path != "" {
+		lines, err := readKeywordLines(path)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, lines...)
+	}
+	return all, nil
+}
+
+// applyPackedRules packs keywords and syncs the stream's rules with the
+// result. Rules tagged with the prefix that are still wanted are kept, the
+// others deleted, and missing ones added. With exclusive set every other rule
+// is deleted too; otherwise other rules stay and count against the limit.
+func applyPackedRules(ctx context.Context, service *stream.Service, keywords []string, opts searchquery.PackOptions, exclusive, dryRun bool, lintOpts *lintFlag) (ruleApplyResult, client.RateLimitSnapshot, error) {
+	if opts.TagPrefix == "" {
+		opts.TagPrefix = searchquery.DefaultTagPrefix
+	}
+	existing, rateLimits, err := service.GetRules(ctx)
+	if err != nil {
+		return ruleApplyResult{}, rateLimits, fmt.Errorf("failed to get existing rules: %w", err)
+	}
+
+	managed := func(rule stream.RuleData) bool {
+		return exclusive || strings.HasPrefix(rule.Tag, opts.TagPrefix+"-")
+	}
+	limit := opts.MaxRules
+	if limit <= 0 {
+		limit = searchquery.MaxRules(opts.Tier)
+	}
+	for _, rule := range existing.Data {
+		if !managed(rule) {
+			limit--
+		}
+	}
+	if limit <= 0 {
+		return ruleApplyResult{}, rateLimits, errors.New("no rules left: other rules already use the whole rule limit")
+	}
+	opts.MaxRules = limit
+
+	rules, err := searchquery.Pack(keywords, opts)
+	if err != nil {
+		return ruleApplyResult{}, rateLimits, err
+	}
+	for _, rule := range rules {
+		if err := lintOpts.check(rule.Value); err != nil {
+			return ruleApplyResult{}, rateLimits, fmt.Errorf("rule %s: %w", rule.Tag, err)
+		}
+	}
+
+	wanted := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		wanted[rule.Tag+"\x00"+rule.Value] = true
+	}
+	result := ruleApplyResult{Rules: rules, DryRun: dryRun}
+	var stale []string
+	for _, rule := range existing.Data {
+		if !managed(rule) {
+			continue
+		}
+		key := rule.Tag + "\x00" + rule.Value
+		if wanted[key] {
+			delete(wanted, key)
+			result.Kept++
+			continue
+		}
+		stale = append(stale, rule.ID)
+	}
+	var adds []stream.Add
+	for _, rule := range rules {
+		if wanted[rule.Tag+"\x00"+rule.Value] {
+			adds = append(adds, stream.Add{Value: rule.Value, Tag: rule.Tag})
+		}
+	}
+	result.Added, result.Deleted = len(adds), len(stale)
+	if dryRun {
+		return result, rateLimits, nil
+	}
+
+	// Delete first so the new rules fit under the rule limit.
+	if len(stale) > 0 {
+		if _, rateLimits, err = service.DeleteRule(ctx, stream.CreateDeleteIdCommand(stale), false); err != nil {
+			return result, rateLimits, fmt.Errorf("failed to delete rules: %w", err)
+		}
+	}
+	if len(adds) > 0 {
+		if _, rateLimits, err = service.AddRule(ctx, stream.AddCommand{Add: adds}, false); err != nil {
+			return result, rateLimits, fmt.Errorf("failed to add rules: %w", err)
+		}
+	}
+	logger.Info("applied stream rules", "rules", len(rules), "kept", result.Kept, "added", result.Added, "deleted", result.Deleted)
+	return result, rateLimits, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/handles"
+	"github.com/spf13/cobra"
+)
+
+// targetFlags holds the flags naming the users a bulk action applies to:
+// ids, a single --username, and files of ids or usernames.
+type targetFlags struct {
+	idFlag        string
+	ids           []string
+	username      string
+	idsFile       string
+	usernamesFile string
+}
+
+func addTargetFlags(cmd *cobra.Command, idFlag, idUsage string) *targetFlags {
+	t := &targetFlags{idFlag: idFlag}
+	cmd.Flags().StringSliceVar(&t.ids, idFlag, nil, idUsage+" (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&t.username, "username", "", "Username of the user, resolved to an id")
+	cmd.Flags().StringVar(&t.idsFile, "ids-file", "", "File with one user id per line (- for stdin)")
+	cmd.Flags().StringVar(&t.usernamesFile, "usernames-file", "", "File with one username per line (- for stdin)")
+	return t
+}
+
+// bulk reports whether the targets came from a file, in which case callers
+// print one result per user instead of the raw API response.
+func (t *targetFlags) bulk() bool {
+	return t.idsFile != "" || t.usernamesFile != ""
+}
+
+// userTarget is one user named on the command line. Error is set when a
+// username could not be resolved.
+type userTarget struct {
+	UserID   string
+	Username string
+	Error    string
+}
+
+// resolve returns the targets in flag order: ids, ids file, username,
+// usernames file. Ids may be @handles; all handles are resolved together in
+// batches through the handle cache, and unknown ones are marked as failed.
+func (t *targetFlags) resolve(ctx context.Context, c *client.Client) ([]userTarget, error) {
+	var targets []userTarget
+
+	ids := t.ids
+	if t.idsFile != "" {
+		lines, err := readLinesFile(t.idsFile)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, lines...)
+	}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if handles.IsHandle(id) {
+			targets = append(targets, userTarget{Username: handles.Normalize(id)})
+			continue
+		}
+		targets = append(targets, userTarget{UserID: id})
+	}
+
+	if username := handles.Normalize(t.username); username != "" {
+		targets = append(targets, userTarget{Username: username})
+	}
+
+	if t.usernamesFile != "" {
+		usernames, err := readLinesFile(t.usernamesFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, username := range usernames {
+			targets = append(targets, userTarget{Username: handles.Normalize(username)})
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("provide --%s, --username, --ids-file or --usernames-file", t.idFlag)
+	}
+
+	var usernames []string
+	for _, target := range targets {
+		if target.UserID == "" {
+			usernames = append(usernames, target.Username)
+		}
+	}
+	if len(usernames) == 0 {
+		return targets, nil
+	}
+
+	resolved, err := resolveHandles(ctx, c, usernames)
+	if err != nil {
+		return nil, err
+	}
+	for i := range targets {
+		if targets[i].UserID != "" {
+			continue
+		}
+		if id, ok := resolved[targets[i].Username]; ok {
+			targets[i].UserID = id
+		} else {
+			targets[i].Error = "user not found"
+		}
+	}
+	return targets, nil
+}
+
+// applyToTargets calls apply with the index of every resolvable target and
+// records per-user failures in errs, so one bad account does not abort a
+// sweep. Rate-limit and auth errors stop the run because every later call
+// would fail the same way; the targets left are then marked as not attempted
+// and the error is returned along with errs.
+func applyToTargets(targets []userTarget, apply func(i int) (client.RateLimitSnapshot, error)) (errs []string, failed int, rateLimits client.RateLimitSnapshot, err error) {
+	errs = make([]string, len(targets))
+	for i, target := range targets {
+		if target.Error != "" {
+			errs[i] = target.Error
+			failed++
+			continue
+		}
+		if err != nil {
+			errs[i] = notAttempted(err)
+			failed++
+			continue
+		}
+		limits, applyErr := apply(i)
+		rateLimits = limits
+		if applyErr != nil {
+			if client.IsRateLimited(applyErr) || client.IsAuth(applyErr) {
+				err = applyErr
+			}
+			errs[i] = applyErr.Error()
+			failed++
+		}
+	}
+	return errs, failed, rateLimits, err
+}
+
+// notAttempted is the error recorded for the items of a bulk action that
+// were skipped after err stopped it.
+func notAttempted(err error) string {
+	if client.IsRateLimited(err) {
+		return "not attempted: rate limited"
+	}
+	return "not attempted: unauthorized"
+}
+
+// errTargetsFailed summarises a partially applied bulk action.
+func errTargetsFailed(failed, total int) error {
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d users failed", failed, total)
+}
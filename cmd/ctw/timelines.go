@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/0dayfall/ctw/internal/fields"
 	"github.com/0dayfall/ctw/internal/tweet/timelines"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +30,9 @@ func newTimelinesCommand() *cobra.Command {
 
 func newTimelinesUserCommand() *cobra.Command {
 	var (
+		me         *bool
+		fieldOpts  *fieldFlags
+		storeOpts  *storeFlag
 		userID     string
 		paramsFlag []string
 	)
@@ -37,8 +41,8 @@ func newTimelinesUserCommand() *cobra.Command {
 		Use:   "user",
 		Short: "Get tweets posted by a user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
+			if strings.TrimSpace(userID) == "" && !*me {
+				return errors.New("--user-id or --me is required")
 			}
 
 			ctx := cmd.Context()
@@ -50,11 +54,17 @@ func newTimelinesUserCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("parse params: %w", err)
 			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := timelines.NewService(c)
 			response, rateLimits, err := service.GetUserTweets(ctx, userID, queryParams)
@@ -63,7 +73,11 @@ func newTimelinesUserCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := storeOpts.save(ctx, "timelines user: "+userID, response); err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -71,14 +85,21 @@ func newTimelinesUserCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+	storeOpts = addStoreFlag(cmd)
+
 	return cmd
 }
 
 func newTimelinesMentionsCommand() *cobra.Command {
 	var (
+		me         *bool
+		fieldOpts  *fieldFlags
+		storeOpts  *storeFlag
 		userID     string
 		paramsFlag []string
 	)
@@ -87,8 +108,8 @@ func newTimelinesMentionsCommand() *cobra.Command {
 		Use:   "mentions",
 		Short: "Get tweets that mention a user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
+			if strings.TrimSpace(userID) == "" && !*me {
+				return errors.New("--user-id or --me is required")
 			}
 
 			ctx := cmd.Context()
@@ -100,11 +121,17 @@ func newTimelinesMentionsCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("parse params: %w", err)
 			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := timelines.NewService(c)
 			response, rateLimits, err := service.GetUserMentions(ctx, userID, queryParams)
@@ -113,7 +140,11 @@ func newTimelinesMentionsCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := storeOpts.save(ctx, "timelines mentions: "+userID, response); err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -121,14 +152,21 @@ func newTimelinesMentionsCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the user")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+	storeOpts = addStoreFlag(cmd)
+
 	return cmd
 }
 
 func newTimelinesHomeCommand() *cobra.Command {
 	var (
+		me         *bool
+		fieldOpts  *fieldFlags
+		storeOpts  *storeFlag
 		userID     string
 		paramsFlag []string
 	)
@@ -137,9 +175,6 @@ func newTimelinesHomeCommand() *cobra.Command {
 		Use:   "home",
 		Short: "Get reverse chronological home timeline for authenticated user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(userID) == "" {
-				return errors.New("--user-id is required")
-			}
 
 			ctx := cmd.Context()
 			if ctx == nil {
@@ -150,11 +185,17 @@ func newTimelinesHomeCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("parse params: %w", err)
 			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
 
 			service := timelines.NewService(c)
 			response, rateLimits, err := service.GetReverseChronological(ctx, userID, queryParams)
@@ -163,7 +204,11 @@ func newTimelinesHomeCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := storeOpts.save(ctx, "timelines home: "+userID, response); err != nil {
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -171,8 +216,12 @@ func newTimelinesHomeCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "user-id", "", "ID of the authenticated user")
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the authenticated user (looked up from the token when omitted)")
+	me = addMeFlag(cmd, "user-id")
 	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+	storeOpts = addStoreFlag(cmd)
+
 	return cmd
 }
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/model"
 	"github.com/0dayfall/ctw/internal/tweet/lookup"
 	publish "github.com/0dayfall/ctw/internal/tweet/publish"
 	"github.com/spf13/cobra"
@@ -26,6 +28,10 @@ func newTweetsCommand() *cobra.Command {
 	cmd.AddCommand(newTweetsCreateCommand())
 	cmd.AddCommand(newTweetsDeleteCommand())
 	cmd.AddCommand(newTweetsGetCommand())
+	cmd.AddCommand(newTweetsConversationCommand())
+	cmd.AddCommand(newTweetsEngagementCommand())
+	cmd.AddCommand(newTweetsHideReplyCommand())
+	cmd.AddCommand(newTweetsUnhideReplyCommand())
 
 	return cmd
 }
@@ -35,6 +41,7 @@ func newTweetsCreateCommand() *cobra.Command {
 		text     string
 		filePath string
 		mediaIDs string
+		replyTo  string
 	)
 
 	cmd := &cobra.Command{
@@ -79,6 +86,9 @@ func newTweetsCreateCommand() *cobra.Command {
 				}
 				req.Media = &publish.Media{MediaIDs: ids}
 			}
+			if replyTo = strings.TrimSpace(replyTo); replyTo != "" {
+				req.Reply = &publish.Reply{InReplyToTweetID: replyTo}
+			}
 
 			service := publish.NewService(c)
 			response, rateLimits, err := service.CreateTweet(ctx, req)
@@ -86,7 +96,7 @@ func newTweetsCreateCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -97,6 +107,7 @@ func newTweetsCreateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&text, "text", "", "Tweet text content")
 	cmd.Flags().StringVar(&filePath, "file", "", "Path to file containing tweet text")
 	cmd.Flags().StringVar(&mediaIDs, "media-ids", "", "Comma-separated media IDs from media upload")
+	cmd.Flags().StringVar(&replyTo, "reply-to", "", "ID of the tweet to reply to")
 
 	return cmd
 }
@@ -128,7 +139,7 @@ func newTweetsDeleteCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -143,20 +154,74 @@ func newTweetsDeleteCommand() *cobra.Command {
 
 func newTweetsGetCommand() *cobra.Command {
 	var (
-		tweetID    string
-		tweetIDs   string
-		paramsFlag []string
+		fieldOpts     *fieldFlags
+		tweetID       string
+		tweetIDs      string
+		idsFile       string
+		concurrency   int
+		missingReport string
+		paramsFlag    []string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Fetch one or more tweets by ID",
+		Long: `Fetch tweets by ID or URL, e.g. https://x.com/user/status/123.
+
+Lists longer than 100 IDs are split into 100-ID requests sent --concurrency at
+a time and paced against the rate limit; a request that is rate limited
+anyway waits for the window to reset and is retried. The responses' tweets,
+includes and errors are merged into one result.
+
+IDs the API did not return are reported separately: one warning per tweet
+that was deleted (not_found), belongs to a protected account (protected) or
+is otherwise unavailable, or, with --missing-report, one NDJSON line each in
+that file.
+
+Examples:
+  ctw tweets get --id https://x.com/golang/status/1234567890
+  ctw tweets get --ids-file ids.txt --missing-report missing.ndjson
+  cat urls.txt | ctw tweets get --ids-file - -o ndjson`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if tweetID == "" && tweetIDs == "" {
-				return errors.New("provide --id or --ids")
+			sources := 0
+			for _, set := range []bool{tweetID != "", tweetIDs != "", idsFile != ""} {
+				if set {
+					sources++
+				}
 			}
-			if tweetID != "" && tweetIDs != "" {
-				return errors.New("use either --id or --ids, not both")
+			if sources == 0 {
+				return errors.New("provide --id, --ids or --ids-file")
+			}
+			if sources > 1 {
+				return errors.New("use only one of --id, --ids and --ids-file")
+			}
+
+			var inputs []string
+			switch {
+			case tweetID != "":
+				inputs = []string{tweetID}
+			case tweetIDs != "":
+				inputs = strings.Split(tweetIDs, ",")
+			default:
+				lines, err := readRawLines(idsFile)
+				if err != nil {
+					return err
+				}
+				inputs = lines
+			}
+			var ids []string
+			for _, input := range inputs {
+				if strings.TrimSpace(input) == "" {
+					continue
+				}
+				id, err := lookup.ParseTweetID(input)
+				if err != nil {
+					return err
+				}
+				ids = append(ids, id)
+			}
+			if len(ids) == 0 {
+				return errors.New("no tweet IDs given")
 			}
 
 			ctx := cmd.Context()
@@ -168,6 +233,9 @@ func newTweetsGetCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("parse params: %w", err)
 			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
@@ -175,26 +243,26 @@ func newTweetsGetCommand() *cobra.Command {
 			}
 
 			service := lookup.NewService(c)
+			service.Concurrency = concurrency
 
 			var (
-				response   lookup.TweetLookupResponse
+				response   model.TweetsResponse
 				rateLimits client.RateLimitSnapshot
 			)
 
 			if tweetID != "" {
-				response, rateLimits, err = service.GetTweet(ctx, tweetID, queryParams)
+				response, rateLimits, err = service.GetTweet(ctx, ids[0], queryParams)
 			} else {
-				idList := strings.Split(tweetIDs, ",")
-				for i, id := range idList {
-					idList[i] = strings.TrimSpace(id)
-				}
-				response, rateLimits, err = service.GetTweets(ctx, idList, queryParams)
+				response, rateLimits, err = service.GetTweets(ctx, ids, queryParams)
 			}
 			if err != nil {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			if err := reportMissingTweets(lookup.MissingTweets(ids, response), missingReport); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -202,9 +270,42 @@ func newTweetsGetCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&tweetID, "id", "", "Single tweet ID to fetch")
-	cmd.Flags().StringVar(&tweetIDs, "ids", "", "Comma-separated list of tweet IDs")
+	cmd.Flags().StringVar(&tweetID, "id", "", "Single tweet ID or URL to fetch")
+	cmd.Flags().StringVar(&tweetIDs, "ids", "", "Comma-separated list of tweet IDs or URLs")
+	cmd.Flags().StringVar(&idsFile, "ids-file", "", "File with one tweet ID or URL per line (- for stdin)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", lookup.DefaultConcurrency, "Maximum 100-ID requests in flight")
+	cmd.Flags().StringVar(&missingReport, "missing-report", "", "Write IDs that were not returned to this file as NDJSON")
 	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
 
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+
 	return cmd
 }
+
+// reportMissingTweets logs each missing tweet, or writes them to path as
+// NDJSON when it is set.
+func reportMissingTweets(missing []lookup.Missing, path string) error {
+	if path == "" {
+		for _, m := range missing {
+			logger.Warn("tweet not returned", "id", m.ID, "reason", m.Reason, "detail", m.Detail)
+		}
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("open missing report: %w", err)
+	}
+	defer f.Close()
+	rows := make([]any, len(missing))
+	for i, m := range missing {
+		rows[i] = m
+	}
+	if err := writeNDJSON(f, rows); err != nil {
+		return fmt.Errorf("write missing report: %w", err)
+	}
+	if len(missing) > 0 {
+		logger.Warn("tweets not returned", "count", len(missing), "report", path)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/tweet/conversation"
+	"github.com/0dayfall/ctw/internal/tweet/lookup"
+	"github.com/spf13/cobra"
+)
+
+func newTweetsConversationCommand() *cobra.Command {
+	var (
+		fieldOpts   *fieldFlags
+		tweetID     string
+		fullArchive bool
+		maxPages    int
+		text        bool
+		paramsFlag  []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "conversation",
+		Short: "Reconstruct the reply thread a tweet belongs to",
+		Long: `Fetch the whole conversation a tweet is part of and print it as a reply tree.
+
+The tweet is looked up for its conversation_id, then conversation_id:<id> is
+searched page by page: the last seven days with recent search, or the full
+archive with --all (Pro or Enterprise access). Tweets that replies point to
+but the search did not return, such as the root of an older thread, are
+looked up by ID. Parents that are deleted or protected appear as unavailable
+placeholders and are listed under "missing".
+
+The output is nested JSON: each tweet with its author's username and its
+replies, oldest first. --text prints one line per tweet instead, indented
+under the tweet it replies to. --param is added to the search requests only,
+e.g. --param start_time=2024-01-01T00:00:00Z.
+
+Examples:
+  ctw tweets conversation --id https://x.com/golang/status/1234567890 --text
+  ctw tweets conversation --id 1234567890 --all --max-pages 5 --preset analytics`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tweetID == "" {
+				return errors.New("--id is required")
+			}
+			id, err := lookup.ParseTweetID(tweetID)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			searchParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return fmt.Errorf("parse params: %w", err)
+			}
+			fieldParams := make(map[string]string)
+			if err := fieldOpts.apply(fieldParams); err != nil {
+				return err
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			service := conversation.NewService(c)
+			thread, rateLimits, err := service.Fetch(ctx, id, conversation.Options{
+				FullArchive:  fullArchive,
+				MaxPages:     maxPages,
+				Fields:       fieldParams,
+				SearchParams: searchParams,
+			})
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+
+			for _, m := range thread.Missing {
+				logger.Warn("parent tweet not returned", "id", m.ID, "reason", m.Reason)
+			}
+			if thread.Truncated {
+				logger.Warn("conversation truncated at --max-pages", "max_pages", maxPages)
+			}
+
+			if text {
+				fmt.Fprint(os.Stdout, thread.Indented())
+			} else if err := printOutput(thread); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tweetID, "id", "", "ID or URL of any tweet in the conversation")
+	cmd.Flags().BoolVar(&fullArchive, "all", false, "Search the full archive (/2/tweets/search/all) instead of the last seven days")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop the search after this many pages (0 fetches every page)")
+	cmd.Flags().BoolVar(&text, "text", false, "Print the thread as indented text instead of JSON")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional search parameters in key=value form (repeatable)")
+
+	fieldOpts = addFieldFlags(cmd, fields.Tweets)
+
+	return cmd
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/tweet/conversation"
+	"github.com/0dayfall/ctw/internal/tweet/lookup"
+	"github.com/0dayfall/ctw/internal/tweet/moderation"
+	publish "github.com/0dayfall/ctw/internal/tweet/publish"
+	"github.com/spf13/cobra"
+)
+
+// sweepResult reports one reply a moderation sweep matched.
+type sweepResult struct {
+	moderation.Candidate
+	Hidden bool   `json:"hidden"`
+	Error  string `json:"error,omitempty"`
+}
+
+func newTweetsUnhideReplyCommand() *cobra.Command {
+	var tweetID string
+
+	cmd := &cobra.Command{
+		Use:   "unhide-reply",
+		Short: "Unhide a reply to one of your conversations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setReplyHidden(cmd, tweetID, false)
+		},
+	}
+
+	cmd.Flags().StringVar(&tweetID, "id", "", "ID or URL of the reply to unhide")
+
+	return cmd
+}
+
+func newTweetsHideReplyCommand() *cobra.Command {
+	var (
+		tweetID     string
+		sweepID     string
+		blocks      []string
+		blocklist   string
+		fullArchive bool
+		maxPages    int
+		dry         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "hide-reply",
+		Short: "Hide replies to your conversations, one by ID or all matching a blocklist",
+		Long: `Hide a reply to a conversation the authenticated user started, or sweep a
+whole conversation for replies to hide.
+
+With --id the reply is hidden. With --sweep the conversation of that tweet is
+fetched as in tweets conversation, and every reply matching the blocklist is
+hidden. Blocklist entries come from --block and from --blocklist, one per
+line (blank lines and lines starting with "# " are skipped):
+  @handle        replies by that author
+  id:12345       replies by that user ID
+  anything else  a regular expression matched against the reply text,
+                 e.g. (?i)free (crypto|nft)
+
+Your own replies are never hidden. --dry-run lists the matches without hiding
+them. The output has one record per match with its reason and the outcome;
+the command exits non-zero when some replies could not be hidden. A rate
+limit or auth error stops the sweep, and the replies left are reported as
+not attempted.
+
+Hiding replies needs a user-context token.
+
+Examples:
+  ctw tweets hide-reply --id https://x.com/acme/status/1234567890
+  ctw tweets hide-reply --sweep 1234567890 --blocklist blocklist.txt --dry-run
+  ctw tweets hide-reply --sweep 1234567890 --block @spammer --block '(?i)airdrop'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (tweetID == "") == (sweepID == "") {
+				return errors.New("provide either --id or --sweep")
+			}
+			if tweetID != "" {
+				if len(blocks) > 0 || blocklist != "" || dry {
+					return errors.New("--block, --blocklist and --dry-run need --sweep")
+				}
+				return setReplyHidden(cmd, tweetID, true)
+			}
+
+			entries := blocks
+			if blocklist != "" {
+				lines, err := readKeywordLines(blocklist)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, lines...)
+			}
+			rules, err := moderation.ParseBlocklist(entries)
+			if err != nil {
+				return err
+			}
+			if rules.Empty() {
+				return errors.New("the blocklist is empty (use --block or --blocklist)")
+			}
+			id, err := lookup.ParseTweetID(sweepID)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			thread, rateLimits, err := conversation.NewService(c).Fetch(ctx, id, conversation.Options{
+				FullArchive: fullArchive,
+				MaxPages:    maxPages,
+			})
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+			if thread.Truncated {
+				logger.Warn("conversation truncated at --max-pages; later replies were not checked", "max_pages", maxPages)
+			}
+
+			candidates := moderation.Candidates(thread, rules)
+			results := make([]sweepResult, len(candidates))
+			var (
+				failed  int
+				stopErr error
+			)
+			service := publish.NewService(c)
+			for i, candidate := range candidates {
+				results[i].Candidate = candidate
+				if dry {
+					continue
+				}
+				if stopErr != nil {
+					results[i].Error = notAttempted(stopErr)
+					failed++
+					continue
+				}
+				response, limits, err := service.HideReply(ctx, candidate.ID, true)
+				rateLimits = limits
+				if err != nil {
+					if client.IsRateLimited(err) || client.IsAuth(err) {
+						stopErr = err
+					}
+					results[i].Error = err.Error()
+					failed++
+					continue
+				}
+				results[i].Hidden = response.Data.Hidden
+			}
+			logger.Info("moderation sweep", "conversation_id", thread.ConversationID, "tweets", thread.TweetCount, "matched", len(candidates), "failed", failed, "dry_run", dry)
+
+			if err := printOutput(struct {
+				Data []sweepResult `json:"data"`
+			}{Data: results}); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+
+			if stopErr != nil {
+				return stopErr
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d replies could not be hidden", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tweetID, "id", "", "ID or URL of the reply to hide")
+	cmd.Flags().StringVar(&sweepID, "sweep", "", "ID or URL of a tweet whose conversation to sweep")
+	cmd.Flags().StringArrayVar(&blocks, "block", nil, "Blocklist entry: @handle, id:<user id> or a regular expression (repeatable)")
+	cmd.Flags().StringVar(&blocklist, "blocklist", "", "File with one blocklist entry per line (- for stdin)")
+	cmd.Flags().BoolVar(&fullArchive, "all", false, "Search the full archive for the conversation's replies")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop the reply search after this many pages (0 fetches every page)")
+	cmd.Flags().BoolVar(&dry, "dry-run", false, "List the matching replies without hiding them")
+
+	return cmd
+}
+
+// setReplyHidden hides or unhides the reply given by --id.
+func setReplyHidden(cmd *cobra.Command, tweetID string, hidden bool) error {
+	if tweetID == "" {
+		return errors.New("--id is required")
+	}
+	id, err := lookup.ParseTweetID(tweetID)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c, err := newClientFromFlags()
+	if err != nil {
+		return err
+	}
+
+	response, rateLimits, err := publish.NewService(c).HideReply(ctx, id, hidden)
+	if err != nil {
+		return err
+	}
+
+	if err := printOutput(response); err != nil {
+		return err
+	}
+	printRateLimits(rateLimits)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/0dayfall/ctw/internal/fields"
 	lookupsvc "github.com/0dayfall/ctw/internal/users/lookup"
 	"github.com/spf13/cobra"
 )
@@ -23,12 +24,17 @@ func newUsersCommand() *cobra.Command {
 	cmd.AddCommand(newUsersUnblockCommand())
 	cmd.AddCommand(newUsersFollowCommand())
 	cmd.AddCommand(newUsersUnfollowCommand())
+	cmd.AddCommand(newUsersMuteCommand("mute", "Mute users", (*lookupsvc.Service).Mute))
+	cmd.AddCommand(newUsersMuteCommand("unmute", "Unmute users", (*lookupsvc.Service).Unmute))
+	cmd.AddCommand(newUsersRelationListCommand("muted", "List the users a user has muted", (*lookupsvc.Service).ListMuting, (*lookupsvc.Service).AllMuting))
+	cmd.AddCommand(newUsersRelationListCommand("blocked", "List the users a user has blocked", (*lookupsvc.Service).ListBlocking, (*lookupsvc.Service).AllBlocking))
 
 	return cmd
 }
 
 func newUsersLookupCommand() *cobra.Command {
 	var (
+		fieldOpts  *fieldFlags
 		id         string
 		username   string
 		ids        []string
@@ -55,6 +61,9 @@ func newUsersLookupCommand() *cobra.Command {
 					params[k] = v
 				}
 			}
+			if err := fieldOpts.apply(params); err != nil {
+				return err
+			}
 
 			c, err := newClientFromFlags()
 			if err != nil {
@@ -70,7 +79,7 @@ func newUsersLookupCommand() *cobra.Command {
 					printRateLimits(rateLimits)
 					return err
 				}
-				if err := printJSON(response); err != nil {
+				if err := printOutput(response); err != nil {
 					return err
 				}
 				printRateLimits(rateLimits)
@@ -81,7 +90,7 @@ func newUsersLookupCommand() *cobra.Command {
 					printRateLimits(rateLimits)
 					return err
 				}
-				if err := printJSON(response); err != nil {
+				if err := printOutput(response); err != nil {
 					return err
 				}
 				printRateLimits(rateLimits)
@@ -92,7 +101,7 @@ func newUsersLookupCommand() *cobra.Command {
 					printRateLimits(rateLimits)
 					return err
 				}
-				if err := printJSON(response); err != nil {
+				if err := printOutput(response); err != nil {
 					return err
 				}
 				printRateLimits(rateLimits)
@@ -103,7 +112,7 @@ func newUsersLookupCommand() *cobra.Command {
 					printRateLimits(rateLimits)
 					return err
 				}
-				if err := printJSON(response); err != nil {
+				if err := printOutput(response); err != nil {
 					return err
 				}
 				printRateLimits(rateLimits)
@@ -120,11 +129,14 @@ func newUsersLookupCommand() *cobra.Command {
 	cmd.Flags().StringSliceVar(&usernames, "usernames", nil, "Comma-separated list of usernames to lookup")
 	cmd.Flags().StringArrayVar(&extraPairs, "param", nil, "Additional query parameter in key=value format")
 
+	fieldOpts = addFieldFlags(cmd, fields.Users)
+
 	return cmd
 }
 
 func newUsersBlockCommand() *cobra.Command {
 	var (
+		me       *bool
 		sourceID string
 		targetID string
 	)
@@ -133,8 +145,8 @@ func newUsersBlockCommand() *cobra.Command {
 		Use:   "block",
 		Short: "Block a user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if sourceID == "" || targetID == "" {
-				return errors.New("both --source-id and --target-id are required")
+			if targetID == "" {
+				return errors.New("--target-id is required")
 			}
 
 			ctx := cmd.Context()
@@ -146,6 +158,9 @@ func newUsersBlockCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &sourceID, &targetID); err != nil {
+				return err
+			}
 			service := lookupsvc.NewService(c)
 
 			response, rateLimits, err := service.Block(ctx, sourceID, targetID)
@@ -153,7 +168,7 @@ func newUsersBlockCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -161,14 +176,16 @@ func newUsersBlockCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id performing the block")
-	cmd.Flags().StringVar(&targetID, "target-id", "", "User id being blocked")
+	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id or @handle performing the block (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "source-id")
+	cmd.Flags().StringVar(&targetID, "target-id", "", "User id or @handle being blocked")
 
 	return cmd
 }
 
 func newUsersUnblockCommand() *cobra.Command {
 	var (
+		me       *bool
 		sourceID string
 		targetID string
 	)
@@ -177,8 +194,8 @@ func newUsersUnblockCommand() *cobra.Command {
 		Use:   "unblock",
 		Short: "Remove an existing block",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if sourceID == "" || targetID == "" {
-				return errors.New("both --source-id and --target-id are required")
+			if targetID == "" {
+				return errors.New("--target-id is required")
 			}
 
 			ctx := cmd.Context()
@@ -190,6 +207,9 @@ func newUsersUnblockCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &sourceID, &targetID); err != nil {
+				return err
+			}
 			service := lookupsvc.NewService(c)
 
 			response, rateLimits, err := service.Unblock(ctx, sourceID, targetID)
@@ -197,7 +217,7 @@ func newUsersUnblockCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -205,14 +225,16 @@ func newUsersUnblockCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id lifting the block")
-	cmd.Flags().StringVar(&targetID, "target-id", "", "User id being unblocked")
+	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id or @handle lifting the block (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "source-id")
+	cmd.Flags().StringVar(&targetID, "target-id", "", "User id or @handle being unblocked")
 
 	return cmd
 }
 
 func newUsersFollowCommand() *cobra.Command {
 	var (
+		me       *bool
 		sourceID string
 		targetID string
 	)
@@ -221,8 +243,8 @@ func newUsersFollowCommand() *cobra.Command {
 		Use:   "follow",
 		Short: "Follow a user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if sourceID == "" || targetID == "" {
-				return errors.New("both --source-id and --target-id are required")
+			if targetID == "" {
+				return errors.New("--target-id is required")
 			}
 
 			ctx := cmd.Context()
@@ -234,6 +256,9 @@ func newUsersFollowCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &sourceID, &targetID); err != nil {
+				return err
+			}
 			service := lookupsvc.NewService(c)
 
 			response, rateLimits, err := service.Follow(ctx, sourceID, targetID)
@@ -241,7 +266,7 @@ func newUsersFollowCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -249,14 +274,16 @@ func newUsersFollowCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id initiating the follow")
-	cmd.Flags().StringVar(&targetID, "target-id", "", "User id being followed")
+	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id or @handle initiating the follow (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "source-id")
+	cmd.Flags().StringVar(&targetID, "target-id", "", "User id or @handle being followed")
 
 	return cmd
 }
 
 func newUsersUnfollowCommand() *cobra.Command {
 	var (
+		me       *bool
 		sourceID string
 		targetID string
 	)
@@ -265,8 +292,8 @@ func newUsersUnfollowCommand() *cobra.Command {
 		Use:   "unfollow",
 		Short: "Stop following a user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if sourceID == "" || targetID == "" {
-				return errors.New("both --source-id and --target-id are required")
+			if targetID == "" {
+				return errors.New("--target-id is required")
 			}
 
 			ctx := cmd.Context()
@@ -278,6 +305,9 @@ func newUsersUnfollowCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := resolveUserFlags(ctx, c, *me, &sourceID, &targetID); err != nil {
+				return err
+			}
 			service := lookupsvc.NewService(c)
 
 			response, rateLimits, err := service.Unfollow(ctx, sourceID, targetID)
@@ -285,7 +315,7 @@ func newUsersUnfollowCommand() *cobra.Command {
 				return err
 			}
 
-			if err := printJSON(response); err != nil {
+			if err := printOutput(response); err != nil {
 				return err
 			}
 			printRateLimits(rateLimits)
@@ -293,8 +323,9 @@ func newUsersUnfollowCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id stopping the follow")
-	cmd.Flags().StringVar(&targetID, "target-id", "", "User id being unfollowed")
+	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id or @handle stopping the follow (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "source-id")
+	cmd.Flags().StringVar(&targetID, "target-id", "", "User id or @handle being unfollowed")
 
 	return cmd
 }
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/fields"
+	"github.com/0dayfall/ctw/internal/model"
+	lookupsvc "github.com/0dayfall/ctw/internal/users/lookup"
+	"github.com/spf13/cobra"
+)
+
+type relationshipFunc func(*lookupsvc.Service, context.Context, string, string) (lookupsvc.RelationshipResponse, client.RateLimitSnapshot, error)
+
+// muteResult reports the outcome of one user in a bulk mute or unmute.
+type muteResult struct {
+	UserID   string `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Muting   bool   `json:"muting"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newUsersMuteCommand(use, short string, change relationshipFunc) *cobra.Command {
+	var (
+		targets  *targetFlags
+		me       *bool
+		sourceID string
+	)
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+
+			if err := resolveUserFlags(ctx, c, *me, &sourceID); err != nil {
+				return err
+			}
+
+			users, err := targets.resolve(ctx, c)
+			if err != nil {
+				return err
+			}
+
+			service := lookupsvc.NewService(c)
+
+			if len(users) == 1 && !targets.bulk() {
+				if users[0].Error != "" {
+					return fmt.Errorf("@%s: %s", users[0].Username, users[0].Error)
+				}
+				response, rateLimits, err := change(service, ctx, sourceID, users[0].UserID)
+				if err != nil {
+					return err
+				}
+				if err := printOutput(response); err != nil {
+					return err
+				}
+				printRateLimits(rateLimits)
+				return nil
+			}
+
+			results := make([]muteResult, len(users))
+			errs, failed, rateLimits, applyErr := applyToTargets(users, func(i int) (client.RateLimitSnapshot, error) {
+				response, limits, err := change(service, ctx, sourceID, users[i].UserID)
+				results[i].Muting = response.Data.Muting
+				return limits, err
+			})
+			for i, user := range users {
+				results[i].UserID = user.UserID
+				results[i].Username = user.Username
+				results[i].Error = errs[i]
+			}
+
+			if err := printOutput(struct {
+				Data []muteResult `json:"data"`
+			}{Data: results}); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+
+			if applyErr != nil {
+				return applyErr
+			}
+			return errTargetsFailed(failed, len(results))
+		},
+	}
+
+	cmd.Flags().StringVar(&sourceID, "source-id", "", "User id or @handle performing the action (defaults to the authenticated user)")
+	me = addMeFlag(cmd, "source-id")
+	targets = addTargetFlags(cmd, "target-id", "User id or @handle to "+use)
+
+	return cmd
+}
+
+type (
+	relationPageFunc func(*lookupsvc.Service, context.Context, string, map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error)
+	relationAllFunc  func(*lookupsvc.Service, context.Context, string, map[string]string, int) (model.UsersResponse, client.RateLimitSnapshot, error)
+)
+
+func newUsersRelationListCommand(use, short string, page relationPageFunc, all relationAllFunc) *cobra.Command {
+	var (
+		fieldOpts  *fieldFlags
+		me         *bool
+		userID     string
+		username   string
+		fetchAll   bool
+		maxPages   int
+		paramsFlag []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if username = strings.TrimSpace(username); username != "" {
+				if userID != "" {
+					return errors.New("use either --user-id or --username, not both")
+				}
+				userID = "@" + strings.TrimPrefix(username, "@")
+			}
+			if userID == "" && !*me {
+				return errors.New("provide --user-id, --username or --me")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			queryParams, err := parseKeyValuePairs(paramsFlag)
+			if err != nil {
+				return fmt.Errorf("parse params: %w", err)
+			}
+			if err := fieldOpts.apply(queryParams); err != nil {
+				return err
+			}
+
+			c, err := newClientFromFlags()
+			if err != nil {
+				return err
+			}
+			if err := resolveUserFlags(ctx, c, *me, &userID); err != nil {
+				return err
+			}
+			service := lookupsvc.NewService(c)
+
+			var (
+				response   model.UsersResponse
+				rateLimits client.RateLimitSnapshot
+			)
+			if fetchAll {
+				response, rateLimits, err = all(service, ctx, userID, queryParams, maxPages)
+			} else {
+				response, rateLimits, err = page(service, ctx, userID, queryParams)
+			}
+			if err != nil {
+				printRateLimits(rateLimits)
+				return err
+			}
+
+			if err := printOutput(response); err != nil {
+				return err
+			}
+			printRateLimits(rateLimits)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&userID, "user-id", "", "ID or @handle of the user whose list to fetch")
+	cmd.Flags().StringVar(&username, "username", "", "Username of the user whose list to fetch")
+	me = addMeFlag(cmd, "user-id")
+	cmd.Flags().BoolVar(&fetchAll, "all", false, "Follow pagination and return every page")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Stop --all after this many pages (0 for no limit)")
+	cmd.Flags().StringSliceVar(&paramsFlag, "param", nil, "Additional query parameters in key=value form (repeatable)")
+
+	fieldOpts = addFieldFlags(cmd, fields.Users)
+
+	return cmd
+}
@@ -12,6 +12,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/columnar"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/searchquery"
 	stream "github.com/0dayfall/ctw/internal/tweet/filteredstream"
 	"github.com/spf13/cobra"
 )
@@ -22,11 +26,20 @@ func init() {
 
 func newWatchCommand() *cobra.Command {
 	var (
-		keywords   []string
-		autoSetup  bool
-		showUser   bool
-		showMeta   bool
-		jsonOutput bool
+		keywords    []string
+		keywordFile string
+		filters     string
+		autoSetup   bool
+		showUser    bool
+		showMeta    bool
+		jsonOutput  bool
+		metricsAddr string
+		maxSilence  time.Duration
+		storeOpts   *storeFlag
+		parquetDir  string
+		rollEvery   time.Duration
+		rollRows    int
+		lintOpts    *lintFlag
 	)
 
 	cmd := &cobra.Command{
@@ -44,11 +57,28 @@ Examples:
   # Auto-setup stream rules
   ctw watch --keyword "AI" --auto-setup
 
+  # Pack a long keyword list into as few rules as the tier allows
+  ctw watch --keyword-file brands.txt --filter "lang:en -is:retweet" --auto-setup
+
   # Show detailed information
-  ctw watch --keyword "bitcoin" --show-user --show-meta`,
+  ctw watch --keyword "bitcoin" --show-user --show-meta
+
+  # Expose Prometheus metrics and a /healthz probe for a systemd service
+  ctw watch --keyword "golang" --json --metrics-addr 127.0.0.1:9464
+
+  # Archive every matching tweet with its author and media
+  ctw watch --keyword "golang" --store tweets.db
+
+  # Write hourly Parquet files for an analytics pipeline
+  ctw watch --keyword "golang" --parquet-dir ./parquet --roll-every 1h`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			all, err := collectKeywords(keywords, keywordFile)
+			if err != nil {
+				return err
+			}
+			keywords = all
 			if len(keywords) == 0 {
-				return errors.New("at least one keyword is required (use --keyword)")
+				return errors.New("at least one keyword is required (use --keyword or --keyword-file)")
 			}
 
 			ctx, cancel := context.WithCancel(context.Background())
@@ -59,7 +89,7 @@ Examples:
 			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 			go func() {
 				<-sigChan
-				fmt.Fprintf(os.Stderr, "\n\n🛑 Stopping stream...\n")
+				logger.Info("stopping stream")
 				cancel()
 			}()
 
@@ -70,44 +100,70 @@ Examples:
 
 			service := stream.NewService(c)
 
-			// Auto-setup rules if requested
-			if autoSetup {
-				fmt.Fprintf(os.Stderr, "📝 Setting up stream rules...\n")
+			archive, err := storeOpts.open(ctx)
+			if err != nil {
+				return err
+			}
+			if archive != nil {
+				defer archive.Close()
+			}
 
-				// Clear existing rules first
-				existing, _, listErr := service.GetRules(ctx)
-				if listErr != nil {
-					return fmt.Errorf("failed to get existing rules: %w", listErr)
+			var parquetFiles *columnar.RollingWriter
+			if parquetDir != "" {
+				parquetFiles, err = columnar.NewRollingWriter(columnar.RollingOptions{Dir: parquetDir, Every: rollEvery, MaxRows: rollRows})
+				if err != nil {
+					return err
 				}
-
-				if len(existing.Data) > 0 {
-					ids := make([]string, len(existing.Data))
-					for i, rule := range existing.Data {
-						ids[i] = rule.ID
-					}
-					deleteCmd := stream.CreateDeleteIdCommand(ids)
-					if _, _, delErr := service.DeleteRule(ctx, deleteCmd, false); delErr != nil {
-						fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to delete existing rules: %v\n", delErr)
+				defer func() {
+					if err := parquetFiles.Close(); err != nil {
+						logger.Warn("failed to complete parquet file", "error", err)
 					}
+				}()
+				if rollEvery > 0 {
+					// Complete the file at the end of its period even when no
+					// further tweet arrives.
+					go func() {
+						ticker := time.NewTicker(min(rollEvery, time.Minute))
+						defer ticker.Stop()
+						for {
+							select {
+							case <-ctx.Done():
+								return
+							case <-ticker.C:
+								if err := parquetFiles.CloseExpired(); err != nil {
+									logger.Warn("failed to complete parquet file", "error", err)
+								}
+							}
+						}
+					}()
 				}
-				// Add new rules for each keyword
-				adds := make([]stream.Add, 0, len(keywords))
-				for _, keyword := range keywords {
-					adds = append(adds, stream.Add{
-						Value: keyword,
-						Tag:   fmt.Sprintf("watch_%s", strings.ReplaceAll(keyword, " ", "_")),
-					})
+			}
+
+			var watchStats *watchMetrics
+			if metricsAddr != "" {
+				watchStats = newWatchMetrics(maxSilence)
+				if err := watchStats.serve(ctx, metricsAddr); err != nil {
+					return err
 				}
+			}
 
-				addCmd := stream.AddCommand{Add: adds}
-				resp, _, err := service.AddRule(ctx, addCmd, false)
+			// Auto-setup rules if requested
+			var packed map[string]searchquery.PackedRule
+			if autoSetup {
+				logger.Info("setting up stream rules")
+				result, _, err := applyPackedRules(ctx, service, keywords, searchquery.PackOptions{
+					Filters:   filters,
+					Tier:      resolvedSettings.Tier,
+					TagPrefix: "watch",
+				}, true, false, lintOpts)
 				if err != nil {
-					return fmt.Errorf("failed to add rules: %w", err)
+					return err
 				}
 
-				fmt.Fprintf(os.Stderr, "✅ Added %d rule(s)\n", len(resp.Data))
-				for _, rule := range resp.Data {
-					fmt.Fprintf(os.Stderr, "   - %s (ID: %s)\n", rule.Value, rule.ID)
+				packed = make(map[string]searchquery.PackedRule, len(result.Rules))
+				for _, rule := range result.Rules {
+					packed[rule.Tag] = rule
+					logger.Info("stream rule", "tag", rule.Tag, "value", rule.Value, "keywords", len(rule.Keywords))
 				}
 			}
 
@@ -119,9 +175,14 @@ Examples:
 				fields["expansions"] = "author_id"
 				fields["user.fields"] = "name,username,created_at"
 			}
+			if archive != nil || parquetFiles != nil {
+				fields["tweet.fields"] += ",conversation_id,in_reply_to_user_id,public_metrics,entities,referenced_tweets,attachments"
+				fields["expansions"] = "author_id,attachments.media_keys"
+				fields["user.fields"] = "name,username,created_at,description,location,verified,public_metrics"
+				fields["media.fields"] = "type,url,preview_image_url,alt_text,width,height,duration_ms,variants"
+			}
 
-			fmt.Fprintf(os.Stderr, "\n🔴 Watching for keywords: %s\n", strings.Join(keywords, ", "))
-			fmt.Fprintf(os.Stderr, "Press Ctrl+C to stop\n\n")
+			logger.Info("watching for keywords; press Ctrl+C to stop", "keywords", strings.Join(keywords, ", "))
 
 			tweetCount := 0
 			startTime := time.Now()
@@ -140,8 +201,28 @@ Examples:
 
 			// Stream tweets with reconnect + backoff
 			for {
-				err = service.StreamReader(ctx, fields, func(tweet stream.StreamTweet, includes stream.StreamIncludes) error {
+				watchStats.observeConnected(true)
+				var rateLimits client.RateLimitSnapshot
+				rateLimits, err = service.StreamReader(ctx, fields, func(tweet model.Tweet, includes *model.Includes, rules []stream.MatchingRule) error {
+					handleStart := time.Now()
+					defer func() { watchStats.observeTweet(rules, time.Since(handleStart)) }()
+
 					tweetCount++
+					matched := matchedKeywords(packed, rules, tweet.Text)
+
+					if archive != nil {
+						// A full disk or locked database must not stop the stream.
+						if _, err := archive.SaveTweet(ctx, tweet, includes, "watch"); err != nil {
+							logger.Warn("failed to store tweet", "id", tweet.ID, "error", err)
+						}
+					}
+					if parquetFiles != nil {
+						row := columnar.FromTweet(tweet, includes)
+						row.MatchingRules = matchingRuleNames(rules)
+						if err := parquetFiles.Write(row); err != nil {
+							logger.Warn("failed to write parquet row", "id", tweet.ID, "error", err)
+						}
+					}
 
 					if jsonOutput {
 						if cmd.Flags().Changed("pretty") {
@@ -155,6 +236,7 @@ Examples:
 								Lang              string    `json:"lang,omitempty"`
 								Source            string    `json:"source,omitempty"`
 								PossiblySensitive bool      `json:"possibly_sensitive,omitempty"`
+								MatchedKeywords   []string  `json:"matched_keywords,omitempty"`
 							}
 							out := prettyTweet{
 								ID:                tweet.ID,
@@ -164,10 +246,11 @@ Examples:
 								Lang:              tweet.Lang,
 								Source:            tweet.Source,
 								PossiblySensitive: tweet.PossiblySensitive,
+								MatchedKeywords:   matched,
 							}
-							if showUser && len(includes.Users) > 0 {
-								out.AuthorUsername = includes.Users[0].Username
-								out.AuthorName = includes.Users[0].Name
+							if author, ok := includes.AuthorOf(tweet); showUser && ok {
+								out.AuthorUsername = author.Username
+								out.AuthorName = author.Name
 							}
 							payload, err := json.MarshalIndent(out, "", "  ")
 							if err != nil {
@@ -178,10 +261,12 @@ Examples:
 						}
 
 						type rawEvent struct {
-							Data     stream.StreamTweet    `json:"data"`
-							Includes stream.StreamIncludes `json:"includes,omitempty"`
+							Data            model.Tweet           `json:"data"`
+							Includes        *model.Includes       `json:"includes,omitempty"`
+							MatchingRules   []stream.MatchingRule `json:"matching_rules,omitempty"`
+							MatchedKeywords []string              `json:"matched_keywords,omitempty"`
 						}
-						payload, err := json.Marshal(rawEvent{Data: tweet, Includes: includes})
+						payload, err := json.Marshal(rawEvent{Data: tweet, Includes: includes, MatchingRules: rules, MatchedKeywords: matched})
 						if err != nil {
 							return err
 						}
@@ -195,14 +280,16 @@ Examples:
 					fmt.Printf("ID: %s\n", tweet.ID)
 					fmt.Printf("Time: %s\n", tweet.CreatedAt.Format(time.RFC3339))
 
-					if showUser && len(includes.Users) > 0 {
-						user := includes.Users[0]
-						fmt.Printf("Author: @%s (%s)\n", user.Username, user.Name)
+					if author, ok := includes.AuthorOf(tweet); showUser && ok {
+						fmt.Printf("Author: @%s (%s)\n", author.Username, author.Name)
 					} else {
 						fmt.Printf("Author ID: %s\n", tweet.AuthorID)
 					}
 
 					fmt.Printf("Language: %s\n", tweet.Lang)
+					if len(matched) > 0 {
+						fmt.Printf("Keywords: %s\n", strings.Join(matched, ", "))
+					}
 					if tweet.PossiblySensitive {
 						fmt.Printf("⚠️  Possibly Sensitive\n")
 					}
@@ -216,6 +303,8 @@ Examples:
 
 					return nil
 				})
+				watchStats.observeConnected(false)
+				watchStats.observeRateLimits(rateLimits)
 
 				if err == nil {
 					lastDisconnect = "EOF"
@@ -235,11 +324,13 @@ Examples:
 					break
 				}
 
-				fmt.Fprintf(os.Stderr, "disconnected: %s\n", lastDisconnect)
+				logger.Warn("stream disconnected", "reason", lastDisconnect)
+				watchStats.observeDisconnect(err)
 				reconnects++
 
 				wait := min(backoff, maxBackoff)
-				fmt.Fprintf(os.Stderr, "reconnecting in %s...\n", wait.Round(time.Second))
+				watchStats.observeReconnect(wait)
+				logger.Info("reconnecting", "wait", wait.Round(time.Second).String(), "reconnects", reconnects)
 
 				timer := time.NewTimer(wait)
 				select {
@@ -257,22 +348,49 @@ Examples:
 
 			// Show summary
 			duration := time.Since(startTime)
-			fmt.Fprintf(os.Stderr, "\n\nStream summary: %s, %d tweets, reconnects=%d, last_disconnect=%s, last_ruleset=%s\n",
-				duration.Round(time.Second), tweetCount, reconnects, lastDisconnect, lastRuleSet)
+			rate := 0.0
 			if duration.Seconds() > 0 {
-				rate := float64(tweetCount) / duration.Seconds() * 60
-				fmt.Fprintf(os.Stderr, "Rate: %.1f tweets/minute\n", rate)
+				rate = float64(tweetCount) / duration.Seconds() * 60
 			}
+			logger.Info("stream summary",
+				"duration", duration.Round(time.Second).String(),
+				"tweets", tweetCount,
+				"reconnects", reconnects,
+				"last_disconnect", lastDisconnect,
+				"last_ruleset", lastRuleSet,
+				"tweets_per_minute", fmt.Sprintf("%.1f", rate),
+			)
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringArrayVar(&keywords, "keyword", nil, "Keyword to watch for (can be specified multiple times)")
-	cmd.Flags().BoolVar(&autoSetup, "auto-setup", false, "Automatically set up stream rules for keywords")
+	cmd.Flags().StringArrayVar(&keywords, "keyword", nil, "Keyword to watch for; several words match anywhere in the tweet (can be specified multiple times)")
+	cmd.Flags().StringVar(&keywordFile, "keyword-file", "", "File with one keyword or exact phrase per line (- for stdin)")
+	cmd.Flags().BoolVar(&autoSetup, "auto-setup", false, "Replace the stream rules with the keywords packed into as few rules as possible")
+	cmd.Flags().StringVar(&filters, "filter", "", "Operators added to every auto-setup rule, e.g. 'lang:en -is:retweet'")
 	cmd.Flags().BoolVar(&showUser, "show-user", false, "Show author information")
 	cmd.Flags().BoolVar(&showMeta, "show-meta", false, "Show additional metadata")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output newline-delimited JSON events")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics on /metrics and a health probe on /healthz at this address (e.g. :9464)")
+	cmd.Flags().DurationVar(&maxSilence, "healthz-max-silence", 10*time.Minute, "Report unhealthy on /healthz when no tweet arrived for this long (0 disables)")
+	storeOpts = addStoreFlag(cmd)
+	lintOpts = addLintFlag(cmd, searchquery.EndpointStream)
+	cmd.Flags().StringVar(&parquetDir, "parquet-dir", "", "Also write every tweet to rolling Parquet files in this directory")
+	cmd.Flags().DurationVar(&rollEvery, "roll-every", time.Hour, "Start a new Parquet file at each multiple of this duration in UTC (0 disables)")
+	cmd.Flags().IntVar(&rollRows, "roll-rows", 0, "Start a new Parquet file after this many rows (0 disables)")
 
 	return cmd
 }
+
+// matchedKeywords maps the rules a tweet matched back to the keywords packed
+// into them that its text contains.
+func matchedKeywords(packed map[string]searchquery.PackedRule, rules []stream.MatchingRule, text string) []string {
+	var matched []string
+	for _, rule := range rules {
+		if packedRule, ok := packed[rule.Tag]; ok {
+			matched = append(matched, packedRule.Match(text)...)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/metrics"
+	stream "github.com/0dayfall/ctw/internal/tweet/filteredstream"
+)
+
+// watchMetrics exposes the state of a long-running watch as Prometheus
+// metrics. A nil *watchMetrics is valid and records nothing, so the watch loop
+// does not need to check whether --metrics-addr was given.
+type watchMetrics struct {
+	registry       *metrics.Registry
+	tweets         *metrics.Counter
+	reconnects     *metrics.Counter
+	disconnects    *metrics.Counter
+	connected      *metrics.Gauge
+	backoff        *metrics.Gauge
+	handlerLatency *metrics.Histogram
+	rateLimit      *metrics.Gauge
+	lastTweet      *metrics.Gauge
+
+	maxSilence   time.Duration
+	lastActivity atomic.Int64
+}
+
+func newWatchMetrics(maxSilence time.Duration) *watchMetrics {
+	registry := metrics.NewRegistry()
+	m := &watchMetrics{
+		registry:       registry,
+		tweets:         registry.NewCounter("ctw_stream_tweets_total", "Tweets received from the filtered stream, by matching rule tag.", "rule_tag"),
+		reconnects:     registry.NewCounter("ctw_stream_reconnects_total", "Stream reconnect attempts."),
+		disconnects:    registry.NewCounter("ctw_stream_disconnects_total", "Stream disconnects, by reason.", "reason"),
+		connected:      registry.NewGauge("ctw_stream_connected", "1 while a stream connection is open, 0 while backing off."),
+		backoff:        registry.NewGauge("ctw_stream_backoff_seconds", "Current reconnect backoff in seconds (0 when connected)."),
+		handlerLatency: registry.NewHistogram("ctw_stream_handler_duration_seconds", "Time spent handling each tweet.", nil),
+		rateLimit:      registry.NewGauge("ctw_stream_rate_limit", "Connection rate limit headers from the last stream connect.", "field"),
+		lastTweet:      registry.NewGauge("ctw_stream_last_tweet_timestamp_seconds", "Unix time of the last tweet received."),
+		maxSilence:     maxSilence,
+	}
+	m.lastActivity.Store(time.Now().UnixNano())
+	return m
+}
+
+func (m *watchMetrics) observeTweet(rules []stream.MatchingRule, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	now := time.Now()
+	m.lastActivity.Store(now.UnixNano())
+	m.lastTweet.Set(float64(now.Unix()))
+	m.handlerLatency.Observe(elapsed.Seconds())
+
+	if len(rules) == 0 {
+		m.tweets.Inc("unknown")
+		return
+	}
+	for _, rule := range rules {
+		tag := rule.Tag
+		if tag == "" {
+			tag = rule.ID
+		}
+		m.tweets.Inc(tag)
+	}
+}
+
+func (m *watchMetrics) observeConnected(connected bool) {
+	if m == nil {
+		return
+	}
+	if connected {
+		m.connected.Set(1)
+		m.backoff.Set(0)
+		return
+	}
+	m.connected.Set(0)
+}
+
+func (m *watchMetrics) observeDisconnect(err error) {
+	if m == nil {
+		return
+	}
+	m.disconnects.Inc(disconnectReason(err))
+}
+
+func (m *watchMetrics) observeReconnect(wait time.Duration) {
+	if m == nil {
+		return
+	}
+	m.reconnects.Inc()
+	m.backoff.Set(wait.Seconds())
+}
+
+func (m *watchMetrics) observeRateLimits(snapshot client.RateLimitSnapshot) {
+	if m == nil {
+		return
+	}
+	if snapshot.Limit >= 0 {
+		m.rateLimit.Set(float64(snapshot.Limit), "limit")
+	}
+	if snapshot.Remaining >= 0 {
+		m.rateLimit.Set(float64(snapshot.Remaining), "remaining")
+	}
+	if snapshot.Reset >= 0 {
+		m.rateLimit.Set(float64(snapshot.Reset), "reset")
+	}
+}
+
+// serve starts the metrics listener in the background. Binding happens before
+// returning so an unusable address fails the command immediately.
+func (m *watchMetrics) serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.registry.Handler())
+	mux.HandleFunc("/healthz", m.handleHealthz)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("metrics listener: %w", err)
+	}
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics listener stopped", "error", err)
+		}
+	}()
+
+	logger.Info("serving metrics", "addr", listener.Addr().String(), "paths", "/metrics,/healthz")
+	return nil
+}
+
+func (m *watchMetrics) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	silence := time.Since(time.Unix(0, m.lastActivity.Load()))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if m.maxSilence > 0 && silence > m.maxSilence {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "stream silent for %s (max %s)\n", silence.Round(time.Second), m.maxSilence)
+		return
+	}
+	fmt.Fprintf(w, "ok (last tweet %s ago)\n", silence.Round(time.Second))
+}
+
+// disconnectReason maps a stream error to a low-cardinality metric label.
+func disconnectReason(err error) string {
+	var netErr net.Error
+	switch {
+	case err == nil, errors.Is(err, io.EOF):
+		return "eof"
+	case client.IsRateLimited(err):
+		return "rate_limited"
+	case client.IsAuth(err):
+		return "auth"
+	case client.IsForbiddenTier(err):
+		return "forbidden"
+	case isAPIError(err):
+		return "api_error"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	default:
+		return "error"
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -42,6 +44,22 @@ type Config struct {
 	// RetryWaitMax caps how long a single retry wait can last. Rate-limit
 	// resets further away than this are truncated. Defaults to 30s.
 	RetryWaitMax time.Duration
+
+	// Logger receives retry decisions and, when Trace is set, per-request
+	// diagnostics. Defaults to a text logger on stderr at info level.
+	Logger *slog.Logger
+
+	// Trace logs redacted request/response headers and timings at debug level
+	// for every attempt.
+	Trace bool
+
+	// UserID is the id of the user the bearer token acts for, when already
+	// known. Left empty, it is looked up on first use by UserID().
+	UserID string
+
+	// OnUserID is called once UserID() has fetched the id from the API, so
+	// callers can persist it for later runs.
+	OnUserID func(id string)
 }
 
 // Client wraps HTTP concerns for talking to the Twitter v2 API.
@@ -53,7 +71,12 @@ type Client struct {
 	retry        int
 	retryBase    time.Duration
 	retryWaitMax time.Duration
-	logf         func(format string, args ...any)
+	logger       *slog.Logger
+	trace        bool
+
+	meMu     sync.Mutex
+	userID   string
+	onUserID func(id string)
 }
 
 // New constructs a Client using the supplied configuration taking sensible defaults
@@ -93,6 +116,11 @@ func New(cfg Config) (*Client, error) {
 		retryWaitMax = defaultRetryWaitMax
 	}
 
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
 	return &Client{
 		httpClient:   httpClient,
 		baseURL:      baseURL,
@@ -101,12 +129,22 @@ func New(cfg Config) (*Client, error) {
 		retry:        retry,
 		retryBase:    defaultRetryBase,
 		retryWaitMax: retryWaitMax,
-		logf: func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format, args...)
-		},
+		logger:       logger,
+		trace:        cfg.Trace,
+		userID:       strings.TrimSpace(cfg.UserID),
+		onUserID:     cfg.OnUserID,
 	}, nil
 }
 
+// Logger returns the structured logger used by the client. Services built on
+// top of the client use it for their own diagnostics.
+func (c *Client) Logger() *slog.Logger {
+	if c == nil || c.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.logger
+}
+
 func resolveBaseURL(raw string) (*url.URL, error) {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
@@ -187,6 +225,15 @@ func (c *Client) Post(ctx context.Context, path string, body any, query map[stri
 	return c.Do(req)
 }
 
+// Put issues a PUT request against the supplied path with a JSON payload.
+func (c *Client) Put(ctx context.Context, path string, body any, query map[string]string) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodPut, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
 // Delete issues a DELETE request against the supplied path.
 func (c *Client) Delete(ctx context.Context, path string, query map[string]string) (*http.Response, error) {
 	req, err := c.NewRequest(ctx, http.MethodDelete, path, query, nil)
@@ -218,19 +265,37 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 			return nil, err
 		}
 
+		if c.trace {
+			c.traceRequest(attemptReq, attempt)
+		}
+		start := time.Now()
 		resp, err := c.httpClient.Do(attemptReq)
+		if c.trace {
+			c.traceResponse(attemptReq, resp, err, time.Since(start))
+		}
 
-		if attempt >= c.retry || !c.shouldRetry(req, resp, err) {
+		retryable := c.shouldRetry(req, resp, err)
+		if attempt >= c.retry || !retryable {
+			if retryable && c.retry > 0 {
+				c.logger.Warn("retries exhausted", "method", req.Method, "url", req.URL.Redacted(), "attempts", attempt+1)
+			}
 			return resp, err
 		}
 
 		wait := c.retryWait(attempt, resp)
+		attrs := []any{
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"wait_ms", wait.Milliseconds(),
+			"attempt", attempt + 1,
+			"max_retries", c.retry,
+		}
 		if err != nil {
 			lastErr = err
-			c.logf("ctw: request failed (%v); retrying in %s (attempt %d/%d)\n", err, wait.Round(time.Millisecond), attempt+1, c.retry)
+			c.logger.Warn("request failed; retrying", append(attrs, "error", err)...)
 		} else {
 			lastErr = fmt.Errorf("client: transient status %d", resp.StatusCode)
-			c.logf("ctw: got HTTP %d; retrying in %s (attempt %d/%d)\n", resp.StatusCode, wait.Round(time.Millisecond), attempt+1, c.retry)
+			c.logger.Warn("transient response; retrying", append(attrs, "status", resp.StatusCode)...)
 			drainAndClose(resp.Body)
 		}
 
@@ -360,53 +425,6 @@ func (c *Client) decorateHeaders(req *http.Request) {
 	}
 }
 
-// APIError represents one or more Twitter API errors.
-type APIError struct {
-	StatusCode int
-	Errors     []Error
-}
-
-func (e APIError) Error() string {
-	if len(e.Errors) == 0 {
-		return fmt.Sprintf("twitter api error: status %d", e.StatusCode)
-	}
-	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("twitter api error: status %d", e.StatusCode))
-	for _, apiErr := range e.Errors {
-		builder.WriteString(fmt.Sprintf("; code=%d message=%s", apiErr.Code, apiErr.Message))
-	}
-	return builder.String()
-}
-
-// Error captures a single entry in the Twitter error payload.
-type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
-type errorResponse struct {
-	Errors []Error `json:"errors"`
-}
-
-// CheckResponse inspects the response status code and returns an APIError when
-// the call did not succeed.
-func CheckResponse(resp *http.Response) error {
-	if resp == nil {
-		return errors.New("client: nil response")
-	}
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return nil
-	}
-
-	defer SafeClose(resp.Body)
-	var payload errorResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
-		return fmt.Errorf("client: decode error response: %w", err)
-	}
-
-	return APIError{StatusCode: resp.StatusCode, Errors: payload.Errors}
-}
-
 // RateLimitSnapshot captures the rate limit headers for a response.
 type RateLimitSnapshot struct {
 	Limit     int
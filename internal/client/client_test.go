@@ -1,9 +1,12 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -20,7 +23,7 @@ func newTestClient(t *testing.T, baseURL string, retry int) *Client {
 		t.Fatalf("New: %v", err)
 	}
 	c.retryBase = 5 * time.Millisecond
-	c.logf = func(string, ...any) {}
+	c.logger = slog.New(slog.DiscardHandler)
 	return c
 }
 
@@ -96,6 +99,37 @@ func TestDoDoesNotRetryPostOn500(t *testing.T) {
 	}
 }
 
+func TestPutRetriesOn500WithBodyReplay(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(r.Body)
+		if got := strings.TrimSpace(body.String()); got != `{"hidden":true}` {
+			t.Errorf("body = %s", got)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL, 3)
+	resp, err := c.Put(context.Background(), "2/tweets/1/hidden", map[string]bool{"hidden": true}, nil)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	defer SafeClose(resp.Body)
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("calls = %d, want 2 (PUT is idempotent)", got)
+	}
+}
+
 func TestDoRetriesGetOn500(t *testing.T) {
 	var calls int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -189,3 +223,38 @@ func TestDoStopsOnContextCancel(t *testing.T) {
 		t.Fatal("expected context error, got nil")
 	}
 }
+
+func TestTraceRedactsAuthorization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-rate-limit-remaining", "42")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	c, err := New(Config{
+		BaseURL:     server.URL,
+		BearerToken: "secret-token",
+		Logger:      slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Trace:       true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	resp, err := c.Get(context.Background(), "2/tweets", nil)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer SafeClose(resp.Body)
+
+	out := logs.String()
+	if strings.Contains(out, "secret-token") {
+		t.Fatalf("trace output leaked bearer token: %s", out)
+	}
+	for _, want := range []string{`"msg":"http request"`, `"Authorization":"[REDACTED]"`, `"msg":"http response"`, `"X-Rate-Limit-Remaining":"42"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("trace output missing %s: %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	problemTypeNotFound        = "https://api.twitter.com/2/problems/resource-not-found"
+	problemTypeClientForbidden = "https://api.twitter.com/2/problems/client-forbidden"
+	problemTypeUnsupportedAuth = "https://api.twitter.com/2/problems/unsupported-authentication"
+	problemTypeUsageCapped     = "https://api.twitter.com/2/problems/usage-capped"
+
+	reasonClientNotEnrolled = "client-not-enrolled"
+)
+
+// APIError represents a failed Twitter API call. Top-level fields carry the
+// RFC 7807 problem details the v2 API returns for the request as a whole, while
+// Errors lists the individual entries of the errors array.
+type APIError struct {
+	StatusCode int
+	Title      string
+	Detail     string
+	Type       string
+	Reason     string
+	Errors     []Error
+}
+
+func (e APIError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("twitter api error: status %d", e.StatusCode))
+	if e.Title != "" {
+		builder.WriteString(": " + e.Title)
+	}
+	if e.Detail != "" && e.Detail != e.Title {
+		builder.WriteString(" (" + e.Detail + ")")
+	}
+	for _, apiErr := range e.Errors {
+		builder.WriteString("; " + apiErr.String())
+	}
+	return builder.String()
+}
+
+// Error captures a single entry in the Twitter error payload. Legacy v1.1-style
+// entries populate Code and Message; v2 problem entries populate the remaining
+// fields. The same shape is used for partial errors returned alongside data.
+type Error struct {
+	Code         int    `json:"code,omitempty"`
+	Message      string `json:"message,omitempty"`
+	Title        string `json:"title,omitempty"`
+	Detail       string `json:"detail,omitempty"`
+	Type         string `json:"type,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Parameter    string `json:"parameter,omitempty"`
+	Value        string `json:"value,omitempty"`
+}
+
+func (e Error) String() string {
+	if e.Code != 0 || e.Message != "" {
+		return fmt.Sprintf("code=%d message=%s", e.Code, e.Message)
+	}
+	parts := make([]string, 0, 4)
+	if e.Title != "" {
+		parts = append(parts, "title="+e.Title)
+	}
+	if e.Detail != "" {
+		parts = append(parts, "detail="+e.Detail)
+	}
+	if e.Parameter != "" {
+		parts = append(parts, "parameter="+e.Parameter)
+	}
+	if e.Value != "" {
+		parts = append(parts, "value="+e.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+type errorResponse struct {
+	Title  string  `json:"title"`
+	Detail string  `json:"detail"`
+	Type   string  `json:"type"`
+	Reason string  `json:"reason"`
+	Errors []Error `json:"errors"`
+}
+
+// CheckResponse inspects the response status code and returns an APIError when
+// the call did not succeed.
+func CheckResponse(resp *http.Response) error {
+	if resp == nil {
+		return errors.New("client: nil response")
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	defer SafeClose(resp.Body)
+	var payload errorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("client: decode error response: %w", err)
+	}
+
+	return APIError{
+		StatusCode: resp.StatusCode,
+		Title:      payload.Title,
+		Detail:     payload.Detail,
+		Type:       payload.Type,
+		Reason:     payload.Reason,
+		Errors:     payload.Errors,
+	}
+}
+
+// IsRateLimited reports whether err is an API error caused by exhausting a
+// rate limit or the monthly usage cap.
+func IsRateLimited(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Type == problemTypeUsageCapped {
+		return true
+	}
+	return apiErr.hasCode(88)
+}
+
+// IsForbiddenTier reports whether err indicates that the app's access level
+// does not include the requested endpoint.
+func IsForbiddenTier(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	if apiErr.Reason == reasonClientNotEnrolled || apiErr.Type == problemTypeClientForbidden {
+		return true
+	}
+	return apiErr.hasCode(453)
+}
+
+// IsNotFound reports whether err refers to a resource that does not exist or
+// is not visible to the caller.
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusNotFound || apiErr.Type == problemTypeNotFound {
+		return true
+	}
+	for _, entry := range apiErr.Errors {
+		if entry.Type == problemTypeNotFound {
+			return true
+		}
+	}
+	return apiErr.hasCode(34) || apiErr.hasCode(50) || apiErr.hasCode(144)
+}
+
+// IsAuth reports whether err was caused by missing, invalid, or unsupported
+// credentials.
+func IsAuth(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusUnauthorized || apiErr.Type == problemTypeUnsupportedAuth {
+		return true
+	}
+	return apiErr.hasCode(32) || apiErr.hasCode(89) || apiErr.hasCode(215)
+}
+
+// AsAPIError finds the APIError in err's chain, whether it was wrapped as a
+// value or as a pointer.
+func AsAPIError(err error) (APIError, bool) {
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	var apiErrPtr *APIError
+	if errors.As(err, &apiErrPtr) && apiErrPtr != nil {
+		return *apiErrPtr, true
+	}
+	return APIError{}, false
+}
+
+func (e APIError) hasCode(code int) bool {
+	for _, entry := range e.Errors {
+		if entry.Code == code {
+			return true
+		}
+	}
+	return false
+}
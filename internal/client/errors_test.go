@@ -0,0 +1,119 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newErrorResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestCheckResponseDecodesProblemDetails(t *testing.T) {
+	resp := newErrorResponse(http.StatusForbidden, `{
+		"client_id": "123",
+		"required_enrollment": "Appropriate Level of API Access",
+		"title": "Client Forbidden",
+		"detail": "This request must be made using an approved developer account.",
+		"reason": "client-not-enrolled",
+		"type": "https://api.twitter.com/2/problems/client-forbidden"
+	}`)
+
+	err := CheckResponse(resp)
+	apiErr, ok := err.(APIError)
+	if !ok {
+		t.Fatalf("err = %T, want APIError", err)
+	}
+	if apiErr.Title != "Client Forbidden" || apiErr.Reason != "client-not-enrolled" {
+		t.Fatalf("unexpected problem details: %+v", apiErr)
+	}
+	if !strings.Contains(apiErr.Error(), "twitter api error: status 403: Client Forbidden") {
+		t.Fatalf("Error() = %q", apiErr.Error())
+	}
+	if !IsForbiddenTier(err) {
+		t.Fatal("IsForbiddenTier = false, want true")
+	}
+	if IsAuth(err) || IsRateLimited(err) || IsNotFound(err) {
+		t.Fatal("forbidden error matched another classifier")
+	}
+}
+
+func TestCheckResponseDecodesErrorEntries(t *testing.T) {
+	resp := newErrorResponse(http.StatusBadRequest, `{
+		"errors": [{
+			"parameters": {"ids": ["abc"]},
+			"message": "The id query parameter value [abc] is not valid",
+			"title": "Invalid Request",
+			"detail": "One or more parameters to your request was invalid.",
+			"type": "https://api.twitter.com/2/problems/invalid-request",
+			"parameter": "ids",
+			"value": "abc"
+		}],
+		"title": "Invalid Request",
+		"detail": "One or more parameters to your request was invalid.",
+		"type": "https://api.twitter.com/2/problems/invalid-request"
+	}`)
+
+	err := CheckResponse(resp)
+	apiErr, ok := err.(APIError)
+	if !ok {
+		t.Fatalf("err = %T, want APIError", err)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Parameter != "ids" || apiErr.Errors[0].Value != "abc" {
+		t.Fatalf("unexpected error entries: %+v", apiErr.Errors)
+	}
+}
+
+func TestErrorClassifiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		check func(error) bool
+	}{
+		{"401 is auth", APIError{StatusCode: http.StatusUnauthorized}, IsAuth},
+		{"code 89 is auth", APIError{StatusCode: http.StatusForbidden, Errors: []Error{{Code: 89}}}, IsAuth},
+		{"429 is rate limited", APIError{StatusCode: http.StatusTooManyRequests}, IsRateLimited},
+		{"usage cap is rate limited", APIError{StatusCode: http.StatusForbidden, Type: problemTypeUsageCapped}, IsRateLimited},
+		{"404 is not found", APIError{StatusCode: http.StatusNotFound}, IsNotFound},
+		{"not found entry", APIError{StatusCode: http.StatusBadRequest, Errors: []Error{{Type: problemTypeNotFound}}}, IsNotFound},
+		{"wrapped error", fmt.Errorf("lookup: %w", APIError{StatusCode: http.StatusUnauthorized}), IsAuth},
+		{"pointer error", &APIError{StatusCode: http.StatusTooManyRequests}, IsRateLimited},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.check(tc.err) {
+				t.Fatalf("classifier returned false for %v", tc.err)
+			}
+		})
+	}
+
+	if IsAuth(io.EOF) || IsNotFound(nil) {
+		t.Fatal("classifier matched a non-API error")
+	}
+}
+
+func TestAsAPIErrorMatchesValuesAndPointers(t *testing.T) {
+	for _, err := range []error{
+		APIError{StatusCode: http.StatusBadRequest},
+		fmt.Errorf("lookup: %w", APIError{StatusCode: http.StatusBadRequest}),
+		fmt.Errorf("lookup: %w", &APIError{StatusCode: http.StatusBadRequest}),
+	} {
+		apiErr, ok := AsAPIError(err)
+		if !ok || apiErr.StatusCode != http.StatusBadRequest {
+			t.Fatalf("AsAPIError(%v) = %v, %v", err, apiErr, ok)
+		}
+	}
+	if _, ok := AsAPIError(fmt.Errorf("lookup: %w", (*APIError)(nil))); ok {
+		t.Fatal("matched a nil pointer")
+	}
+	if _, ok := AsAPIError(io.EOF); ok {
+		t.Fatal("matched a non-API error")
+	}
+}
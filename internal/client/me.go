@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const mePath = "/2/users/me"
+
+// UserID returns the id of the user the bearer token acts for. It comes from
+// Config.UserID when set; otherwise it is fetched from /2/users/me on first
+// use and remembered for the life of the client.
+func (c *Client) UserID(ctx context.Context) (string, error) {
+	c.meMu.Lock()
+	defer c.meMu.Unlock()
+
+	if c.userID != "" {
+		return c.userID, nil
+	}
+
+	resp, err := c.Get(ctx, mePath, nil)
+	if err != nil {
+		return "", err
+	}
+	defer SafeClose(resp.Body)
+
+	if err := CheckResponse(resp); err != nil {
+		return "", err
+	}
+
+	var me struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
+		return "", fmt.Errorf("client: decode %s response: %w", mePath, err)
+	}
+	if me.Data.ID == "" {
+		return "", errors.New("client: " + mePath + " returned no user id")
+	}
+
+	c.userID = me.Data.ID
+	if c.onUserID != nil {
+		c.onUserID(c.userID)
+	}
+	return c.userID, nil
+}
+
+// ResolveUserID returns userID, or the authenticated user's id when userID
+// is empty. Services use it to let callers omit the acting user.
+func (c *Client) ResolveUserID(ctx context.Context, userID string) (string, error) {
+	if userID != "" {
+		return userID, nil
+	}
+	id, err := c.UserID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("resolve authenticated user: %w", err)
+	}
+	return id, nil
+}
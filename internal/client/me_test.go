@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserIDFetchesOnceAndNotifies(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/2/users/me" {
+			t.Errorf("path = %s, want /2/users/me", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"7","username":"me","name":"Me"}}`))
+	}))
+	defer server.Close()
+
+	var notified string
+	c, err := New(Config{
+		BaseURL:     server.URL,
+		BearerToken: "test-token",
+		OnUserID:    func(id string) { notified = id },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for range 2 {
+		id, err := c.UserID(context.Background())
+		if err != nil {
+			t.Fatalf("UserID: %v", err)
+		}
+		if id != "7" {
+			t.Fatalf("id = %q, want 7", id)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+	if notified != "7" {
+		t.Fatalf("OnUserID got %q, want 7", notified)
+	}
+}
+
+func TestResolveUserIDPrefersExplicitAndConfigured(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	c, err := New(Config{BaseURL: server.URL, BearerToken: "test-token", UserID: "9"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if id, err := c.ResolveUserID(context.Background(), "3"); err != nil || id != "3" {
+		t.Fatalf("ResolveUserID(3) = %q, %v", id, err)
+	}
+	if id, err := c.ResolveUserID(context.Background(), ""); err != nil || id != "9" {
+		t.Fatalf("ResolveUserID(\"\") = %q, %v; want configured 9", id, err)
+	}
+}
+
+func TestUserIDRejectsAppOnlyToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"title":"Unsupported Authentication","detail":"Authenticating with OAuth 2.0 Application-Only is forbidden for this endpoint.","type":"https://api.twitter.com/2/problems/unsupported-authentication","status":403}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL, 0)
+	_, err := c.ResolveUserID(context.Background(), "")
+	if err == nil {
+		t.Fatal("expected error for app-only token")
+	}
+	if !IsAuth(err) {
+		t.Fatalf("IsAuth(%v) = false, want true", err)
+	}
+}
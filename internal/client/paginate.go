@@ -0,0 +1,30 @@
+package client
+
+// PaginationTokenParam is the query parameter most v2 endpoints take the next
+// page's token in; the full-archive counts endpoint uses next_token.
+const PaginationTokenParam = "pagination_token"
+
+// PageFunc fetches one page with query and returns the page's next token, or
+// "" to stop after it.
+type PageFunc func(query map[string]string) (next string, err error)
+
+// Paginate calls fetch with a copy of params and then again with each next
+// token it returns set as tokenParam, until the token is empty or maxPages
+// pages were read (0 means no limit). When maxPages stops the walk it returns
+// the next token left unfollowed, otherwise "".
+func Paginate(params map[string]string, tokenParam string, maxPages int, fetch PageFunc) (string, error) {
+	query := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		query[k] = v
+	}
+	for pages := 1; ; pages++ {
+		next, err := fetch(query)
+		if err != nil || next == "" {
+			return "", err
+		}
+		if maxPages > 0 && pages >= maxPages {
+			return next, nil
+		}
+		query[tokenParam] = next
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginateFollowsTokens(t *testing.T) {
+	params := map[string]string{"max_results": "100"}
+	tokens := map[string]string{"": "p2", "p2": "p3", "p3": ""}
+
+	var seen []string
+	next, err := Paginate(params, PaginationTokenParam, 0, func(query map[string]string) (string, error) {
+		require.Equal(t, "100", query["max_results"])
+		seen = append(seen, query[PaginationTokenParam])
+		return tokens[query[PaginationTokenParam]], nil
+	})
+	require.NoError(t, err)
+	require.Empty(t, next)
+	require.Equal(t, []string{"", "p2", "p3"}, seen)
+	require.NotContains(t, params, PaginationTokenParam)
+
+	seen = nil
+	next, err = Paginate(nil, "next_token", 2, func(query map[string]string) (string, error) {
+		seen = append(seen, query["next_token"])
+		return tokens[query["next_token"]], nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "p3", next)
+	require.Equal(t, []string{"", "p2"}, seen)
+
+	boom := errors.New("boom")
+	_, err = Paginate(nil, PaginationTokenParam, 0, func(map[string]string) (string, error) { return "p2", boom })
+	require.ErrorIs(t, err, boom)
+}
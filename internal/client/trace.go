@@ -0,0 +1,64 @@
+package client
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders lists headers whose values never appear in trace output.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+func (c *Client) traceRequest(req *http.Request, attempt int) {
+	c.logger.Debug("http request",
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"attempt", attempt+1,
+		"headers", redactHeaders(req.Header),
+	)
+}
+
+func (c *Client) traceResponse(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	attrs := []any{
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"duration_ms", elapsed.Milliseconds(),
+	}
+	if err != nil {
+		c.logger.Debug("http response", append(attrs, "error", err)...)
+		return
+	}
+	c.logger.Debug("http response", append(attrs,
+		"status", resp.StatusCode,
+		"headers", redactHeaders(resp.Header),
+	)...)
+}
+
+// redactHeaders flattens headers into a stable, loggable group with secrets
+// replaced. Multi-valued headers are joined with ", ".
+func redactHeaders(header http.Header) slog.Value {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(header[key], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			value = redactedValue
+		}
+		attrs = append(attrs, slog.String(key, value))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,163 @@
+// Package columnar writes tweets as Parquet with a stable, typed schema so
+// dumps load into DuckDB, pandas or Spark without JSON parsing: timestamps
+// are TIMESTAMP(MILLIS), metrics are INT64 and entities are nested lists.
+package columnar
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/parquet-go/parquet-go"
+)
+
+// Tweet is one Parquet row. Column names and order are the file schema:
+// extend it at the end and never rename or retype a column, so files written
+// by different ctw versions can be read together.
+type Tweet struct {
+	ID                string            `parquet:"id"`
+	Text              string            `parquet:"text"`
+	AuthorID          string            `parquet:"author_id,optional"`
+	AuthorUsername    string            `parquet:"author_username,optional"`
+	AuthorName        string            `parquet:"author_name,optional"`
+	CreatedAt         time.Time         `parquet:"created_at,optional,timestamp(millisecond)"`
+	ConversationID    string            `parquet:"conversation_id,optional"`
+	InReplyToUserID   string            `parquet:"in_reply_to_user_id,optional"`
+	Lang              string            `parquet:"lang,optional"`
+	Source            string            `parquet:"source,optional"`
+	PossiblySensitive bool              `parquet:"possibly_sensitive"`
+	PublicMetrics     *PublicMetrics    `parquet:"public_metrics,optional"`
+	Entities          Entities          `parquet:"entities"`
+	ReferencedTweets  []ReferencedTweet `parquet:"referenced_tweets,list"`
+	MediaKeys         []string          `parquet:"media_keys,list"`
+	MatchingRules     []string          `parquet:"matching_rules,list"`
+}
+
+// PublicMetrics are the engagement counts; the group is null when the
+// response did not request public_metrics.
+type PublicMetrics struct {
+	RetweetCount    int64 `parquet:"retweet_count"`
+	ReplyCount      int64 `parquet:"reply_count"`
+	LikeCount       int64 `parquet:"like_count"`
+	QuoteCount      int64 `parquet:"quote_count"`
+	BookmarkCount   int64 `parquet:"bookmark_count"`
+	ImpressionCount int64 `parquet:"impression_count"`
+}
+
+// Entities are the tweet's hashtags and cashtags without their symbol,
+// mentioned usernames and unwound (or expanded) URLs.
+type Entities struct {
+	Hashtags []string `parquet:"hashtags,list"`
+	Cashtags []string `parquet:"cashtags,list"`
+	Mentions []string `parquet:"mentions,list"`
+	URLs     []string `parquet:"urls,list"`
+}
+
+// ReferencedTweet is a quoted, replied-to or retweeted tweet.
+type ReferencedTweet struct {
+	Type string `parquet:"type"`
+	ID   string `parquet:"id"`
+}
+
+// FromTweet converts tweet, resolving its author from includes.
+func FromTweet(tweet model.Tweet, includes *model.Includes) Tweet {
+	row := Tweet{
+		ID:                tweet.ID,
+		Text:              tweet.FullText(),
+		AuthorID:          tweet.AuthorID,
+		CreatedAt:         tweet.CreatedAt,
+		ConversationID:    tweet.ConversationID,
+		InReplyToUserID:   tweet.InReplyToUserID,
+		Lang:              tweet.Lang,
+		Source:            tweet.Source,
+		PossiblySensitive: tweet.PossiblySensitive,
+	}
+	if author, ok := includes.AuthorOf(tweet); ok {
+		row.AuthorUsername = author.Username
+		row.AuthorName = author.Name
+	}
+	if m := tweet.PublicMetrics; m != nil {
+		row.PublicMetrics = &PublicMetrics{
+			RetweetCount:    int64(m.RetweetCount),
+			ReplyCount:      int64(m.ReplyCount),
+			LikeCount:       int64(m.LikeCount),
+			QuoteCount:      int64(m.QuoteCount),
+			BookmarkCount:   int64(m.BookmarkCount),
+			ImpressionCount: int64(m.ImpressionCount),
+		}
+	}
+	if e := tweet.Entities; e != nil {
+		for _, tag := range e.Hashtags {
+			row.Entities.Hashtags = append(row.Entities.Hashtags, tag.Tag)
+		}
+		for _, tag := range e.Cashtags {
+			row.Entities.Cashtags = append(row.Entities.Cashtags, tag.Tag)
+		}
+		for _, mention := range e.Mentions {
+			row.Entities.Mentions = append(row.Entities.Mentions, mention.Username)
+		}
+		for _, url := range e.URLs {
+			expanded := url.ExpandedURL
+			if url.UnwoundURL != "" {
+				expanded = url.UnwoundURL
+			}
+			if expanded == "" {
+				expanded = url.URL
+			}
+			row.Entities.URLs = append(row.Entities.URLs, expanded)
+		}
+	}
+	for _, ref := range tweet.ReferencedTweets {
+		row.ReferencedTweets = append(row.ReferencedTweets, ReferencedTweet{Type: ref.Type, ID: ref.ID})
+	}
+	if tweet.Attachments != nil {
+		row.MediaKeys = tweet.Attachments.MediaKeys
+	}
+	return row
+}
+
+// FromResponse converts the tweets of response.
+func FromResponse(response model.TweetsResponse) []Tweet {
+	rows := make([]Tweet, 0, len(response.Data))
+	for _, tweet := range response.Data {
+		rows = append(rows, FromTweet(tweet, response.Includes))
+	}
+	return rows
+}
+
+// Writer writes Tweet rows to one Parquet file. The file is only readable
+// once Close has written its footer.
+type Writer struct {
+	writer *parquet.GenericWriter[Tweet]
+	rows   int
+}
+
+// NewWriter starts a zstd-compressed Parquet file on w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{writer: parquet.NewGenericWriter[Tweet](w, parquet.Compression(&parquet.Zstd))}
+}
+
+// Write appends rows.
+func (w *Writer) Write(rows ...Tweet) error {
+	n, err := w.writer.Write(rows)
+	w.rows += n
+	if err != nil {
+		return fmt.Errorf("columnar: write: %w", err)
+	}
+	return nil
+}
+
+// Rows returns the number of rows written so far.
+func (w *Writer) Rows() int {
+	return w.rows
+}
+
+// Close flushes the remaining rows and writes the footer. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.writer.Close(); err != nil {
+		return fmt.Errorf("columnar: close: %w", err)
+	}
+	return nil
+}
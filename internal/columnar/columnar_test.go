@@ -0,0 +1,130 @@
+package columnar
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func readRows(t *testing.T, data []byte) []Tweet {
+	t.Helper()
+	file, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	rows := make([]Tweet, file.NumRows())
+	reader := parquet.NewGenericReader[Tweet](file)
+	_, _ = reader.Read(rows)
+	require.NoError(t, reader.Close())
+	return rows
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	response := model.TweetsResponse{
+		Data: []model.Tweet{{
+			ID:               "1",
+			Text:             "Hello #golang $GO @gopher https://t.co/x",
+			AuthorID:         "10",
+			CreatedAt:        time.Date(2024, 5, 1, 13, 14, 15, 0, time.UTC),
+			Lang:             "en",
+			PublicMetrics:    &model.PublicMetrics{LikeCount: 5, RetweetCount: 2},
+			ReferencedTweets: []model.ReferencedTweet{{Type: "quoted", ID: "2"}},
+			Attachments:      &model.Attachments{MediaKeys: []string{"3_1"}},
+			Entities: &model.Entities{
+				Hashtags: []model.Tag{{Tag: "golang"}},
+				Cashtags: []model.Tag{{Tag: "GO"}},
+				Mentions: []model.Mention{{Username: "gopher"}},
+				URLs:     []model.URL{{URL: "https://t.co/x", ExpandedURL: "https://go.dev"}},
+			},
+		}, {
+			ID:   "2",
+			Text: "bare",
+		}},
+		Includes: &model.Includes{Users: []model.User{{ID: "10", Username: "alice", Name: "Alice"}}},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.Write(FromResponse(response)...))
+	require.Equal(t, 2, w.Rows())
+	require.NoError(t, w.Close())
+
+	rows := readRows(t, buf.Bytes())
+	require.Len(t, rows, 2)
+	first := rows[0]
+	require.Equal(t, "alice", first.AuthorUsername)
+	require.True(t, first.CreatedAt.Equal(response.Data[0].CreatedAt))
+	require.Equal(t, int64(5), first.PublicMetrics.LikeCount)
+	require.Equal(t, Entities{
+		Hashtags: []string{"golang"},
+		Cashtags: []string{"GO"},
+		Mentions: []string{"gopher"},
+		URLs:     []string{"https://go.dev"},
+	}, first.Entities)
+	require.Equal(t, []ReferencedTweet{{Type: "quoted", ID: "2"}}, first.ReferencedTweets)
+	require.Equal(t, []string{"3_1"}, first.MediaKeys)
+
+	require.Nil(t, rows[1].PublicMetrics)
+	require.Empty(t, rows[1].Entities.Hashtags)
+}
+
+func TestRollingWriterRollsByPeriodAndRows(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRollingWriter(RollingOptions{Dir: dir, Every: time.Hour, MaxRows: 2})
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 1, 13, 5, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, r.Write(Tweet{ID: id}))
+	}
+	// The row limit completed the first file; the second is still open.
+	require.Equal(t, []string{filepath.Join(dir, "tweets-20240501T130000Z-0001.parquet")}, r.Files())
+	_, err = os.Stat(filepath.Join(dir, "tweets-20240501T130000Z-0002.parquet.inprogress"))
+	require.NoError(t, err)
+
+	require.NoError(t, r.CloseExpired())
+	require.Len(t, r.Files(), 1)
+
+	now = now.Add(time.Hour)
+	require.NoError(t, r.CloseExpired())
+	require.Len(t, r.Files(), 2)
+
+	require.NoError(t, r.Write(Tweet{ID: "4"}))
+	require.NoError(t, r.Close())
+	files := r.Files()
+	require.Equal(t, filepath.Join(dir, "tweets-20240501T140000Z-0001.parquet"), files[2])
+
+	var ids []string
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		for _, row := range readRows(t, data) {
+			ids = append(ids, row.ID)
+		}
+	}
+	require.Equal(t, []string{"1", "2", "3", "4"}, ids)
+
+	// A restart in the same period does not overwrite earlier files.
+	again, err := NewRollingWriter(RollingOptions{Dir: dir, Every: time.Hour})
+	require.NoError(t, err)
+	again.now = r.now
+	require.NoError(t, again.Write(Tweet{ID: "5"}))
+	require.NoError(t, again.Close())
+	require.Equal(t, []string{filepath.Join(dir, "tweets-20240501T140000Z-0002.parquet")}, again.Files())
+}
+
+func TestRollingWriterReportsUnusableDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	r, err := NewRollingWriter(RollingOptions{Dir: dir})
+	require.NoError(t, err)
+
+	// Probing names under a file fails with ENOTDIR rather than ErrNotExist.
+	require.NoError(t, os.Remove(dir))
+	require.NoError(t, os.WriteFile(dir, nil, 0o644))
+	require.ErrorContains(t, r.Write(Tweet{ID: "1"}), "columnar: stat ")
+}
@@ -0,0 +1,172 @@
+package columnar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// inProgressSuffix marks the file being written. Parquet files are only
+// readable after their footer is written, so a glob such as *.parquet never
+// picks up a partial file.
+const inProgressSuffix = ".inprogress"
+
+// RollingOptions control when a RollingWriter starts a new file.
+type RollingOptions struct {
+	// Dir receives the files; it is created when missing.
+	Dir string
+	// Prefix starts every file name; it defaults to "tweets".
+	Prefix string
+	// Every starts a new file at each multiple of the duration in UTC, such
+	// as every full hour. Zero disables time-based rolling.
+	Every time.Duration
+	// MaxRows starts a new file after this many rows. Zero disables it.
+	MaxRows int
+}
+
+// RollingWriter writes rows across a series of Parquet files named
+// <prefix>-<period start>-<sequence>.parquet, e.g.
+// tweets-20240501T130000Z-0001.parquet.
+type RollingWriter struct {
+	opts RollingOptions
+	now  func() time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *Writer
+	path   string
+	period time.Time
+	seq    int
+	files  []string
+}
+
+// NewRollingWriter prepares dir. The first file is opened by the first Write.
+func NewRollingWriter(opts RollingOptions) (*RollingWriter, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("columnar: rolling directory is required")
+	}
+	if opts.Every < 0 || opts.MaxRows < 0 {
+		return nil, errors.New("columnar: rolling interval and row limit must not be negative")
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "tweets"
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("columnar: create %s: %w", opts.Dir, err)
+	}
+	return &RollingWriter{opts: opts, now: time.Now}, nil
+}
+
+// Write appends row, first rolling to a new file when the period has ended or
+// the current file is full.
+func (r *RollingWriter) Write(row Tweet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().UTC()
+	if r.writer != nil {
+		full := r.opts.MaxRows > 0 && r.writer.Rows() >= r.opts.MaxRows
+		if r.expired(now) || full {
+			if err := r.finish(); err != nil {
+				return err
+			}
+		}
+	}
+	if r.writer == nil {
+		if err := r.start(now); err != nil {
+			return err
+		}
+	}
+	return r.writer.Write(row)
+}
+
+// CloseExpired completes the current file when its period has ended. Call it
+// periodically so a quiet stream does not leave the last hour's file open
+// until the next row arrives.
+func (r *RollingWriter) CloseExpired() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writer == nil || !r.expired(r.now().UTC()) {
+		return nil
+	}
+	return r.finish()
+}
+
+// Files returns the completed files, oldest first.
+func (r *RollingWriter) Files() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.files...)
+}
+
+// Close completes the current file.
+func (r *RollingWriter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writer == nil {
+		return nil
+	}
+	return r.finish()
+}
+
+func (r *RollingWriter) expired(now time.Time) bool {
+	return r.opts.Every > 0 && !now.Truncate(r.opts.Every).Equal(r.period)
+}
+
+func (r *RollingWriter) start(now time.Time) error {
+	period := now
+	if r.opts.Every > 0 {
+		period = now.Truncate(r.opts.Every)
+	}
+	if !period.Equal(r.period) {
+		r.period = period
+		r.seq = 0
+	}
+
+	// Skip names taken by an earlier run in the same period.
+	for {
+		r.seq++
+		name := fmt.Sprintf("%s-%s-%04d.parquet", r.opts.Prefix, r.period.Format("20060102T150405Z"), r.seq)
+		path := filepath.Join(r.opts.Dir, name)
+		_, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			r.path = path
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("columnar: stat %s: %w", path, err)
+		}
+	}
+
+	file, err := os.Create(r.path + inProgressSuffix)
+	if err != nil {
+		return fmt.Errorf("columnar: create %s: %w", r.path, err)
+	}
+	r.file = file
+	r.writer = NewWriter(file)
+	return nil
+}
+
+func (r *RollingWriter) finish() error {
+	writer, file := r.writer, r.file
+	r.writer, r.file = nil, nil
+
+	if err := writer.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("columnar: close %s: %w", r.path, err)
+	}
+	if err := os.Rename(file.Name(), r.path); err != nil {
+		return fmt.Errorf("columnar: close %s: %w", r.path, err)
+	}
+	r.files = append(r.files, r.path)
+	return nil
+}
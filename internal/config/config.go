@@ -23,13 +23,29 @@ type Config struct {
 		Retry     int      `toml:"retry"`
 	} `toml:"http"`
 
+	API struct {
+		// Tier is the X API access level: basic, pro or enterprise.
+		Tier string `toml:"tier"`
+	} `toml:"api"`
+
 	Output struct {
-		Pretty bool `toml:"pretty"`
+		Pretty bool   `toml:"pretty"`
+		Format string `toml:"format"`
 	} `toml:"output"`
 
 	Stream struct {
 		BackoffMax Duration `toml:"backoff_max"`
 	} `toml:"stream"`
+
+	Log struct {
+		Level  string `toml:"level"`
+		Format string `toml:"format"`
+	} `toml:"log"`
+
+	Cache struct {
+		Dir       string   `toml:"dir"`
+		HandleTTL Duration `toml:"handle_ttl"`
+	} `toml:"cache"`
 }
 
 // Default returns a config populated with default values.
@@ -38,7 +54,11 @@ func Default() Config {
 	cfg.HTTP.Timeout = Duration(15 * time.Second)
 	cfg.HTTP.Retry = 3
 	cfg.Output.Pretty = true
+	cfg.Output.Format = "json"
 	cfg.Stream.BackoffMax = Duration(2 * time.Minute)
+	cfg.Log.Level = "info"
+	cfg.Log.Format = "text"
+	cfg.Cache.HandleTTL = Duration(7 * 24 * time.Hour)
 	return cfg
 }
 
@@ -0,0 +1,246 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/dm"
+	"github.com/0dayfall/ctw/internal/ratelimit"
+)
+
+const (
+	messageCreateEvent = "MessageCreate"
+	eventFields        = "id,text,event_type,created_at,dm_conversation_id,sender_id"
+)
+
+// Messenger is the part of dm.Service the bot uses.
+type Messenger interface {
+	ListEvents(ctx context.Context, params map[string]string) (dm.DMEventsResponse, client.RateLimitSnapshot, error)
+	SendToConversation(ctx context.Context, conversationID string, req dm.SendDMRequest) (dm.SendDMResponse, client.RateLimitSnapshot, error)
+}
+
+// Bot answers incoming direct messages according to a Config.
+type Bot struct {
+	cfg       Config
+	messenger Messenger
+	selfID    string
+	logger    *slog.Logger
+
+	// DryRun logs replies instead of sending them and never writes the
+	// state file, so a rehearsal leaves the messages to a real run.
+	DryRun bool
+	// dryState carries the state from one dry-run poll to the next.
+	dryState *state
+
+	now        func() time.Time
+	runCommand func(ctx context.Context, command []string, stdin string, env []string) (string, error)
+}
+
+// New constructs a Bot replying as selfID, whose own messages it ignores.
+func New(cfg Config, messenger Messenger, selfID string, logger *slog.Logger) *Bot {
+	if messenger == nil {
+		panic("bot: nil messenger")
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &Bot{
+		cfg:        cfg,
+		messenger:  messenger,
+		selfID:     selfID,
+		logger:     logger,
+		now:        time.Now,
+		runCommand: runCommand,
+	}
+}
+
+// PollResult summarises one poll.
+type PollResult struct {
+	Events  int
+	Replies int
+}
+
+// Run polls until ctx is cancelled, waiting Interval between polls or
+// longer when the rate limit requires it. Errors from a poll are logged and
+// retried on the next tick, except authentication errors which stop the bot.
+func (b *Bot) Run(ctx context.Context) error {
+	return ratelimit.Poll(ctx, b.cfg.Interval.Std(), b.now, func(ctx context.Context) (client.RateLimitSnapshot, error) {
+		result, rateLimits, err := b.Poll(ctx)
+		switch {
+		case ctx.Err() != nil:
+		case client.IsAuth(err):
+			return rateLimits, err
+		case err != nil:
+			b.logger.Warn("dm poll failed", "error", err)
+		case result.Events > 0:
+			b.logger.Info("dm poll", "events", result.Events, "replies", result.Replies)
+		}
+		return rateLimits, nil
+	})
+}
+
+// Poll fetches recent events once and replies to the new ones. The first poll
+// against an empty state only records the existing events, so starting the
+// bot never answers an old backlog.
+func (b *Bot) Poll(ctx context.Context) (PollResult, client.RateLimitSnapshot, error) {
+	state, err := b.loadState()
+	if err != nil {
+		return PollResult{}, client.RateLimitSnapshot{}, err
+	}
+
+	params := map[string]string{
+		"dm_event.fields": eventFields,
+		"event_types":     messageCreateEvent,
+	}
+	response, rateLimits, err := b.messenger.ListEvents(ctx, params)
+	if err != nil {
+		return PollResult{}, rateLimits, err
+	}
+
+	var result PollResult
+	backlog := state.empty()
+	for i := len(response.Data) - 1; i >= 0; i-- {
+		event := response.Data[i]
+		if state.processed(event.ID) {
+			continue
+		}
+		state.markProcessed(event.ID)
+		if backlog || event.SenderID == b.selfID || event.EventType != messageCreateEvent {
+			continue
+		}
+
+		result.Events++
+		replied, err := b.handle(ctx, state, event)
+		if err != nil {
+			if client.IsAuth(err) || client.IsRateLimited(err) {
+				// Leave the event for the next poll.
+				state.unmarkProcessed(event.ID)
+				if saveErr := b.save(state); saveErr != nil {
+					b.logger.Warn("bot state not saved", "error", saveErr)
+				}
+				return result, rateLimits, err
+			}
+			b.logger.Warn("dm reply failed", "event_id", event.ID, "sender_id", event.SenderID, "error", err)
+		}
+		if replied {
+			result.Replies++
+		}
+	}
+
+	if backlog && len(response.Data) > 0 {
+		b.logger.Info("recorded existing dm events without replying", "events", len(response.Data))
+	}
+	if err := b.save(state); err != nil {
+		return result, rateLimits, err
+	}
+	return result, rateLimits, nil
+}
+
+// loadState reads the state file, or during a dry run the state the previous
+// poll left.
+func (b *Bot) loadState() (*state, error) {
+	if b.DryRun && b.dryState != nil {
+		return b.dryState, nil
+	}
+	return loadState(b.cfg.State)
+}
+
+// save writes the state file. A dry run only keeps the state for its next
+// poll.
+func (b *Bot) save(state *state) error {
+	if b.DryRun {
+		if state.Started.IsZero() {
+			state.Started = b.now().UTC()
+		}
+		b.dryState = state
+		return nil
+	}
+	return state.save(b.cfg.State, b.cfg.maxCooldown(), b.now())
+}
+
+// handle replies to one incoming message and reports whether it sent one.
+func (b *Bot) handle(ctx context.Context, state *state, event dm.DMEvent) (bool, error) {
+	now := b.now()
+	logger := b.logger.With("event_id", event.ID, "sender_id", event.SenderID)
+
+	rule, ok := b.cfg.Match(event.Text)
+	if !ok {
+		logger.Debug("no rule matched")
+		return false, nil
+	}
+
+	cooldown := b.cfg.Cooldown.Std()
+	if rule.Cooldown > 0 {
+		cooldown = rule.Cooldown.Std()
+	}
+	if last, ok := state.LastReply[event.SenderID]; ok && now.Sub(last) < cooldown {
+		logger.Debug("sender in cooldown", "rule", rule.Name)
+		return false, nil
+	}
+
+	var reply string
+	if b.cfg.QuietHours.Active(now) {
+		reply = b.cfg.QuietHours.Reply
+		if reply == "" {
+			logger.Debug("quiet hours", "rule", rule.Name)
+			return false, nil
+		}
+	} else if len(rule.Command) > 0 {
+		env := []string{
+			"CTW_SENDER_ID=" + event.SenderID,
+			"CTW_CONVERSATION_ID=" + event.DMConversationID,
+			"CTW_EVENT_ID=" + event.ID,
+			"CTW_RULE=" + rule.Name,
+		}
+		cmdCtx, cancel := context.WithTimeout(ctx, b.cfg.CommandTimeout.Std())
+		output, err := b.runCommand(cmdCtx, rule.Command, event.Text, env)
+		cancel()
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", rule.Name, err)
+		}
+		reply = strings.TrimSpace(output)
+	} else {
+		reply = rule.Reply
+	}
+	if reply == "" {
+		return false, nil
+	}
+
+	if b.DryRun {
+		logger.Info("dry run: would reply", "rule", rule.Name, "conversation_id", event.DMConversationID, "text", reply)
+		return true, nil
+	}
+	if event.DMConversationID == "" {
+		return false, errors.New("event has no conversation id")
+	}
+	if _, _, err := b.messenger.SendToConversation(ctx, event.DMConversationID, dm.SendDMRequest{Text: reply}); err != nil {
+		return false, err
+	}
+	logger.Info("replied", "rule", rule.Name, "conversation_id", event.DMConversationID)
+	state.LastReply[event.SenderID] = now.UTC()
+	return true, nil
+}
+
+func runCommand(ctx context.Context, command []string, stdin string, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/dm"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMessenger struct {
+	events  []dm.DMEvent
+	replies map[string][]string
+}
+
+func (f *fakeMessenger) ListEvents(ctx context.Context, params map[string]string) (dm.DMEventsResponse, client.RateLimitSnapshot, error) {
+	// The API lists newest first.
+	data := make([]dm.DMEvent, len(f.events))
+	for i, event := range f.events {
+		data[len(f.events)-1-i] = event
+	}
+	return dm.DMEventsResponse{Data: data}, client.RateLimitSnapshot{Limit: -1, Remaining: -1, Reset: -1}, nil
+}
+
+func (f *fakeMessenger) SendToConversation(ctx context.Context, conversationID string, req dm.SendDMRequest) (dm.SendDMResponse, client.RateLimitSnapshot, error) {
+	if f.replies == nil {
+		f.replies = map[string][]string{}
+	}
+	f.replies[conversationID] = append(f.replies[conversationID], req.Text)
+	return dm.SendDMResponse{}, client.RateLimitSnapshot{}, nil
+}
+
+func (f *fakeMessenger) receive(id, sender, text string) {
+	f.events = append(f.events, dm.DMEvent{ID: id, EventType: "MessageCreate", SenderID: sender, DMConversationID: "c" + sender, Text: text})
+}
+
+func writeRules(t *testing.T, rules string) Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bot.toml")
+	require.NoError(t, os.WriteFile(path, []byte(rules), 0o644))
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg := writeRules(t, `
+cooldown = "1h"
+
+[[rule]]
+name = "orders"
+regex = '(?i)order\s+#?\d+'
+command = ["./status.sh", "--short"]
+`)
+	require.Equal(t, DefaultInterval, cfg.Interval.Std())
+	require.Equal(t, "bot.state.json", filepath.Base(cfg.State))
+	require.True(t, filepath.IsAbs(cfg.Rules[0].Command[0]))
+
+	rule, ok := cfg.Match("Where is ORDER #42?")
+	require.True(t, ok)
+	require.Equal(t, "orders", rule.Name)
+	_, ok = cfg.Match("hello")
+	require.False(t, ok)
+
+	dir := t.TempDir()
+	for name, rules := range map[string]string{
+		"unknown key":  "colldown = \"1h\"\n[[rule]]\nreply = \"hi\"\n",
+		"no reply":     "[[rule]]\nkeywords = [\"hi\"]\n",
+		"bad regex":    "[[rule]]\nregex = \"(\"\nreply = \"hi\"\n",
+		"no rules":     "interval = \"2m\"\n",
+		"bad quiet hr": "[quiet_hours]\nstart = \"25:00\"\nend = \"07:00\"\n[[rule]]\nreply = \"hi\"\n",
+	} {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".toml")
+		require.NoError(t, os.WriteFile(path, []byte(rules), 0o644))
+		_, err := LoadConfig(path)
+		require.Error(t, err, name)
+	}
+}
+
+func TestPollRepliesToNewMessagesWithCooldown(t *testing.T) {
+	cfg := writeRules(t, `
+cooldown = "1h"
+
+[[rule]]
+name = "refunds"
+keywords = ["refund"]
+reply = "See https://example.com/refunds"
+
+[[rule]]
+name = "fallback"
+command = ["lookup"]
+`)
+	messenger := &fakeMessenger{}
+	messenger.receive("1", "100", "old refund question")
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := New(cfg, messenger, "7", nil)
+	b.now = func() time.Time { return now }
+	b.runCommand = func(ctx context.Context, command []string, stdin string, env []string) (string, error) {
+		require.Equal(t, []string{"lookup"}, command)
+		require.Contains(t, env, "CTW_RULE=fallback")
+		return "you said: " + stdin + "\n", nil
+	}
+
+	result, _, err := b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, result.Events, "existing events are backlog")
+
+	messenger.receive("2", "100", "I want a REFUND")
+	messenger.receive("3", "7", "our own reply")
+	messenger.receive("4", "200", "hello")
+	result, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PollResult{Events: 2, Replies: 2}, result)
+	require.Equal(t, []string{"See https://example.com/refunds"}, messenger.replies["c100"])
+	require.Equal(t, []string{"you said: hello"}, messenger.replies["c200"])
+
+	now = now.Add(30 * time.Minute)
+	messenger.receive("5", "100", "refund please?")
+	result, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PollResult{Events: 1}, result, "sender is in cooldown")
+
+	now = now.Add(time.Hour)
+	messenger.receive("6", "100", "refund!!")
+	_, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, messenger.replies["c100"], 2)
+}
+
+func TestDryRunPollLeavesStateUntouched(t *testing.T) {
+	cfg := writeRules(t, `
+cooldown = "1h"
+
+[[rule]]
+keywords = ["refund"]
+reply = "See https://example.com/refunds"
+`)
+	messenger := &fakeMessenger{}
+	messenger.receive("1", "100", "old refund question")
+
+	b := New(cfg, messenger, "7", nil)
+	_, _, err := b.Poll(context.Background())
+	require.NoError(t, err)
+	before, err := os.ReadFile(cfg.State)
+	require.NoError(t, err)
+
+	b.DryRun = true
+	messenger.receive("2", "100", "refund please")
+	result, _, err := b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PollResult{Events: 1, Replies: 1}, result)
+	messenger.receive("3", "100", "refund?")
+	result, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PollResult{Events: 1, Replies: 1}, result, "a dry run starts no cooldown")
+	require.Empty(t, messenger.replies)
+
+	after, err := os.ReadFile(cfg.State)
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after))
+
+	// A real run still answers the rehearsed messages.
+	b = New(cfg, messenger, "7", nil)
+	result, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Events)
+	require.Len(t, messenger.replies["c100"], 1)
+}
+
+func TestPollQuietHours(t *testing.T) {
+	cfg := writeRules(t, `
+[quiet_hours]
+start = "22:00"
+end = "07:00"
+timezone = "UTC"
+reply = "We're offline until 07:00."
+
+[[rule]]
+reply = "Thanks, an agent will answer shortly."
+`)
+	messenger := &fakeMessenger{}
+	now := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+	b := New(cfg, messenger, "7", nil)
+	b.now = func() time.Time { return now }
+
+	_, _, err := b.Poll(context.Background())
+	require.NoError(t, err)
+
+	messenger.receive("10", "100", "hi")
+	_, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+
+	now = now.Add(8 * time.Hour)
+	messenger.receive("11", "200", "hi")
+	_, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"We're offline until 07:00."}, messenger.replies["c100"])
+	require.Equal(t, []string{"Thanks, an agent will answer shortly."}, messenger.replies["c200"])
+}
@@ -0,0 +1,221 @@
+// Package bot runs a rule-based direct message auto-responder.
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/config"
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	// DefaultInterval polls GET /2/dm_events 15 times per 15 minutes, the
+	// per-user limit.
+	DefaultInterval = time.Minute
+	// DefaultCommandTimeout bounds how long a reply command may run.
+	DefaultCommandTimeout = 30 * time.Second
+)
+
+// Config is the bot's rules file.
+//
+//	interval = "1m"
+//	cooldown = "1h"
+//
+//	[quiet_hours]
+//	start = "22:00"
+//	end = "07:00"
+//	timezone = "Europe/Stockholm"
+//	reply = "We're offline until 07:00 and will get back to you."
+//
+//	[[rule]]
+//	name = "refunds"
+//	keywords = ["refund", "money back"]
+//	reply = "Refunds are handled at https://example.com/refunds."
+//
+//	[[rule]]
+//	name = "orders"
+//	regex = '(?i)order\s+#?\d+'
+//	command = ["./order-status.sh"]
+type Config struct {
+	// Interval is the time between polls; it is stretched when rate-limit
+	// headers ask for slower polling.
+	Interval config.Duration `toml:"interval"`
+	// Cooldown is the minimum time between replies to the same user.
+	Cooldown config.Duration `toml:"cooldown"`
+	// State is the file recording processed events and cooldowns, relative
+	// to the rules file. It defaults to <rules name>.state.json.
+	State string `toml:"state"`
+	// CommandTimeout bounds reply commands.
+	CommandTimeout config.Duration `toml:"command_timeout"`
+
+	QuietHours *QuietHours `toml:"quiet_hours"`
+	Rules      []Rule      `toml:"rule"`
+}
+
+// QuietHours is a daily window in which rules do not reply. Reply, when set,
+// is sent instead, subject to the usual cooldown.
+type QuietHours struct {
+	Start    string `toml:"start"`
+	End      string `toml:"end"`
+	Timezone string `toml:"timezone"`
+	Reply    string `toml:"reply"`
+
+	start, end time.Duration
+	location   *time.Location
+}
+
+// Rule matches a message when any keyword occurs in it (case-insensitively)
+// or the regex matches. A rule without keywords or regex matches every
+// message, so it belongs last as a fallback. The first matching rule wins.
+//
+// The reply is Reply, or the standard output of Command, which receives the
+// message text on stdin and CTW_SENDER_ID, CTW_CONVERSATION_ID, CTW_EVENT_ID
+// and CTW_RULE in its environment. Empty output sends nothing.
+type Rule struct {
+	Name     string          `toml:"name"`
+	Keywords []string        `toml:"keywords"`
+	Regex    string          `toml:"regex"`
+	Reply    string          `toml:"reply"`
+	Command  []string        `toml:"command"`
+	Cooldown config.Duration `toml:"cooldown"`
+
+	pattern *regexp.Regexp
+}
+
+// LoadConfig reads and validates a rules file. Relative paths in it are
+// resolved against the file's directory.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	meta, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
+		return Config{}, fmt.Errorf("bot: read rules: %w", err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return Config{}, fmt.Errorf("bot: unknown key %q in %s", undecoded[0].String(), path)
+	}
+
+	dir := filepath.Dir(path)
+	if cfg.State == "" {
+		cfg.State = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + ".state.json"
+	}
+	if !filepath.IsAbs(cfg.State) {
+		cfg.State = filepath.Join(dir, cfg.State)
+	}
+	for i := range cfg.Rules {
+		if command := cfg.Rules[i].Command; len(command) > 0 && !filepath.IsAbs(command[0]) && strings.ContainsRune(command[0], filepath.Separator) {
+			command[0] = filepath.Join(dir, command[0])
+		}
+	}
+
+	if err := cfg.compile(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// compile fills defaults and prepares rules and quiet hours for matching.
+func (c *Config) compile() error {
+	if c.Interval <= 0 {
+		c.Interval = config.Duration(DefaultInterval)
+	}
+	if c.CommandTimeout <= 0 {
+		c.CommandTimeout = config.Duration(DefaultCommandTimeout)
+	}
+	if len(c.Rules) == 0 {
+		return errors.New("bot: at least one [[rule]] is required")
+	}
+
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if (rule.Reply == "") == (len(rule.Command) == 0) {
+			return fmt.Errorf("bot: %s: set exactly one of reply or command", rule.Name)
+		}
+		if rule.Regex != "" {
+			pattern, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				return fmt.Errorf("bot: %s: %w", rule.Name, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+
+	if q := c.QuietHours; q != nil {
+		var err error
+		if q.start, err = parseClock(q.Start); err != nil {
+			return fmt.Errorf("bot: quiet_hours.start: %w", err)
+		}
+		if q.end, err = parseClock(q.End); err != nil {
+			return fmt.Errorf("bot: quiet_hours.end: %w", err)
+		}
+		q.location = time.Local
+		if q.Timezone != "" {
+			if q.location, err = time.LoadLocation(q.Timezone); err != nil {
+				return fmt.Errorf("bot: quiet_hours.timezone: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the first rule matching text.
+func (c *Config) Match(text string) (*Rule, bool) {
+	lower := strings.ToLower(text)
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.matches(text, lower) {
+			return rule, true
+		}
+	}
+	return nil, false
+}
+
+func (r *Rule) matches(text, lower string) bool {
+	if len(r.Keywords) == 0 && r.pattern == nil {
+		return true
+	}
+	for _, keyword := range r.Keywords {
+		if keyword != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return r.pattern != nil && r.pattern.MatchString(text)
+}
+
+// maxCooldown is the longest cooldown any rule applies.
+func (c *Config) maxCooldown() time.Duration {
+	longest := c.Cooldown.Std()
+	for _, rule := range c.Rules {
+		longest = max(longest, rule.Cooldown.Std())
+	}
+	return longest
+}
+
+// Active reports whether t falls inside the quiet window. Windows that end
+// before they start wrap past midnight.
+func (q *QuietHours) Active(t time.Time) bool {
+	if q == nil || q.start == q.end {
+		return false
+	}
+	local := t.In(q.location)
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if q.start < q.end {
+		return clock >= q.start && clock < q.end
+	}
+	return clock >= q.start || clock < q.end
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/utils"
+)
+
+// maxProcessed bounds the remembered event ids. A poll returns at most 100
+// events, so older ids can never reappear.
+const maxProcessed = 500
+
+// state is what the bot remembers between polls and restarts.
+type state struct {
+	// Started is set by the first save; before it, every event is backlog.
+	Started   time.Time            `json:"started,omitzero"`
+	Processed []string             `json:"processed"`
+	LastReply map[string]time.Time `json:"last_reply"`
+
+	seen map[string]bool
+}
+
+func loadState(path string) (*state, error) {
+	s := &state{LastReply: map[string]time.Time{}, seen: map[string]bool{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bot: read state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("bot: decode state %s: %w", path, err)
+	}
+	if s.LastReply == nil {
+		s.LastReply = map[string]time.Time{}
+	}
+	for _, id := range s.Processed {
+		s.seen[id] = true
+	}
+	return s, nil
+}
+
+// empty reports whether the bot has never polled with this state.
+func (s *state) empty() bool {
+	return s.Started.IsZero()
+}
+
+func (s *state) processed(id string) bool {
+	return s.seen[id]
+}
+
+func (s *state) markProcessed(id string) {
+	s.seen[id] = true
+}
+
+func (s *state) unmarkProcessed(id string) {
+	delete(s.seen, id)
+}
+
+// save writes the state atomically, keeping the newest maxProcessed ids and
+// dropping cooldowns older than keepReplies.
+func (s *state) save(path string, keepReplies time.Duration, now time.Time) error {
+	ids := make([]string, 0, len(s.seen))
+	for id := range s.seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return model.NewerID(ids[i], ids[j]) })
+	if len(ids) > maxProcessed {
+		ids = ids[:maxProcessed]
+	}
+	s.Processed = ids
+	if s.Started.IsZero() {
+		s.Started = now.UTC()
+	}
+
+	for sender, at := range s.LastReply {
+		if now.Sub(at) > keepReplies {
+			delete(s.LastReply, sender)
+		}
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("bot: encode state: %w", err)
+	}
+	if err := utils.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("bot: write state: %w", err)
+	}
+	return nil
+}
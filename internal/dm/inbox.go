@@ -0,0 +1,409 @@
+package dm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/utils"
+)
+
+// Parameters requesting every DM event field and expansion, so synced
+// events are self-contained.
+const (
+	SyncEventFields = "id,text,event_type,created_at,dm_conversation_id,sender_id,participant_ids,referenced_tweets,attachments"
+	SyncExpansions  = "attachments.media_keys,referenced_tweets.id,sender_id,participant_ids"
+	SyncMediaFields = "media_key,type,url,preview_image_url,variants,width,height,duration_ms,alt_text"
+	SyncUserFields  = "id,name,username"
+	SyncTweetFields = "id,text,author_id,created_at"
+
+	maxEventsPerPage = "100"
+)
+
+// Layout of a synced inbox directory.
+const (
+	InboxStateFile   = "state.json"
+	ConversationsDir = "conversations"
+	MediaDir         = "media"
+)
+
+// InboxState is persisted between syncs so later runs fetch only newer events.
+type InboxState struct {
+	NewestEventID string    `json:"newest_event_id"`
+	SyncedAt      time.Time `json:"synced_at"`
+}
+
+// StoredEvent is one line of a conversation file: the event with its sender
+// and media resolved from the response includes. Files lists downloaded
+// media relative to the inbox directory.
+type StoredEvent struct {
+	DMEvent
+	Sender *model.User   `json:"sender,omitempty"`
+	Media  []model.Media `json:"media,omitempty"`
+	Tweets []model.Tweet `json:"tweets,omitempty"`
+	Files  []string      `json:"files,omitempty"`
+}
+
+// SyncOptions tunes SyncInbox.
+type SyncOptions struct {
+	// MaxPages bounds how many pages of events are fetched; 0 means no
+	// limit. Events beyond the limit are not fetched by later syncs either.
+	MaxPages int
+	// SkipMedia disables downloading attachments.
+	SkipMedia bool
+}
+
+// SyncResult summarises one sync run.
+type SyncResult struct {
+	Events        int      `json:"events"`
+	Conversations int      `json:"conversations"`
+	MediaFiles    int      `json:"media_files"`
+	MediaErrors   []string `json:"media_errors,omitempty"`
+	NewestEventID string   `json:"newest_event_id,omitempty"`
+}
+
+// EventsSince pages through the authenticated user's DM events, which the API
+// returns newest first, until it reaches sinceID. The newer events come back
+// oldest first with their includes merged. An empty sinceID fetches all the
+// history the API keeps.
+func (s *Service) EventsSince(ctx context.Context, sinceID string, params map[string]string, maxPages int) (DMEventsResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return DMEventsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: nil service")
+	}
+
+	query := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		query[k] = v
+	}
+	if query["max_results"] == "" {
+		query["max_results"] = maxEventsPerPage
+	}
+
+	var (
+		result     DMEventsResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(query, client.PaginationTokenParam, maxPages, func(query map[string]string) (string, error) {
+		page, limits, err := s.list(ctx, dmEventsPath, query)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+
+		for _, event := range page.Data {
+			if sinceID != "" && !model.NewerID(event.ID, sinceID) {
+				// The rest were synced before.
+				page.Meta.NextToken = ""
+				break
+			}
+			result.Data = append(result.Data, event)
+		}
+		result.Includes = result.Includes.Merge(page.Includes)
+		result.Meta.ResultCount = len(result.Data)
+		return page.Meta.NextToken, nil
+	})
+	if err != nil {
+		return DMEventsResponse{}, rateLimits, err
+	}
+
+	for i, j := 0, len(result.Data)-1; i < j; i, j = i+1, j-1 {
+		result.Data[i], result.Data[j] = result.Data[j], result.Data[i]
+	}
+	return result, rateLimits, nil
+}
+
+// SyncInbox brings the inbox in dir up to date. New events are appended to
+// conversations/<conversation id>.ndjson in the order they happened, and
+// attached media is saved under media/ by media key. The newest event id is
+// written to state.json last. An interrupted sync is repeated from the old
+// state on the next run, which skips the events a conversation file already
+// ends with and drops a line left half written. Media that cannot be
+// downloaded is reported in the result rather than failing the sync.
+func (s *Service) SyncInbox(ctx context.Context, dir string, opts SyncOptions) (SyncResult, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return SyncResult{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: nil service")
+	}
+	if dir == "" {
+		return SyncResult{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: inbox directory is required")
+	}
+
+	state, err := readInboxState(dir)
+	if err != nil {
+		return SyncResult{}, client.RateLimitSnapshot{}, err
+	}
+
+	params := map[string]string{
+		"dm_event.fields": SyncEventFields,
+		"expansions":      SyncExpansions,
+		"media.fields":    SyncMediaFields,
+		"user.fields":     SyncUserFields,
+		"tweet.fields":    SyncTweetFields,
+	}
+	events, rateLimits, err := s.EventsSince(ctx, state.NewestEventID, params, opts.MaxPages)
+	if err != nil {
+		return SyncResult{}, rateLimits, err
+	}
+
+	result := SyncResult{Events: len(events.Data), NewestEventID: state.NewestEventID}
+	if len(events.Data) == 0 {
+		return result, rateLimits, nil
+	}
+
+	byConversation := map[string][]StoredEvent{}
+	for _, event := range events.Data {
+		stored := storedEvent(event, events.Includes)
+		if !opts.SkipMedia {
+			for _, media := range stored.Media {
+				file, err := s.downloadMedia(ctx, dir, media)
+				if err != nil {
+					result.MediaErrors = append(result.MediaErrors, fmt.Sprintf("%s: %v", media.MediaKey, err))
+					continue
+				}
+				if file != "" {
+					stored.Files = append(stored.Files, file)
+					result.MediaFiles++
+				}
+			}
+		}
+		key := conversationKey(event)
+		byConversation[key] = append(byConversation[key], stored)
+	}
+
+	keys := make([]string, 0, len(byConversation))
+	for key := range byConversation {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := appendConversation(dir, key, byConversation[key]); err != nil {
+			return result, rateLimits, err
+		}
+	}
+	result.Conversations = len(keys)
+
+	newest := events.Data[len(events.Data)-1].ID
+	if err := writeInboxState(dir, InboxState{NewestEventID: newest, SyncedAt: time.Now().UTC()}); err != nil {
+		return result, rateLimits, err
+	}
+	result.NewestEventID = newest
+	return result, rateLimits, nil
+}
+
+func storedEvent(event DMEvent, includes *model.Includes) StoredEvent {
+	stored := StoredEvent{DMEvent: event}
+	if user, ok := includes.User(event.SenderID); ok {
+		stored.Sender = &user
+	}
+	if includes != nil && event.Attachments != nil {
+		for _, key := range event.Attachments.MediaKeys {
+			for _, media := range includes.Media {
+				if media.MediaKey == key {
+					stored.Media = append(stored.Media, media)
+				}
+			}
+		}
+	}
+	for _, ref := range event.ReferencedTweets {
+		if tweet, ok := includes.Tweet(ref.ID); ok {
+			stored.Tweets = append(stored.Tweets, tweet)
+		}
+	}
+	return stored
+}
+
+// conversationKey names the file an event belongs to.
+func conversationKey(event DMEvent) string {
+	switch {
+	case event.DMConversationID != "":
+		return event.DMConversationID
+	case event.ConversationID != "":
+		return event.ConversationID
+	default:
+		return "unknown"
+	}
+}
+
+// appendConversation appends the events, oldest first, that are newer than
+// the last one stored in the conversation file.
+func appendConversation(dir, key string, events []StoredEvent) error {
+	convDir := filepath.Join(dir, ConversationsDir)
+	if err := os.MkdirAll(convDir, 0o700); err != nil {
+		return fmt.Errorf("dm: create inbox: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(convDir, safeFileName(key)+".ndjson"), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("dm: open conversation: %w", err)
+	}
+	lastID, end, err := lastStoredEvent(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Truncate(end); err != nil {
+		f.Close()
+		return fmt.Errorf("dm: write conversation: %w", err)
+	}
+	if _, err := f.Seek(end, io.SeekStart); err != nil {
+		f.Close()
+		return fmt.Errorf("dm: write conversation: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if lastID != "" && !model.NewerID(event.ID, lastID) {
+			continue
+		}
+		if err := encoder.Encode(event); err != nil {
+			f.Close()
+			return fmt.Errorf("dm: write conversation: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("dm: write conversation: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("dm: write conversation: %w", err)
+	}
+	return nil
+}
+
+// lastStoredEvent returns the id of the last complete line of a conversation
+// file and the offset just past it. A partial line after it, left by an
+// interrupted write, lies beyond that offset.
+func lastStoredEvent(f *os.File) (string, int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, fmt.Errorf("dm: read conversation: %w", err)
+	}
+	// Read backwards until the tail holds the last complete line.
+	const blockSize = 4096
+	var tail []byte
+	offset := info.Size()
+	for offset > 0 && bytes.Count(tail, []byte{'\n'}) < 2 {
+		n := min(blockSize, offset)
+		offset -= n
+		block := make([]byte, n)
+		if _, err := f.ReadAt(block, offset); err != nil {
+			return "", 0, fmt.Errorf("dm: read conversation: %w", err)
+		}
+		tail = append(block, tail...)
+	}
+	end := bytes.LastIndexByte(tail, '\n')
+	if end < 0 {
+		return "", 0, nil
+	}
+	line := tail[bytes.LastIndexByte(tail[:end], '\n')+1 : end]
+	var last struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(line, &last); err != nil {
+		return "", 0, fmt.Errorf("dm: decode conversation: %w", err)
+	}
+	return last.ID, offset + int64(end) + 1, nil
+}
+
+// downloadMedia saves media under media/<key><ext> and returns that path
+// relative to dir. Files already present are kept, and media without a
+// downloadable URL is skipped with an empty path.
+func (s *Service) downloadMedia(ctx context.Context, dir string, media model.Media) (string, error) {
+	source := media.DownloadURL()
+	if source == "" {
+		return "", nil
+	}
+	parsed, err := url.Parse(source)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Join(MediaDir, safeFileName(media.MediaKey)+path.Ext(parsed.Path))
+	target := filepath.Join(dir, name)
+	if _, err := os.Stat(target); err == nil {
+		return name, nil
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, source, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	// DM attachments on ton.twitter.com are only served to the
+	// conversation's members, so that request needs the token. Any other
+	// host named by the response is fetched without credentials.
+	req.Header.Del("Content-Type")
+	if !twitterMediaHost(parsed.Hostname()) {
+		req.Header.Del("Authorization")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer client.SafeClose(resp.Body)
+	if err := client.CheckResponse(resp); err != nil {
+		return "", err
+	}
+
+	if err := utils.CopyFileAtomic(target, resp.Body); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// twitterMediaHost reports whether host serves Twitter media and may be sent
+// the token.
+func twitterMediaHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "ton.twitter.com" || host == "twimg.com" || strings.HasSuffix(host, ".twimg.com")
+}
+
+func readInboxState(dir string) (InboxState, error) {
+	var state InboxState
+	data, err := os.ReadFile(filepath.Join(dir, InboxStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("dm: read inbox state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("dm: decode inbox state: %w", err)
+	}
+	return state, nil
+}
+
+func writeInboxState(dir string, state InboxState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("dm: encode inbox state: %w", err)
+	}
+	if err := utils.WriteFileAtomic(filepath.Join(dir, InboxStateFile), data); err != nil {
+		return fmt.Errorf("dm: write inbox state: %w", err)
+	}
+	return nil
+}
+
+// safeFileName keeps ids usable as file names; conversation ids such as
+// "123-456" pass through unchanged.
+func safeFileName(name string) string {
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-', r == '_':
+		default:
+			runes[i] = '_'
+		}
+	}
+	return string(runes)
+}
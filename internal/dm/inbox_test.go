@@ -0,0 +1,154 @@
+package dm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncInboxIsIncremental(t *testing.T) {
+	var (
+		mediaURL string
+		newest   = "30"
+		calls    []string
+	)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/media/photo.jpg":
+			require.Empty(t, r.Header.Get("Authorization"), "media on a foreign host is fetched without the token")
+			_, _ = w.Write([]byte("jpeg bytes"))
+			return
+		case "/2/dm_events":
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		query := r.URL.Query()
+		require.Equal(t, SyncExpansions, query.Get("expansions"))
+		token := query.Get("pagination_token")
+		calls = append(calls, token)
+
+		switch {
+		case newest == "40" && token == "":
+			_, _ = w.Write([]byte(`{"data":[{"id":"40","text":"new","dm_conversation_id":"1-2","sender_id":"2"},{"id":"30","text":"seen","dm_conversation_id":"1-2","sender_id":"1"}],"meta":{"result_count":2,"next_token":"p2"}}`))
+		case token == "":
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"data":[{"id":"30","text":"pic","dm_conversation_id":"1-2","sender_id":"1","attachments":{"media_keys":["3_9"]}},{"id":"20","text":"group hi","dm_conversation_id":"g7","sender_id":"2"}],"includes":{"users":[{"id":"1","username":"support","name":"Support"}],"media":[{"media_key":"3_9","type":"photo","url":%q}]},"meta":{"result_count":2,"next_token":"p2"}}`, mediaURL)))
+		case token == "p2":
+			_, _ = w.Write([]byte(`{"data":[{"id":"10","text":"first","dm_conversation_id":"1-2","sender_id":"2"}],"meta":{"result_count":1}}`))
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+	mediaURL = server.URL + "/media/photo.jpg"
+
+	c, err := client.New(client.Config{BaseURL: server.URL + "/", BearerToken: "test-token"})
+	require.NoError(t, err)
+	service := NewService(c)
+	dir := t.TempDir()
+
+	result, _, err := service.SyncInbox(context.Background(), dir, SyncOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Events)
+	require.Equal(t, 2, result.Conversations)
+	require.Equal(t, 1, result.MediaFiles)
+	require.Equal(t, "30", result.NewestEventID)
+	require.Equal(t, []string{"", "p2"}, calls)
+
+	events := readConversation(t, filepath.Join(dir, ConversationsDir, "1-2.ndjson"))
+	require.Len(t, events, 2)
+	require.Equal(t, "10", events[0].ID)
+	require.Equal(t, "30", events[1].ID)
+	require.Equal(t, "support", events[1].Sender.Username)
+	require.Equal(t, []string{filepath.Join(MediaDir, "3_9.jpg")}, events[1].Files)
+
+	data, err := os.ReadFile(filepath.Join(dir, MediaDir, "3_9.jpg"))
+	require.NoError(t, err)
+	require.Equal(t, "jpeg bytes", string(data))
+
+	newest, calls = "40", nil
+	result, _, err = service.SyncInbox(context.Background(), dir, SyncOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Events)
+	require.Equal(t, "40", result.NewestEventID)
+	require.Equal(t, []string{""}, calls)
+
+	events = readConversation(t, filepath.Join(dir, ConversationsDir, "1-2.ndjson"))
+	require.Len(t, events, 3)
+	require.Equal(t, "40", events[2].ID)
+}
+
+func TestTwitterMediaHost(t *testing.T) {
+	for host, want := range map[string]bool{
+		"ton.twitter.com":      true,
+		"pbs.twimg.com":        true,
+		"video.twimg.com":      true,
+		"TON.Twitter.com":      true,
+		"twitter.com":          false,
+		"eviltwimg.com":        false,
+		"ton.twitter.com.evil": false,
+		"127.0.0.1":            false,
+	} {
+		require.Equal(t, want, twitterMediaHost(host), host)
+	}
+}
+
+func TestSyncInboxRepeatsInterruptedSync(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"30","text":"again","dm_conversation_id":"1-2","sender_id":"1"},{"id":"20","text":"group hi","dm_conversation_id":"g7","sender_id":"2"},{"id":"10","text":"first","dm_conversation_id":"1-2","sender_id":"2"}],"meta":{"result_count":3}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(client.Config{BaseURL: server.URL + "/", BearerToken: "test-token"})
+	require.NoError(t, err)
+	service := NewService(c)
+	dir := t.TempDir()
+
+	// A sync killed after writing 1-2 in full and half a line of g7, and
+	// before state.json.
+	convDir := filepath.Join(dir, ConversationsDir)
+	require.NoError(t, os.MkdirAll(convDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(convDir, "1-2.ndjson"), []byte(`{"id":"10","text":"first"}`+"\n"+`{"id":"30","text":"again"}`+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(convDir, "g7.ndjson"), []byte(`{"id":"20","te`), 0o600))
+
+	result, _, err := service.SyncInbox(context.Background(), dir, SyncOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "30", result.NewestEventID)
+
+	events := readConversation(t, filepath.Join(convDir, "1-2.ndjson"))
+	require.Len(t, events, 2)
+	require.Equal(t, "10", events[0].ID)
+	require.Equal(t, "30", events[1].ID)
+
+	events = readConversation(t, filepath.Join(convDir, "g7.ndjson"))
+	require.Len(t, events, 1)
+	require.Equal(t, "group hi", events[0].Text)
+}
+
+func readConversation(t *testing.T, path string) []StoredEvent {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []StoredEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event StoredEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
@@ -1,11 +1,31 @@
 // Package dm provides helpers for interacting with Twitter direct message endpoints.
 package dm
 
-import "time"
+import (
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+)
 
 // SendDMRequest captures the payload required to create a direct message.
+// A message needs text, an attachment, or both.
 type SendDMRequest struct {
-	Text string `json:"text"`
+	Text        string       `json:"text,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment references uploaded media, typically in the dm_image or
+// dm_video category.
+type Attachment struct {
+	MediaID string `json:"media_id"`
+}
+
+// CreateConversationRequest starts a group conversation with an initial
+// message.
+type CreateConversationRequest struct {
+	ConversationType string        `json:"conversation_type"`
+	ParticipantIDs   []string      `json:"participant_ids"`
+	Message          SendDMRequest `json:"message"`
 }
 
 // SendDMResponse represents the ACK returned after creating a DM event.
@@ -15,24 +35,35 @@ type SendDMResponse struct {
 
 // SendDMData provides identifiers for the newly created DM event.
 type SendDMData struct {
-	DMEventID string `json:"dm_event_id"`
+	DMConversationID string `json:"dm_conversation_id,omitempty"`
+	DMEventID        string `json:"dm_event_id"`
 }
 
 // DMEventsResponse captures the timeline-style listing of DM events.
 type DMEventsResponse struct {
-	Data []DMEvent `json:"data"`
-	Meta DMMeta    `json:"meta"`
+	Data     []DMEvent       `json:"data"`
+	Includes *model.Includes `json:"includes,omitempty"`
+	Meta     DMMeta          `json:"meta"`
 }
 
 // DMEvent represents a single DM event returned by Twitter.
 type DMEvent struct {
-	ID               string    `json:"id"`
-	Text             string    `json:"text"`
-	EventType        string    `json:"event_type"`
-	ConversationID   string    `json:"conversation_id"`
-	DMConversationID string    `json:"dm_conversation_id"`
-	SenderID         string    `json:"sender_id"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               string                  `json:"id"`
+	Text             string                  `json:"text"`
+	EventType        string                  `json:"event_type"`
+	ConversationID   string                  `json:"conversation_id"`
+	DMConversationID string                  `json:"dm_conversation_id"`
+	SenderID         string                  `json:"sender_id"`
+	ParticipantIDs   []string                `json:"participant_ids,omitempty"`
+	ReferencedTweets []model.ReferencedTweet `json:"referenced_tweets,omitempty"`
+	Attachments      *DMMedia                `json:"attachments,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+}
+
+// DMMedia lists the media attached to a DM event; expand
+// attachments.media_keys to receive the objects in Includes.
+type DMMedia struct {
+	MediaKeys []string `json:"media_keys,omitempty"`
 }
 
 // DMMeta contains pagination metadata for DM listings.
@@ -10,9 +10,14 @@ import (
 )
 
 const (
-	dmEventsPath             = "/2/dm_events"
-	dmConversationPathFormat = "/2/dm_conversations/%s/messages"
-	dmWithUserPathFormat     = "/2/dm_conversations/with/%s/messages"
+	dmEventsPath                   = "/2/dm_events"
+	dmConversationsPath            = "/2/dm_conversations"
+	dmConversationPathFormat       = "/2/dm_conversations/%s/messages"
+	dmWithUserPathFormat           = "/2/dm_conversations/with/%s/messages"
+	dmConversationEventsPathFormat = "/2/dm_conversations/%s/dm_events"
+	dmWithUserEventsPathFormat     = "/2/dm_conversations/with/%s/dm_events"
+
+	groupConversationType = "Group"
 )
 
 // Service coordinates Twitter direct message operations.
@@ -36,8 +41,8 @@ func (s *Service) SendToUser(ctx context.Context, participantID string, req Send
 	if participantID == "" {
 		return SendDMResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: participant id is required")
 	}
-	if strings.TrimSpace(req.Text) == "" {
-		return SendDMResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: text is required")
+	if err := req.validate(); err != nil {
+		return SendDMResponse{}, client.RateLimitSnapshot{}, err
 	}
 
 	path := fmt.Sprintf(dmWithUserPathFormat, participantID)
@@ -52,15 +57,49 @@ func (s *Service) SendToConversation(ctx context.Context, conversationID string,
 	if conversationID == "" {
 		return SendDMResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: conversation id is required")
 	}
-	if strings.TrimSpace(req.Text) == "" {
-		return SendDMResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: text is required")
+	if err := req.validate(); err != nil {
+		return SendDMResponse{}, client.RateLimitSnapshot{}, err
 	}
 
 	path := fmt.Sprintf(dmConversationPathFormat, conversationID)
 	return s.send(ctx, path, req)
 }
 
-func (s *Service) send(ctx context.Context, path string, req SendDMRequest) (SendDMResponse, client.RateLimitSnapshot, error) {
+// CreateGroupConversation starts a group conversation with the participants
+// and sends message as its first event. The response carries both the new
+// conversation id and the event id.
+func (s *Service) CreateGroupConversation(ctx context.Context, participantIDs []string, message SendDMRequest) (SendDMResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return SendDMResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: nil service")
+	}
+	if len(participantIDs) < 2 {
+		return SendDMResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: a group conversation needs at least two participants")
+	}
+	if err := message.validate(); err != nil {
+		return SendDMResponse{}, client.RateLimitSnapshot{}, err
+	}
+
+	req := CreateConversationRequest{
+		ConversationType: groupConversationType,
+		ParticipantIDs:   participantIDs,
+		Message:          message,
+	}
+	return s.send(ctx, dmConversationsPath, req)
+}
+
+func (r SendDMRequest) validate() error {
+	if strings.TrimSpace(r.Text) == "" && len(r.Attachments) == 0 {
+		return fmt.Errorf("dm: text or attachment is required")
+	}
+	for _, attachment := range r.Attachments {
+		if attachment.MediaID == "" {
+			return fmt.Errorf("dm: attachment media id is required")
+		}
+	}
+	return nil
+}
+
+func (s *Service) send(ctx context.Context, path string, req any) (SendDMResponse, client.RateLimitSnapshot, error) {
 	resp, err := s.client.Post(ctx, path, req, nil)
 	if err != nil {
 		return SendDMResponse{}, client.RateLimitSnapshot{}, err
@@ -85,8 +124,33 @@ func (s *Service) ListEvents(ctx context.Context, params map[string]string) (DME
 	if s == nil {
 		return DMEventsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: nil service")
 	}
+	return s.list(ctx, dmEventsPath, params)
+}
+
+// ListConversationEvents returns the events of one conversation, 1:1 or group.
+func (s *Service) ListConversationEvents(ctx context.Context, conversationID string, params map[string]string) (DMEventsResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return DMEventsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: nil service")
+	}
+	if conversationID == "" {
+		return DMEventsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: conversation id is required")
+	}
+	return s.list(ctx, fmt.Sprintf(dmConversationEventsPathFormat, conversationID), params)
+}
+
+// ListEventsWithUser returns the events of the 1:1 conversation with a participant.
+func (s *Service) ListEventsWithUser(ctx context.Context, participantID string, params map[string]string) (DMEventsResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return DMEventsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: nil service")
+	}
+	if participantID == "" {
+		return DMEventsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("dm: participant id is required")
+	}
+	return s.list(ctx, fmt.Sprintf(dmWithUserEventsPathFormat, participantID), params)
+}
 
-	resp, err := s.client.Get(ctx, dmEventsPath, params)
+func (s *Service) list(ctx context.Context, path string, params map[string]string) (DMEventsResponse, client.RateLimitSnapshot, error) {
+	resp, err := s.client.Get(ctx, path, params)
 	if err != nil {
 		return DMEventsResponse{}, client.RateLimitSnapshot{}, err
 	}
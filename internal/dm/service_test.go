@@ -77,3 +77,78 @@ func TestDeleteEvent(t *testing.T) {
 	require.Equal(t, 4, rateLimits.Remaining)
 	require.Equal(t, 300, rateLimits.Reset)
 }
+
+func TestSendToConversationWithAttachment(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/2/dm_conversations/conv-1/messages", r.URL.Path)
+
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.NotContains(t, payload, "text")
+		require.Equal(t, []any{map[string]any{"media_id": "555"}}, payload["attachments"])
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"dm_conversation_id":"conv-1","dm_event_id":"event-3"}}`))
+	}
+
+	service := newTestService(t, handler)
+
+	resp, _, err := service.SendToConversation(context.Background(), "conv-1", SendDMRequest{Attachments: []Attachment{{MediaID: "555"}}})
+	require.NoError(t, err)
+	require.Equal(t, "event-3", resp.Data.DMEventID)
+
+	_, _, err = service.SendToConversation(context.Background(), "conv-1", SendDMRequest{})
+	require.EqualError(t, err, "dm: text or attachment is required")
+}
+
+func TestCreateGroupConversation(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/2/dm_conversations", r.URL.Path)
+
+		var payload CreateConversationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.Equal(t, "Group", payload.ConversationType)
+		require.Equal(t, []string{"1", "2"}, payload.ParticipantIDs)
+		require.Equal(t, "welcome", payload.Message.Text)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"dm_conversation_id":"group-1","dm_event_id":"event-4"}}`))
+	}
+
+	service := newTestService(t, handler)
+
+	resp, _, err := service.CreateGroupConversation(context.Background(), []string{"1", "2"}, SendDMRequest{Text: "welcome"})
+	require.NoError(t, err)
+	require.Equal(t, "group-1", resp.Data.DMConversationID)
+	require.Equal(t, "event-4", resp.Data.DMEventID)
+
+	_, _, err = service.CreateGroupConversation(context.Background(), []string{"1"}, SendDMRequest{Text: "welcome"})
+	require.Error(t, err)
+}
+
+func TestListConversationEvents(t *testing.T) {
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		paths = append(paths, r.URL.Path)
+		require.Equal(t, "attachments.media_keys", r.URL.Query().Get("expansions"))
+		_, _ = w.Write([]byte(`{"data":[{"id":"event-5","event_type":"MessageCreate","attachments":{"media_keys":["3_1"]}}],"includes":{"media":[{"media_key":"3_1","type":"photo"}]},"meta":{"result_count":1}}`))
+	}
+
+	service := newTestService(t, handler)
+	params := map[string]string{"expansions": "attachments.media_keys"}
+
+	resp, _, err := service.ListConversationEvents(context.Background(), "conv-1", params)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, []string{"3_1"}, resp.Data[0].Attachments.MediaKeys)
+	require.NotNil(t, resp.Includes)
+	require.Len(t, resp.Includes.Media, 1)
+
+	_, _, err = service.ListEventsWithUser(context.Background(), "123", params)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/2/dm_conversations/conv-1/dm_events", "/2/dm_conversations/with/123/dm_events"}, paths)
+}
@@ -0,0 +1,278 @@
+// Package fields describes the Twitter v2 field and expansion catalog and the
+// named presets ctw offers on top of it, so commands can validate selections
+// before a request is sent.
+package fields
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resource identifies the primary object an endpoint returns. Tweet and user
+// endpoints accept different expansions.
+type Resource int
+
+const (
+	// Tweets covers endpoints whose data is tweets (search, lookup, timelines, likes, bookmarks, streams).
+	Tweets Resource = iota
+	// Users covers endpoints whose data is users (user lookup, retweeters, followers).
+	Users
+)
+
+// Query parameter names for each field group.
+const (
+	TweetParam     = "tweet.fields"
+	UserParam      = "user.fields"
+	MediaParam     = "media.fields"
+	PollParam      = "poll.fields"
+	PlaceParam     = "place.fields"
+	ExpansionParam = "expansions"
+)
+
+// Preset names accepted by Preset and by Parse.
+const (
+	PresetMinimal   = "minimal"
+	PresetAnalytics = "analytics"
+	PresetFull      = "full"
+)
+
+// Presets lists the preset names in increasing order of detail.
+var Presets = []string{PresetMinimal, PresetAnalytics, PresetFull}
+
+var (
+	// TweetFields is the v2 tweet.fields catalog.
+	TweetFields = []string{
+		"attachments", "author_id", "card_uri", "context_annotations", "conversation_id",
+		"created_at", "display_text_range", "edit_controls", "edit_history_tweet_ids", "entities",
+		"geo", "id", "in_reply_to_user_id", "lang", "non_public_metrics", "note_tweet",
+		"organic_metrics", "possibly_sensitive", "promoted_metrics", "public_metrics",
+		"referenced_tweets", "reply_settings", "source", "text", "withheld",
+	}
+
+	// UserFields is the v2 user.fields catalog.
+	UserFields = []string{
+		"affiliation", "connection_status", "created_at", "description", "entities", "id",
+		"location", "most_recent_tweet_id", "name", "pinned_tweet_id", "profile_banner_url",
+		"profile_image_url", "protected", "public_metrics", "receives_your_dm",
+		"subscription_type", "url", "username", "verified", "verified_type", "withheld",
+	}
+
+	// MediaFields is the v2 media.fields catalog.
+	MediaFields = []string{
+		"alt_text", "duration_ms", "height", "media_key", "non_public_metrics", "organic_metrics",
+		"preview_image_url", "promoted_metrics", "public_metrics", "type", "url", "variants", "width",
+	}
+
+	// PollFields is the v2 poll.fields catalog.
+	PollFields = []string{"duration_minutes", "end_datetime", "id", "options", "voting_status"}
+
+	// PlaceFields is the v2 place.fields catalog.
+	PlaceFields = []string{"contained_within", "country", "country_code", "full_name", "geo", "id", "name", "place_type"}
+
+	// TweetExpansions are the expansions accepted by tweet endpoints.
+	TweetExpansions = []string{
+		"attachments.media_keys", "attachments.poll_ids", "author_id", "edit_history_tweet_ids",
+		"entities.mentions.username", "geo.place_id", "in_reply_to_user_id",
+		"referenced_tweets.id", "referenced_tweets.id.author_id",
+	}
+
+	// UserExpansions are the expansions accepted by user endpoints.
+	UserExpansions = []string{"affiliation.user_id", "most_recent_tweet_id", "pinned_tweet_id"}
+)
+
+// privateMetrics need user-context auth and make app-only requests fail, so
+// the full preset leaves them out. They remain valid for explicit selection.
+var privateMetrics = map[string]bool{
+	"non_public_metrics": true,
+	"organic_metrics":    true,
+	"promoted_metrics":   true,
+}
+
+// Set is a selection of fields and expansions for one request.
+type Set struct {
+	Tweet      []string
+	User       []string
+	Media      []string
+	Poll       []string
+	Place      []string
+	Expansions []string
+}
+
+// Preset returns the named preset for resource.
+func Preset(resource Resource, name string) (Set, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case PresetMinimal:
+		if resource == Users {
+			return Set{User: []string{"created_at"}}, nil
+		}
+		return Set{Tweet: []string{"author_id", "created_at"}}, nil
+	case PresetAnalytics:
+		if resource == Users {
+			return Set{
+				User: []string{"created_at", "description", "location", "public_metrics", "verified"},
+			}, nil
+		}
+		return Set{
+			Tweet: []string{
+				"author_id", "context_annotations", "conversation_id", "created_at", "entities",
+				"lang", "public_metrics", "referenced_tweets",
+			},
+			User:       []string{"name", "public_metrics", "username", "verified"},
+			Expansions: []string{"author_id"},
+		}, nil
+	case PresetFull:
+		set := Set{
+			Tweet: public(TweetFields),
+			User:  public(UserFields),
+		}
+		if resource == Users {
+			set.Expansions = []string{"pinned_tweet_id"}
+			return set, nil
+		}
+		set.Media = public(MediaFields)
+		set.Poll = append([]string(nil), PollFields...)
+		set.Place = append([]string(nil), PlaceFields...)
+		set.Expansions = append([]string(nil), TweetExpansions...)
+		return set, nil
+	default:
+		return Set{}, fmt.Errorf("unknown field preset %q (valid: %s)", name, strings.Join(Presets, ", "))
+	}
+}
+
+// Parse splits a comma-separated flag value for param into names. Items that
+// name a preset expand to that preset's selection for the same group, so
+// "analytics,source" is accepted.
+func Parse(resource Resource, param string, values []string) ([]string, error) {
+	var names []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if isPreset(item) {
+				preset, err := Preset(resource, item)
+				if err != nil {
+					return nil, err
+				}
+				names = append(names, preset.group(param)...)
+				continue
+			}
+			names = append(names, item)
+		}
+	}
+	return dedupe(names), nil
+}
+
+// Merge returns the union of s and other, keeping first-seen order.
+func (s Set) Merge(other Set) Set {
+	return Set{
+		Tweet:      dedupe(append(append([]string(nil), s.Tweet...), other.Tweet...)),
+		User:       dedupe(append(append([]string(nil), s.User...), other.User...)),
+		Media:      dedupe(append(append([]string(nil), s.Media...), other.Media...)),
+		Poll:       dedupe(append(append([]string(nil), s.Poll...), other.Poll...)),
+		Place:      dedupe(append(append([]string(nil), s.Place...), other.Place...)),
+		Expansions: dedupe(append(append([]string(nil), s.Expansions...), other.Expansions...)),
+	}
+}
+
+// Validate reports every name that is not in the catalog for its group.
+func (s Set) Validate(resource Resource) error {
+	expansions := TweetExpansions
+	if resource == Users {
+		expansions = UserExpansions
+	}
+
+	var problems []string
+	check := func(param string, names, catalog []string) {
+		valid := make(map[string]bool, len(catalog))
+		for _, name := range catalog {
+			valid[name] = true
+		}
+		for _, name := range names {
+			if !valid[name] {
+				problems = append(problems, fmt.Sprintf("unknown %s value %q (valid: %s)", param, name, strings.Join(catalog, ",")))
+			}
+		}
+	}
+	check(TweetParam, s.Tweet, TweetFields)
+	check(UserParam, s.User, UserFields)
+	check(MediaParam, s.Media, MediaFields)
+	check(PollParam, s.Poll, PollFields)
+	check(PlaceParam, s.Place, PlaceFields)
+	check(ExpansionParam, s.Expansions, expansions)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid field selection:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// Params renders the selection as query parameters, omitting empty groups.
+func (s Set) Params() map[string]string {
+	params := make(map[string]string)
+	for _, param := range []string{TweetParam, UserParam, MediaParam, PollParam, PlaceParam, ExpansionParam} {
+		if names := s.group(param); len(names) > 0 {
+			params[param] = strings.Join(names, ",")
+		}
+	}
+	return params
+}
+
+// IsZero reports whether the selection is empty.
+func (s Set) IsZero() bool {
+	return len(s.Params()) == 0
+}
+
+func (s Set) group(param string) []string {
+	switch param {
+	case TweetParam:
+		return s.Tweet
+	case UserParam:
+		return s.User
+	case MediaParam:
+		return s.Media
+	case PollParam:
+		return s.Poll
+	case PlaceParam:
+		return s.Place
+	case ExpansionParam:
+		return s.Expansions
+	}
+	return nil
+}
+
+func isPreset(name string) bool {
+	for _, preset := range Presets {
+		if strings.EqualFold(name, preset) {
+			return true
+		}
+	}
+	return false
+}
+
+func public(catalog []string) []string {
+	out := make([]string, 0, len(catalog))
+	for _, name := range catalog {
+		if !privateMetrics[name] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func dedupe(names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package fields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpandsPresetNames(t *testing.T) {
+	names, err := Parse(Tweets, TweetParam, []string{"minimal,source", "author_id"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"author_id", "created_at", "source"}, names)
+}
+
+func TestPresetFullSkipsPrivateMetrics(t *testing.T) {
+	set, err := Preset(Tweets, PresetFull)
+	require.NoError(t, err)
+	require.Contains(t, set.Tweet, "public_metrics")
+	require.NotContains(t, set.Tweet, "non_public_metrics")
+	require.NotContains(t, set.Media, "organic_metrics")
+	require.NoError(t, set.Validate(Tweets))
+
+	users, err := Preset(Users, PresetFull)
+	require.NoError(t, err)
+	require.Equal(t, []string{"pinned_tweet_id"}, users.Expansions)
+	require.NoError(t, users.Validate(Users))
+}
+
+func TestPresetRejectsUnknownName(t *testing.T) {
+	_, err := Preset(Tweets, "everything")
+	require.ErrorContains(t, err, `unknown field preset "everything"`)
+}
+
+func TestValidateReportsEveryUnknownName(t *testing.T) {
+	set := Set{
+		Tweet:      []string{"public_metrics", "like_count"},
+		Expansions: []string{"pinned_tweet_id"},
+	}
+	err := set.Validate(Tweets)
+	require.ErrorContains(t, err, `unknown tweet.fields value "like_count"`)
+	require.ErrorContains(t, err, `unknown expansions value "pinned_tweet_id"`)
+	require.NoError(t, Set{Expansions: []string{"pinned_tweet_id"}}.Validate(Users))
+}
+
+func TestMergeAndParams(t *testing.T) {
+	set := Set{Tweet: []string{"author_id"}}.Merge(Set{Tweet: []string{"author_id", "lang"}, Expansions: []string{"author_id"}})
+	require.Equal(t, map[string]string{
+		TweetParam:     "author_id,lang",
+		ExpansionParam: "author_id",
+	}, set.Params())
+	require.True(t, Set{}.IsZero())
+}
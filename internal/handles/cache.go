@@ -0,0 +1,129 @@
+// Package handles resolves @handles to user IDs. Resolved IDs are kept in an
+// on-disk cache so scripts that name the same accounts on every run do not
+// spend paid reads looking them up again.
+package handles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/utils"
+)
+
+// CacheFile is the cache's file name inside the cache directory.
+const CacheFile = "handles.json"
+
+// DefaultTTL is how long a resolved handle is trusted. Handles rarely move
+// between accounts, but renames do happen.
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Entry is a cached handle→ID mapping.
+type Entry struct {
+	ID         string    `json:"id"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// Cache is a TTL'd handle→ID map persisted as JSON. A nil *Cache is valid and
+// caches nothing.
+type Cache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]Entry
+	dirty   bool
+}
+
+// OpenCache loads the cache at path. A missing file yields an empty cache;
+// an unreadable one is discarded, since every entry can be fetched again.
+func OpenCache(path string, ttl time.Duration) (*Cache, error) {
+	c := &Cache{path: path, ttl: ttl, now: time.Now, entries: map[string]Entry{}}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("handles: read cache: %w", err)
+	}
+	if err := json.Unmarshal(b, &c.entries); err != nil {
+		c.entries = map[string]Entry{}
+		c.dirty = true
+	}
+	return c, nil
+}
+
+// Get returns the cached ID for username if it has not expired.
+func (c *Cache) Get(username string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[Normalize(username)]
+	if !ok || c.now().Sub(entry.ResolvedAt) > c.ttl {
+		return "", false
+	}
+	return entry.ID, true
+}
+
+// Put records username's ID.
+func (c *Cache) Put(username, id string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[Normalize(username)] = Entry{ID: id, ResolvedAt: c.now().UTC()}
+	c.dirty = true
+}
+
+// Save writes the cache back to disk if it changed, dropping expired
+// entries. The file is replaced atomically so concurrent runs never read a
+// partial write.
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+	now := c.now()
+	for username, entry := range c.entries {
+		if now.Sub(entry.ResolvedAt) > c.ttl {
+			delete(c.entries, username)
+		}
+	}
+
+	b, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("handles: encode cache: %w", err)
+	}
+	if err := utils.WriteFileAtomic(c.path, b); err != nil {
+		return fmt.Errorf("handles: write cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// IsHandle reports whether value names a user by @handle rather than by ID.
+func IsHandle(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "@")
+}
+
+// Normalize strips the leading @ and lower-cases username; handles are
+// case-insensitive.
+func Normalize(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
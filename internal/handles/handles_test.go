@@ -0,0 +1,104 @@
+package handles
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLookup struct {
+	calls [][]string
+	known map[string]string
+}
+
+func (f *fakeLookup) LookupUsernames(_ context.Context, usernames []string, _ map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	f.calls = append(f.calls, append([]string(nil), usernames...))
+	var response model.UsersResponse
+	for _, username := range usernames {
+		if id, ok := f.known[username]; ok {
+			response.Data = append(response.Data, model.User{ID: id, Username: strings.ToUpper(username)})
+		}
+	}
+	return response, client.RateLimitSnapshot{}, nil
+}
+
+func TestResolveBatchesAndSkipsUnknown(t *testing.T) {
+	lookup := &fakeLookup{known: map[string]string{}}
+	var usernames []string
+	for i := range 150 {
+		name := fmt.Sprintf("user%d", i)
+		usernames = append(usernames, "@"+name)
+		lookup.known[name] = fmt.Sprint(1000 + i)
+	}
+	usernames = append(usernames, "@ghost", "@USER0")
+
+	ids, _, err := NewResolver(lookup, nil).Resolve(context.Background(), usernames)
+	require.NoError(t, err)
+	require.Len(t, lookup.calls, 2)
+	require.Len(t, lookup.calls[0], BatchSize)
+	require.Len(t, lookup.calls[1], 51)
+	require.Len(t, ids, 150)
+	require.Equal(t, "1000", ids["user0"])
+	_, ok := ids["ghost"]
+	require.False(t, ok)
+}
+
+func TestResolveUsesCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CacheFile)
+	cache, err := OpenCache(path, time.Hour)
+	require.NoError(t, err)
+
+	lookup := &fakeLookup{known: map[string]string{"gopher": "42"}}
+	ids, _, err := NewResolver(lookup, cache).Resolve(context.Background(), []string{"@Gopher"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"gopher": "42"}, ids)
+	require.NoError(t, cache.Save())
+
+	reopened, err := OpenCache(path, time.Hour)
+	require.NoError(t, err)
+	ids, _, err = NewResolver(lookup, reopened).Resolve(context.Background(), []string{"gopher"})
+	require.NoError(t, err)
+	require.Equal(t, "42", ids["gopher"])
+	require.Len(t, lookup.calls, 1)
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CacheFile)
+	cache, err := OpenCache(path, time.Hour)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	cache.Put("@gopher", "42")
+
+	id, ok := cache.Get("GOPHER")
+	require.True(t, ok)
+	require.Equal(t, "42", id)
+
+	now = now.Add(2 * time.Hour)
+	_, ok = cache.Get("gopher")
+	require.False(t, ok)
+
+	require.NoError(t, cache.Save())
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(b))
+}
+
+func TestOpenCacheDiscardsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CacheFile)
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	cache, err := OpenCache(path, time.Hour)
+	require.NoError(t, err)
+	_, ok := cache.Get("gopher")
+	require.False(t, ok)
+}
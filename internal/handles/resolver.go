@@ -0,0 +1,78 @@
+package handles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+)
+
+// BatchSize is the most usernames GET /2/users/by accepts per request.
+const BatchSize = 100
+
+// UsernameLookup is the subset of the users lookup service the resolver
+// needs.
+type UsernameLookup interface {
+	LookupUsernames(ctx context.Context, usernames []string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error)
+}
+
+// Resolver maps handles to user IDs, consulting the cache first.
+type Resolver struct {
+	lookup UsernameLookup
+	cache  *Cache
+}
+
+// NewResolver constructs a Resolver. cache may be nil to always hit the API.
+func NewResolver(lookup UsernameLookup, cache *Cache) *Resolver {
+	if lookup == nil {
+		panic("handles: nil lookup")
+	}
+	return &Resolver{lookup: lookup, cache: cache}
+}
+
+// Resolve returns the IDs of usernames keyed by Normalize(username). Cache
+// misses are looked up in batches of BatchSize; accounts the API does not
+// return (unknown or suspended) are absent from the result. Newly resolved
+// handles are added to the cache; call Cache.Save to persist them.
+func (r *Resolver) Resolve(ctx context.Context, usernames []string) (map[string]string, client.RateLimitSnapshot, error) {
+	ids := make(map[string]string, len(usernames))
+	var misses []string
+	for _, username := range usernames {
+		key := Normalize(username)
+		if key == "" {
+			continue
+		}
+		if _, seen := ids[key]; seen {
+			continue
+		}
+		if id, ok := r.cache.Get(key); ok {
+			ids[key] = id
+			continue
+		}
+		ids[key] = ""
+		misses = append(misses, key)
+	}
+
+	var rateLimits client.RateLimitSnapshot
+	for start := 0; start < len(misses); start += BatchSize {
+		end := min(start+BatchSize, len(misses))
+		response, limits, err := r.lookup.LookupUsernames(ctx, misses[start:end], nil)
+		rateLimits = limits
+		if err != nil {
+			return nil, rateLimits, fmt.Errorf("handles: lookup usernames: %w", err)
+		}
+		for _, user := range response.Data {
+			key := Normalize(user.Username)
+			ids[key] = user.ID
+			r.cache.Put(key, user.ID)
+		}
+	}
+
+	for key, id := range ids {
+		if id == "" {
+			delete(ids, key)
+		}
+	}
+	return ids, rateLimits, nil
+}
@@ -0,0 +1,100 @@
+// Package lists provides helpers for reading and managing Twitter Lists.
+package lists
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+)
+
+// List is a v2 list object. Fields beyond ID and Name are only populated
+// when requested through list.fields.
+type List struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	OwnerID       string    `json:"owner_id,omitempty"`
+	Private       bool      `json:"private,omitempty"`
+	FollowerCount int       `json:"follower_count,omitempty"`
+	MemberCount   int       `json:"member_count,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitzero"`
+}
+
+// ListsResponse captures list lookups and the owned, followed and pinned
+// list collections.
+type ListsResponse struct {
+	Data     []List          `json:"data"`
+	Includes *model.Includes `json:"includes,omitempty"`
+	Errors   []model.Error   `json:"errors,omitempty"`
+	Meta     model.Meta      `json:"meta,omitzero"`
+}
+
+// UnmarshalJSON accepts data as a single list, as returned by GET
+// /2/lists/:id, or as an array.
+func (r *ListsResponse) UnmarshalJSON(b []byte) error {
+	type response ListsResponse
+	var raw struct {
+		response
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*r = ListsResponse(raw.response)
+	r.Data = nil
+	return model.DecodeOneOrMany(raw.Data, &r.Data)
+}
+
+// OwnerOf resolves list.OwnerID against the included users. It needs the
+// owner_id expansion.
+func (r ListsResponse) OwnerOf(list List) (model.User, bool) {
+	return r.Includes.User(list.OwnerID)
+}
+
+// CreateRequest is the payload for POST /2/lists.
+type CreateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Private     bool   `json:"private,omitempty"`
+}
+
+// UpdateRequest is the payload for PUT /2/lists/:id. Nil fields are left
+// unchanged.
+type UpdateRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Private     *bool   `json:"private,omitempty"`
+}
+
+// CreateResponse captures the list created by POST /2/lists.
+type CreateResponse struct {
+	Data List `json:"data"`
+}
+
+// UpdateResponse reports whether a list update was applied.
+type UpdateResponse struct {
+	Data struct {
+		Updated bool `json:"updated"`
+	} `json:"data"`
+}
+
+// DeleteResponse reports whether a list was deleted.
+type DeleteResponse struct {
+	Data struct {
+		Deleted bool `json:"deleted"`
+	} `json:"data"`
+}
+
+// MemberRequest is the payload for POST /2/lists/:id/members.
+type MemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// MemberResponse reports a user's membership after an add or remove.
+type MemberResponse struct {
+	Data struct {
+		IsMember bool `json:"is_member"`
+	} `json:"data"`
+}
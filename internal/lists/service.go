@@ -0,0 +1,229 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+)
+
+const (
+	listsPath               = "/2/lists"
+	listPathFormat          = "/2/lists/%s"
+	ownedListsPathFormat    = "/2/users/%s/owned_lists"
+	followedListsPathFormat = "/2/users/%s/followed_lists"
+	pinnedListsPathFormat   = "/2/users/%s/pinned_lists"
+	membersPathFormat       = "/2/lists/%s/members"
+	memberPathFormat        = "/2/lists/%s/members/%s"
+	listTweetsPathFormat    = "/2/lists/%s/tweets"
+)
+
+// Service coordinates Twitter List operations.
+type Service struct {
+	client *client.Client
+}
+
+// NewService constructs a Service backed by the supplied client.
+func NewService(c *client.Client) *Service {
+	if c == nil {
+		panic("lists: nil client")
+	}
+	return &Service{client: c}
+}
+
+// Get fetches a single list by ID.
+func (s *Service) Get(ctx context.Context, listID string, params map[string]string) (ListsResponse, client.RateLimitSnapshot, error) {
+	if listID == "" {
+		return ListsResponse{}, client.RateLimitSnapshot{}, errors.New("lists: list id is required")
+	}
+	var result ListsResponse
+	rateLimits, err := s.get(ctx, fmt.Sprintf(listPathFormat, listID), params, &result)
+	return result, rateLimits, err
+}
+
+// Owned lists the lists owned by userID.
+func (s *Service) Owned(ctx context.Context, userID string, params map[string]string) (ListsResponse, client.RateLimitSnapshot, error) {
+	return s.userLists(ctx, ownedListsPathFormat, userID, params)
+}
+
+// Followed lists the lists userID follows.
+func (s *Service) Followed(ctx context.Context, userID string, params map[string]string) (ListsResponse, client.RateLimitSnapshot, error) {
+	return s.userLists(ctx, followedListsPathFormat, userID, params)
+}
+
+// Pinned lists the lists userID has pinned.
+func (s *Service) Pinned(ctx context.Context, userID string, params map[string]string) (ListsResponse, client.RateLimitSnapshot, error) {
+	return s.userLists(ctx, pinnedListsPathFormat, userID, params)
+}
+
+// Create makes a new list owned by the authenticated user.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (CreateResponse, client.RateLimitSnapshot, error) {
+	if req.Name == "" {
+		return CreateResponse{}, client.RateLimitSnapshot{}, errors.New("lists: name is required")
+	}
+	var result CreateResponse
+	rateLimits, err := s.send(ctx, http.MethodPost, listsPath, req, &result)
+	return result, rateLimits, err
+}
+
+// Update changes a list's name, description or visibility.
+func (s *Service) Update(ctx context.Context, listID string, req UpdateRequest) (UpdateResponse, client.RateLimitSnapshot, error) {
+	if listID == "" {
+		return UpdateResponse{}, client.RateLimitSnapshot{}, errors.New("lists: list id is required")
+	}
+	if req.Name == nil && req.Description == nil && req.Private == nil {
+		return UpdateResponse{}, client.RateLimitSnapshot{}, errors.New("lists: nothing to update")
+	}
+	var result UpdateResponse
+	rateLimits, err := s.send(ctx, http.MethodPut, fmt.Sprintf(listPathFormat, listID), req, &result)
+	return result, rateLimits, err
+}
+
+// Delete removes a list owned by the authenticated user.
+func (s *Service) Delete(ctx context.Context, listID string) (DeleteResponse, client.RateLimitSnapshot, error) {
+	if listID == "" {
+		return DeleteResponse{}, client.RateLimitSnapshot{}, errors.New("lists: list id is required")
+	}
+	var result DeleteResponse
+	rateLimits, err := s.send(ctx, http.MethodDelete, fmt.Sprintf(listPathFormat, listID), nil, &result)
+	return result, rateLimits, err
+}
+
+// AddMember adds userID to the list.
+func (s *Service) AddMember(ctx context.Context, listID, userID string) (MemberResponse, client.RateLimitSnapshot, error) {
+	if listID == "" || userID == "" {
+		return MemberResponse{}, client.RateLimitSnapshot{}, errors.New("lists: list id and user id are required")
+	}
+	var result MemberResponse
+	rateLimits, err := s.send(ctx, http.MethodPost, fmt.Sprintf(membersPathFormat, listID), MemberRequest{UserID: userID}, &result)
+	return result, rateLimits, err
+}
+
+// RemoveMember removes userID from the list.
+func (s *Service) RemoveMember(ctx context.Context, listID, userID string) (MemberResponse, client.RateLimitSnapshot, error) {
+	if listID == "" || userID == "" {
+		return MemberResponse{}, client.RateLimitSnapshot{}, errors.New("lists: list id and user id are required")
+	}
+	var result MemberResponse
+	rateLimits, err := s.send(ctx, http.MethodDelete, fmt.Sprintf(memberPathFormat, listID, userID), nil, &result)
+	return result, rateLimits, err
+}
+
+// Members fetches one page of the list's members.
+func (s *Service) Members(ctx context.Context, listID string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	if listID == "" {
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, errors.New("lists: list id is required")
+	}
+	var result model.UsersResponse
+	rateLimits, err := s.get(ctx, fmt.Sprintf(membersPathFormat, listID), params, &result)
+	return result, rateLimits, err
+}
+
+// AllMembers follows pagination until every member is fetched or maxPages
+// pages were read (0 means no limit).
+func (s *Service) AllMembers(ctx context.Context, listID string, params map[string]string, maxPages int) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	var (
+		all        model.UsersResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(params, client.PaginationTokenParam, maxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := s.Members(ctx, listID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Append(resp)
+		return resp.Meta.NextToken, nil
+	})
+	return all, rateLimits, err
+}
+
+// Tweets fetches one page of the list's tweet timeline.
+func (s *Service) Tweets(ctx context.Context, listID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	if listID == "" {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, errors.New("lists: list id is required")
+	}
+	var result model.TweetsResponse
+	rateLimits, err := s.get(ctx, fmt.Sprintf(listTweetsPathFormat, listID), params, &result)
+	return result, rateLimits, err
+}
+
+// AllTweets follows pagination until the timeline is exhausted or maxPages
+// pages were read (0 means no limit).
+func (s *Service) AllTweets(ctx context.Context, listID string, params map[string]string, maxPages int) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	var (
+		all        model.TweetsResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(params, client.PaginationTokenParam, maxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := s.Tweets(ctx, listID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Append(resp)
+		return resp.Meta.NextToken, nil
+	})
+	return all, rateLimits, err
+}
+
+func (s *Service) userLists(ctx context.Context, format, userID string, params map[string]string) (ListsResponse, client.RateLimitSnapshot, error) {
+	if userID == "" {
+		return ListsResponse{}, client.RateLimitSnapshot{}, errors.New("lists: user id is required")
+	}
+	var result ListsResponse
+	rateLimits, err := s.get(ctx, fmt.Sprintf(format, userID), params, &result)
+	return result, rateLimits, err
+}
+
+func (s *Service) get(ctx context.Context, path string, params map[string]string, out any) (client.RateLimitSnapshot, error) {
+	if s == nil {
+		return client.RateLimitSnapshot{}, errors.New("lists: nil service")
+	}
+	resp, err := s.client.Get(ctx, path, params)
+	if err != nil {
+		return client.RateLimitSnapshot{}, err
+	}
+	return decode(resp, out)
+}
+
+func (s *Service) send(ctx context.Context, method, path string, body, out any) (client.RateLimitSnapshot, error) {
+	if s == nil {
+		return client.RateLimitSnapshot{}, errors.New("lists: nil service")
+	}
+	var (
+		resp *http.Response
+		err  error
+	)
+	switch method {
+	case http.MethodPost:
+		resp, err = s.client.Post(ctx, path, body, nil)
+	case http.MethodPut:
+		resp, err = s.client.Put(ctx, path, body, nil)
+	case http.MethodDelete:
+		resp, err = s.client.Delete(ctx, path, nil)
+	default:
+		return client.RateLimitSnapshot{}, fmt.Errorf("lists: unsupported method %s", method)
+	}
+	if err != nil {
+		return client.RateLimitSnapshot{}, err
+	}
+	return decode(resp, out)
+}
+
+func decode(resp *http.Response, out any) (client.RateLimitSnapshot, error) {
+	defer client.SafeClose(resp.Body)
+
+	rateLimits := client.ParseRateLimits(resp)
+	if err := client.CheckResponse(resp); err != nil {
+		return rateLimits, err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return rateLimits, fmt.Errorf("lists: decode response: %w", err)
+	}
+	return rateLimits, nil
+}
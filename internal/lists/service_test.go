@@ -0,0 +1,171 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDecodesSingleList(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/2/lists/84839422", r.URL.Path)
+		require.Equal(t, "owner_id", r.URL.Query().Get("expansions"))
+
+		w.Header().Set("x-rate-limit-limit", "75")
+		w.Header().Set("x-rate-limit-remaining", "74")
+		_, _ = w.Write([]byte(`{"data":{"id":"84839422","name":"Official Accounts","owner_id":"783214"},"includes":{"users":[{"id":"783214","name":"Twitter","username":"Twitter"}]}}`))
+	}
+
+	service := newTestService(t, handler)
+
+	resp, rateLimits, err := service.Get(context.Background(), "84839422", map[string]string{"expansions": "owner_id"})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, "Official Accounts", resp.Data[0].Name)
+	owner, ok := resp.OwnerOf(resp.Data[0])
+	require.True(t, ok)
+	require.Equal(t, "Twitter", owner.Username)
+	require.Equal(t, 75, rateLimits.Limit)
+}
+
+func TestOwnedFollowedPinned(t *testing.T) {
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":[{"id":"1","name":"one"}],"meta":{"result_count":1}}`))
+	}
+
+	service := newTestService(t, handler)
+	ctx := context.Background()
+
+	_, _, err := service.Owned(ctx, "42", nil)
+	require.NoError(t, err)
+	_, _, err = service.Followed(ctx, "42", nil)
+	require.NoError(t, err)
+	resp, _, err := service.Pinned(ctx, "42", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.Meta.ResultCount)
+
+	require.Equal(t, []string{"/2/users/42/owned_lists", "/2/users/42/followed_lists", "/2/users/42/pinned_lists"}, paths)
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			require.Equal(t, "/2/lists", r.URL.Path)
+			var body CreateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, CreateRequest{Name: "golang", Private: true}, body)
+			_, _ = w.Write([]byte(`{"data":{"id":"99","name":"golang"}}`))
+		case http.MethodPut:
+			require.Equal(t, "/2/lists/99", r.URL.Path)
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]any{"description": "Gophers"}, body)
+			_, _ = w.Write([]byte(`{"data":{"updated":true}}`))
+		case http.MethodDelete:
+			require.Equal(t, "/2/lists/99", r.URL.Path)
+			_, _ = w.Write([]byte(`{"data":{"deleted":true}}`))
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	}
+
+	service := newTestService(t, handler)
+	ctx := context.Background()
+
+	created, _, err := service.Create(ctx, CreateRequest{Name: "golang", Private: true})
+	require.NoError(t, err)
+	require.Equal(t, "99", created.Data.ID)
+
+	description := "Gophers"
+	updated, _, err := service.Update(ctx, "99", UpdateRequest{Description: &description})
+	require.NoError(t, err)
+	require.True(t, updated.Data.Updated)
+
+	deleted, _, err := service.Delete(ctx, "99")
+	require.NoError(t, err)
+	require.True(t, deleted.Data.Deleted)
+}
+
+func TestUpdateRequiresChange(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request")
+	})
+
+	_, _, err := service.Update(context.Background(), "99", UpdateRequest{})
+	require.EqualError(t, err, "lists: nothing to update")
+}
+
+func TestAddAndRemoveMember(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			require.Equal(t, "/2/lists/99/members", r.URL.Path)
+			var body MemberRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "7", body.UserID)
+			_, _ = w.Write([]byte(`{"data":{"is_member":true}}`))
+		case http.MethodDelete:
+			require.Equal(t, "/2/lists/99/members/7", r.URL.Path)
+			_, _ = w.Write([]byte(`{"data":{"is_member":false}}`))
+		}
+	}
+
+	service := newTestService(t, handler)
+	ctx := context.Background()
+
+	added, _, err := service.AddMember(ctx, "99", "7")
+	require.NoError(t, err)
+	require.True(t, added.Data.IsMember)
+
+	removed, _, err := service.RemoveMember(ctx, "99", "7")
+	require.NoError(t, err)
+	require.False(t, removed.Data.IsMember)
+}
+
+func TestAllTweetsFollowsPagination(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/2/lists/99/tweets", r.URL.Path)
+		require.Equal(t, "100", r.URL.Query().Get("max_results"))
+
+		switch r.URL.Query().Get("pagination_token") {
+		case "":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","text":"a"}],"meta":{"result_count":1,"next_token":"p2"}}`))
+		case "p2":
+			_, _ = w.Write([]byte(`{"data":[{"id":"2","text":"b"}],"meta":{"result_count":1}}`))
+		default:
+			t.Fatalf("unexpected token %q", r.URL.Query().Get("pagination_token"))
+		}
+	}
+
+	service := newTestService(t, handler)
+
+	resp, _, err := service.AllTweets(context.Background(), "99", map[string]string{"max_results": "100"}, 0)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	require.Equal(t, 2, resp.Meta.ResultCount)
+	require.Empty(t, resp.Meta.NextToken)
+}
+
+func TestAllMembersStopsAtMaxPages(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		require.Equal(t, "/2/lists/99/members", r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":[{"id":"1","name":"A","username":"a"}],"meta":{"result_count":1,"next_token":"more"}}`))
+	}
+
+	service := newTestService(t, handler)
+
+	resp, _, err := service.AllMembers(context.Background(), "99", nil, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Len(t, resp.Data, 2)
+	require.Equal(t, "more", resp.Meta.NextToken)
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -307,6 +308,19 @@ func (s *Service) newRequest(ctx context.Context, method, path string, params ur
 	return req, nil
 }
 
+// DMCategory picks the direct message category for a file from its
+// extension: dm_gif for GIFs, dm_video for videos and dm_image otherwise.
+func DMCategory(filePath string) MediaCategory {
+	switch mediaType := detectMediaType(filePath); {
+	case mediaType == "image/gif":
+		return CategoryDMGif
+	case strings.HasPrefix(mediaType, "video/"):
+		return CategoryDMVideo
+	default:
+		return CategoryDMImage
+	}
+}
+
 func detectMediaType(filePath string) string {
 	ext := filepath.Ext(filePath)
 	switch ext {
@@ -87,3 +87,10 @@ func TestDetectMediaType(t *testing.T) {
 		})
 	}
 }
+
+func TestDMCategory(t *testing.T) {
+	require.Equal(t, CategoryDMImage, DMCategory("photo.png"))
+	require.Equal(t, CategoryDMGif, DMCategory("loop.gif"))
+	require.Equal(t, CategoryDMVideo, DMCategory("clip.mov"))
+	require.Equal(t, CategoryDMImage, DMCategory("scan.unknown"))
+}
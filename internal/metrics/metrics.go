@@ -0,0 +1,240 @@
+// Package metrics implements a small registry of counters, gauges and
+// histograms rendered in the Prometheus text exposition format. It covers what
+// ctw's long-running commands need without pulling in a client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// DefaultBuckets are histogram upper bounds in seconds suitable for
+// per-event handler latency.
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// Registry holds metric families and renders them on demand.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+	names    map[string]bool
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+type family struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+func (r *Registry) register(name, help, kind string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: duplicate metric " + name)
+	}
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.names[name] = true
+	r.families = append(r.families, f)
+	return f
+}
+
+// seriesFor returns the series for the label values, creating it on first
+// use. Callers must hold r.mu.
+func (f *family) seriesFor(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.kind == kindHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Counter is a monotonically increasing value partitioned by labels.
+type Counter struct {
+	r *Registry
+	f *family
+}
+
+// NewCounter registers a counter with the given label names.
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{r: r, f: r.register(name, help, kindCounter, nil, labelNames)}
+}
+
+// Inc adds one to the series identified by labelValues.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the series identified by labelValues. Negative deltas are ignored.
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.r.mu.Lock()
+	defer c.r.mu.Unlock()
+	c.f.seriesFor(labelValues).value += delta
+}
+
+// Gauge is a value that can go up and down, partitioned by labels.
+type Gauge struct {
+	r *Registry
+	f *family
+}
+
+// NewGauge registers a gauge with the given label names.
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{r: r, f: r.register(name, help, kindGauge, nil, labelNames)}
+}
+
+// Set stores value for the series identified by labelValues.
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.r.mu.Lock()
+	defer g.r.mu.Unlock()
+	g.f.seriesFor(labelValues).value = value
+}
+
+// Histogram tracks the distribution of observations in cumulative buckets.
+type Histogram struct {
+	r *Registry
+	f *family
+}
+
+// NewHistogram registers a histogram. Buckets must be sorted ascending; nil
+// selects DefaultBuckets.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return &Histogram{r: r, f: r.register(name, help, kindHistogram, buckets, labelNames)}
+}
+
+// Observe records value in the series identified by labelValues.
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.r.mu.Lock()
+	defer h.r.mu.Unlock()
+	s := h.f.seriesFor(labelValues)
+	for i, upper := range h.f.buckets {
+		if value <= upper {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// WriteText renders every registered family in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range r.families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != kindHistogram {
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, "", ""), formatValue(s.value))
+				continue
+			}
+			for i, upper := range f.buckets {
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues, "le", formatValue(upper)), s.counts[i])
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues, "le", "+Inf"), s.count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, "", ""), formatValue(s.sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues, "", ""), s.count)
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(value)
+}
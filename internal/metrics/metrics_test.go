@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTextCountersAndGauges(t *testing.T) {
+	registry := NewRegistry()
+	tweets := registry.NewCounter("ctw_tweets_total", "Tweets received.", "rule_tag")
+	connected := registry.NewGauge("ctw_connected", "Whether the stream is connected.")
+
+	tweets.Inc("golang")
+	tweets.Inc("golang")
+	tweets.Add(3, `say "hi"`)
+	connected.Set(1)
+
+	var out bytes.Buffer
+	require.NoError(t, registry.WriteText(&out))
+	require.Equal(t, `# HELP ctw_tweets_total Tweets received.
+# TYPE ctw_tweets_total counter
+ctw_tweets_total{rule_tag="golang"} 2
+ctw_tweets_total{rule_tag="say \"hi\""} 3
+# HELP ctw_connected Whether the stream is connected.
+# TYPE ctw_connected gauge
+ctw_connected 1
+`, out.String())
+}
+
+func TestWriteTextHistogram(t *testing.T) {
+	registry := NewRegistry()
+	latency := registry.NewHistogram("ctw_latency_seconds", "Handler latency.", []float64{0.1, 1})
+
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(2)
+
+	var out bytes.Buffer
+	require.NoError(t, registry.WriteText(&out))
+	require.Contains(t, out.String(), `ctw_latency_seconds_bucket{le="0.1"} 1`)
+	require.Contains(t, out.String(), `ctw_latency_seconds_bucket{le="1"} 2`)
+	require.Contains(t, out.String(), `ctw_latency_seconds_bucket{le="+Inf"} 3`)
+	require.Contains(t, out.String(), `ctw_latency_seconds_sum 2.55`)
+	require.Contains(t, out.String(), `ctw_latency_seconds_count 3`)
+}
+
+func TestHandlerServesTextFormat(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("ctw_reconnects_total", "Reconnects.").Inc()
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	require.Contains(t, recorder.Body.String(), "ctw_reconnects_total 1")
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("ctw_dup", "first")
+	require.Panics(t, func() { registry.NewGauge("ctw_dup", "second") })
+}
@@ -0,0 +1,302 @@
+// Package model defines the Twitter v2 tweet, user, media, poll and place
+// objects shared by every service, so a tweet decoded from search, lookup,
+// timelines, likes, bookmarks or the filtered stream has the same shape.
+package model
+
+import "time"
+
+// Tweet is a v2 tweet object. Fields beyond ID and Text are only populated
+// when requested through tweet.fields.
+type Tweet struct {
+	ID                  string              `json:"id"`
+	Text                string              `json:"text"`
+	AuthorID            string              `json:"author_id,omitempty"`
+	CreatedAt           time.Time           `json:"created_at,omitzero"`
+	ConversationID      string              `json:"conversation_id,omitempty"`
+	InReplyToUserID     string              `json:"in_reply_to_user_id,omitempty"`
+	Lang                string              `json:"lang,omitempty"`
+	Source              string              `json:"source,omitempty"`
+	PossiblySensitive   bool                `json:"possibly_sensitive,omitempty"`
+	ReplySettings       string              `json:"reply_settings,omitempty"`
+	CardURI             string              `json:"card_uri,omitempty"`
+	DisplayTextRange    []int               `json:"display_text_range,omitempty"`
+	Entities            *Entities           `json:"entities,omitempty"`
+	PublicMetrics       *PublicMetrics      `json:"public_metrics,omitempty"`
+	NonPublicMetrics    map[string]int      `json:"non_public_metrics,omitempty"`
+	OrganicMetrics      map[string]int      `json:"organic_metrics,omitempty"`
+	PromotedMetrics     map[string]int      `json:"promoted_metrics,omitempty"`
+	ReferencedTweets    []ReferencedTweet   `json:"referenced_tweets,omitempty"`
+	Attachments         *Attachments        `json:"attachments,omitempty"`
+	ContextAnnotations  []ContextAnnotation `json:"context_annotations,omitempty"`
+	EditHistoryTweetIDs []string            `json:"edit_history_tweet_ids,omitempty"`
+	EditControls        *EditControls       `json:"edit_controls,omitempty"`
+	Geo                 *TweetGeo           `json:"geo,omitempty"`
+	NoteTweet           *NoteTweet          `json:"note_tweet,omitempty"`
+	Withheld            *Withheld           `json:"withheld,omitempty"`
+}
+
+// FullText returns the long-form note text when present, otherwise Text.
+func (t Tweet) FullText() string {
+	if t.NoteTweet != nil && t.NoteTweet.Text != "" {
+		return t.NoteTweet.Text
+	}
+	return t.Text
+}
+
+// Reference returns the ID of the tweet t references with kind ("retweeted",
+// "quoted" or "replied_to").
+func (t Tweet) Reference(kind string) (string, bool) {
+	for _, ref := range t.ReferencedTweets {
+		if ref.Type == kind {
+			return ref.ID, true
+		}
+	}
+	return "", false
+}
+
+// NewerID reports whether snowflake ID a is newer than b. IDs are compared as
+// decimal strings so they never overflow.
+func NewerID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
+
+// PublicMetrics holds the engagement counts requested with
+// tweet.fields=public_metrics.
+type PublicMetrics struct {
+	RetweetCount    int `json:"retweet_count"`
+	ReplyCount      int `json:"reply_count"`
+	LikeCount       int `json:"like_count"`
+	QuoteCount      int `json:"quote_count"`
+	BookmarkCount   int `json:"bookmark_count"`
+	ImpressionCount int `json:"impression_count"`
+}
+
+// ReferencedTweet links a tweet to the tweet it quotes, retweets or replies to.
+// Type is one of "retweeted", "quoted" or "replied_to".
+type ReferencedTweet struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Attachments lists the media and polls attached to a tweet. Expand them with
+// attachments.media_keys and attachments.poll_ids.
+type Attachments struct {
+	MediaKeys []string `json:"media_keys,omitempty"`
+	PollIDs   []string `json:"poll_ids,omitempty"`
+}
+
+// ContextAnnotation is a domain/entity pair Twitter inferred for a tweet.
+type ContextAnnotation struct {
+	Domain ContextEntity `json:"domain"`
+	Entity ContextEntity `json:"entity"`
+}
+
+// ContextEntity names a context annotation domain or entity.
+type ContextEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// EditControls reports whether and until when a tweet can still be edited.
+type EditControls struct {
+	EditsRemaining int       `json:"edits_remaining"`
+	IsEditEligible bool      `json:"is_edit_eligible"`
+	EditableUntil  time.Time `json:"editable_until,omitzero"`
+}
+
+// TweetGeo carries the place and optional exact coordinates of a tweet.
+type TweetGeo struct {
+	PlaceID     string `json:"place_id,omitempty"`
+	Coordinates *Point `json:"coordinates,omitempty"`
+}
+
+// Point is a GeoJSON point; Coordinates are [longitude, latitude].
+type Point struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// NoteTweet holds the full text of tweets longer than 280 characters.
+type NoteTweet struct {
+	Text     string    `json:"text"`
+	Entities *Entities `json:"entities,omitempty"`
+}
+
+// Withheld describes content withheld in some countries.
+type Withheld struct {
+	Copyright    bool     `json:"copyright,omitempty"`
+	CountryCodes []string `json:"country_codes,omitempty"`
+	Scope        string   `json:"scope,omitempty"`
+}
+
+// Entities are the parsed mentions, URLs, hashtags and annotations in text.
+type Entities struct {
+	Mentions    []Mention    `json:"mentions,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	URLs        []URL        `json:"urls,omitempty"`
+	Hashtags    []Tag        `json:"hashtags,omitempty"`
+	Cashtags    []Tag        `json:"cashtags,omitempty"`
+}
+
+// Mention is an @username reference within text.
+type Mention struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Username string `json:"username"`
+	ID       string `json:"id,omitempty"`
+}
+
+// Annotation is a named entity recognised within text.
+type Annotation struct {
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Probability    float64 `json:"probability"`
+	Type           string  `json:"type"`
+	NormalizedText string  `json:"normalized_text"`
+}
+
+// URL is a link within text with its expanded and unwound forms.
+type URL struct {
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+	URL         string  `json:"url"`
+	ExpandedURL string  `json:"expanded_url,omitempty"`
+	DisplayURL  string  `json:"display_url,omitempty"`
+	UnwoundURL  string  `json:"unwound_url,omitempty"`
+	MediaKey    string  `json:"media_key,omitempty"`
+	Images      []Image `json:"images,omitempty"`
+	Status      int     `json:"status,omitempty"`
+	Title       string  `json:"title,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+// Image is a preview image for a URL entity.
+type Image struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Tag is a hashtag or cashtag within text, without its leading symbol.
+type Tag struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Tag   string `json:"tag"`
+}
+
+// User is a v2 user object. Fields beyond ID, Name and Username are only
+// populated when requested through user.fields.
+type User struct {
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	Username          string             `json:"username"`
+	CreatedAt         time.Time          `json:"created_at,omitzero"`
+	Description       string             `json:"description,omitempty"`
+	Location          string             `json:"location,omitempty"`
+	URL               string             `json:"url,omitempty"`
+	ProfileImageURL   string             `json:"profile_image_url,omitempty"`
+	ProfileBannerURL  string             `json:"profile_banner_url,omitempty"`
+	PinnedTweetID     string             `json:"pinned_tweet_id,omitempty"`
+	MostRecentTweetID string             `json:"most_recent_tweet_id,omitempty"`
+	Protected         bool               `json:"protected,omitempty"`
+	Verified          bool               `json:"verified,omitempty"`
+	VerifiedType      string             `json:"verified_type,omitempty"`
+	SubscriptionType  string             `json:"subscription_type,omitempty"`
+	ReceivesYourDM    bool               `json:"receives_your_dm,omitempty"`
+	ConnectionStatus  []string           `json:"connection_status,omitempty"`
+	Entities          *UserEntities      `json:"entities,omitempty"`
+	PublicMetrics     *UserPublicMetrics `json:"public_metrics,omitempty"`
+	Withheld          *Withheld          `json:"withheld,omitempty"`
+}
+
+// UserEntities holds entities parsed from a user's URL and description.
+type UserEntities struct {
+	URL         *Entities `json:"url,omitempty"`
+	Description *Entities `json:"description,omitempty"`
+}
+
+// UserPublicMetrics holds the counts requested with user.fields=public_metrics.
+type UserPublicMetrics struct {
+	FollowersCount int `json:"followers_count"`
+	FollowingCount int `json:"following_count"`
+	TweetCount     int `json:"tweet_count"`
+	ListedCount    int `json:"listed_count"`
+	LikeCount      int `json:"like_count,omitempty"`
+}
+
+// Media is an attachment expanded through attachments.media_keys.
+type Media struct {
+	MediaKey         string         `json:"media_key"`
+	Type             string         `json:"type"`
+	URL              string         `json:"url,omitempty"`
+	PreviewImageURL  string         `json:"preview_image_url,omitempty"`
+	AltText          string         `json:"alt_text,omitempty"`
+	Width            int            `json:"width,omitempty"`
+	Height           int            `json:"height,omitempty"`
+	DurationMS       int            `json:"duration_ms,omitempty"`
+	Variants         []MediaVariant `json:"variants,omitempty"`
+	PublicMetrics    map[string]int `json:"public_metrics,omitempty"`
+	NonPublicMetrics map[string]int `json:"non_public_metrics,omitempty"`
+	OrganicMetrics   map[string]int `json:"organic_metrics,omitempty"`
+}
+
+// DownloadURL returns the URL of the media's full content: the image itself
+// for photos, or the highest-bitrate MP4 variant for videos and GIFs.
+func (m Media) DownloadURL() string {
+	if m.URL != "" {
+		return m.URL
+	}
+	best, bitRate := "", -1
+	for _, variant := range m.Variants {
+		if variant.ContentType == "video/mp4" && variant.BitRate > bitRate {
+			best, bitRate = variant.URL, variant.BitRate
+		}
+	}
+	return best
+}
+
+// MediaVariant is one encoding of a video or animated GIF.
+type MediaVariant struct {
+	BitRate     int    `json:"bit_rate,omitempty"`
+	ContentType string `json:"content_type"`
+	URL         string `json:"url"`
+}
+
+// Poll is a poll expanded through attachments.poll_ids.
+type Poll struct {
+	ID              string       `json:"id"`
+	Options         []PollOption `json:"options"`
+	DurationMinutes int          `json:"duration_minutes,omitempty"`
+	EndDatetime     time.Time    `json:"end_datetime,omitzero"`
+	VotingStatus    string       `json:"voting_status,omitempty"`
+}
+
+// PollOption is a single poll choice and its vote count.
+type PollOption struct {
+	Position int    `json:"position"`
+	Label    string `json:"label"`
+	Votes    int    `json:"votes"`
+}
+
+// Place is a location expanded through geo.place_id.
+type Place struct {
+	ID              string    `json:"id"`
+	FullName        string    `json:"full_name"`
+	Name            string    `json:"name,omitempty"`
+	Country         string    `json:"country,omitempty"`
+	CountryCode     string    `json:"country_code,omitempty"`
+	PlaceType       string    `json:"place_type,omitempty"`
+	ContainedWithin []string  `json:"contained_within,omitempty"`
+	Geo             *PlaceGeo `json:"geo,omitempty"`
+}
+
+// PlaceGeo is the GeoJSON feature describing a place's bounding box.
+type PlaceGeo struct {
+	Type       string         `json:"type"`
+	BBox       []float64      `json:"bbox,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
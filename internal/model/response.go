@@ -0,0 +1,290 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/0dayfall/ctw/internal/client"
+)
+
+// Includes carries the objects referenced by expansions.
+type Includes struct {
+	Users  []User  `json:"users,omitempty"`
+	Tweets []Tweet `json:"tweets,omitempty"`
+	Media  []Media `json:"media,omitempty"`
+	Polls  []Poll  `json:"polls,omitempty"`
+	Places []Place `json:"places,omitempty"`
+}
+
+// User returns the included user with id. A nil Includes has no objects.
+func (i *Includes) User(id string) (User, bool) {
+	if i == nil || id == "" {
+		return User{}, false
+	}
+	for _, user := range i.Users {
+		if user.ID == id {
+			return user, true
+		}
+	}
+	return User{}, false
+}
+
+// Tweet returns the included tweet with id.
+func (i *Includes) Tweet(id string) (Tweet, bool) {
+	if i == nil || id == "" {
+		return Tweet{}, false
+	}
+	for _, tweet := range i.Tweets {
+		if tweet.ID == id {
+			return tweet, true
+		}
+	}
+	return Tweet{}, false
+}
+
+// AuthorOf resolves tweet.AuthorID against the included users. It needs the
+// author_id expansion.
+func (i *Includes) AuthorOf(tweet Tweet) (User, bool) {
+	return i.User(tweet.AuthorID)
+}
+
+// MediaOf returns the included media attached to tweet, in attachment order.
+// It needs the attachments.media_keys expansion.
+func (i *Includes) MediaOf(tweet Tweet) []Media {
+	if i == nil || tweet.Attachments == nil {
+		return nil
+	}
+	var media []Media
+	for _, key := range tweet.Attachments.MediaKeys {
+		for _, m := range i.Media {
+			if m.MediaKey == key {
+				media = append(media, m)
+				break
+			}
+		}
+	}
+	return media
+}
+
+// PollOf returns the included poll attached to tweet. It needs the
+// attachments.poll_ids expansion.
+func (i *Includes) PollOf(tweet Tweet) (Poll, bool) {
+	if i == nil || tweet.Attachments == nil {
+		return Poll{}, false
+	}
+	for _, id := range tweet.Attachments.PollIDs {
+		for _, poll := range i.Polls {
+			if poll.ID == id {
+				return poll, true
+			}
+		}
+	}
+	return Poll{}, false
+}
+
+// PlaceOf returns the included place tagged on tweet. It needs the
+// geo.place_id expansion.
+func (i *Includes) PlaceOf(tweet Tweet) (Place, bool) {
+	if i == nil || tweet.Geo == nil || tweet.Geo.PlaceID == "" {
+		return Place{}, false
+	}
+	for _, place := range i.Places {
+		if place.ID == tweet.Geo.PlaceID {
+			return place, true
+		}
+	}
+	return Place{}, false
+}
+
+// ReferencedTweetOf returns the included tweet that tweet references with
+// kind. It needs the referenced_tweets.id expansion.
+func (i *Includes) ReferencedTweetOf(tweet Tweet, kind string) (Tweet, bool) {
+	id, ok := tweet.Reference(kind)
+	if !ok {
+		return Tweet{}, false
+	}
+	return i.Tweet(id)
+}
+
+// PinnedTweetOf resolves user.PinnedTweetID against the included tweets. It
+// needs the pinned_tweet_id expansion.
+func (i *Includes) PinnedTweetOf(user User) (Tweet, bool) {
+	return i.Tweet(user.PinnedTweetID)
+}
+
+// Merge returns the union of i and other, keeping the first copy of each
+// object. Either side may be nil.
+func (i *Includes) Merge(other *Includes) *Includes {
+	if other == nil {
+		return i
+	}
+	if i == nil {
+		copied := *other
+		return &copied
+	}
+	return &Includes{
+		Users:  mergeByKey(i.Users, other.Users, func(u User) string { return u.ID }),
+		Tweets: mergeByKey(i.Tweets, other.Tweets, func(t Tweet) string { return t.ID }),
+		Media:  mergeByKey(i.Media, other.Media, func(m Media) string { return m.MediaKey }),
+		Polls:  mergeByKey(i.Polls, other.Polls, func(p Poll) string { return p.ID }),
+		Places: mergeByKey(i.Places, other.Places, func(p Place) string { return p.ID }),
+	}
+}
+
+// Meta is the pagination metadata shared by list endpoints.
+type Meta struct {
+	ResultCount   int    `json:"result_count"`
+	NextToken     string `json:"next_token,omitempty"`
+	PreviousToken string `json:"previous_token,omitempty"`
+	NewestID      string `json:"newest_id,omitempty"`
+	OldestID      string `json:"oldest_id,omitempty"`
+}
+
+// Error is a partial error reported alongside data, such as a deleted tweet
+// in a batch lookup.
+type Error = client.Error
+
+// TweetsResponse is returned by every endpoint whose data is tweets.
+type TweetsResponse struct {
+	Data     []Tweet   `json:"data"`
+	Includes *Includes `json:"includes,omitempty"`
+	Errors   []Error   `json:"errors,omitempty"`
+	Meta     Meta      `json:"meta,omitzero"`
+}
+
+// UnmarshalJSON accepts data as a single tweet, as returned by single-ID
+// endpoints, or as an array.
+func (r *TweetsResponse) UnmarshalJSON(b []byte) error {
+	type response TweetsResponse
+	var raw struct {
+		response
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*r = TweetsResponse(raw.response)
+	r.Data = nil
+	return DecodeOneOrMany(raw.Data, &r.Data)
+}
+
+// Append adds a further page to r. Data and errors are concatenated,
+// includes merged, and Meta carries the combined count and the cursors of
+// the newest page.
+func (r *TweetsResponse) Append(page TweetsResponse) {
+	r.Data = append(r.Data, page.Data...)
+	r.Errors = append(r.Errors, page.Errors...)
+	r.Includes = r.Includes.Merge(page.Includes)
+	r.Meta = appendMeta(r.Meta, page.Meta)
+}
+
+// AuthorOf resolves the author of tweet from the response's includes.
+func (r TweetsResponse) AuthorOf(tweet Tweet) (User, bool) {
+	return r.Includes.AuthorOf(tweet)
+}
+
+// MediaOf resolves the media attached to tweet from the response's includes.
+func (r TweetsResponse) MediaOf(tweet Tweet) []Media {
+	return r.Includes.MediaOf(tweet)
+}
+
+// UsersResponse is returned by every endpoint whose data is users.
+type UsersResponse struct {
+	Data     []User    `json:"data"`
+	Includes *Includes `json:"includes,omitempty"`
+	Errors   []Error   `json:"errors,omitempty"`
+	Meta     Meta      `json:"meta,omitzero"`
+}
+
+// UnmarshalJSON accepts data as a single user or as an array.
+func (r *UsersResponse) UnmarshalJSON(b []byte) error {
+	type response UsersResponse
+	var raw struct {
+		response
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*r = UsersResponse(raw.response)
+	r.Data = nil
+	return DecodeOneOrMany(raw.Data, &r.Data)
+}
+
+// Append adds a further page to r; see TweetsResponse.Append.
+func (r *UsersResponse) Append(page UsersResponse) {
+	r.Data = append(r.Data, page.Data...)
+	r.Errors = append(r.Errors, page.Errors...)
+	r.Includes = r.Includes.Merge(page.Includes)
+	r.Meta = appendMeta(r.Meta, page.Meta)
+}
+
+// PinnedTweetOf resolves the pinned tweet of user from the response's includes.
+func (r UsersResponse) PinnedTweetOf(user User) (Tweet, bool) {
+	return r.Includes.PinnedTweetOf(user)
+}
+
+// UserResponse is returned by single-user endpoints.
+type UserResponse struct {
+	Data     User      `json:"data"`
+	Includes *Includes `json:"includes,omitempty"`
+	Errors   []Error   `json:"errors,omitempty"`
+}
+
+// PinnedTweetOf resolves the pinned tweet of user from the response's includes.
+func (r UserResponse) PinnedTweetOf(user User) (Tweet, bool) {
+	return r.Includes.PinnedTweetOf(user)
+}
+
+// DecodeOneOrMany decodes a response's data into out whether it holds a
+// single object, as single-ID endpoints return it, or an array. Missing and
+// null data leave out unchanged.
+func DecodeOneOrMany[T any](data json.RawMessage, out *[]T) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0 || bytes.Equal(data, []byte("null")):
+		return nil
+	case data[0] == '{':
+		var one T
+		if err := json.Unmarshal(data, &one); err != nil {
+			return err
+		}
+		*out = []T{one}
+		return nil
+	default:
+		return json.Unmarshal(data, out)
+	}
+}
+
+func appendMeta(current, page Meta) Meta {
+	merged := page
+	merged.ResultCount = current.ResultCount + page.ResultCount
+	if current.NewestID != "" {
+		merged.NewestID = current.NewestID
+	}
+	if current.PreviousToken != "" {
+		merged.PreviousToken = current.PreviousToken
+	}
+	return merged
+}
+
+func mergeByKey[T any](current, extra []T, key func(T) string) []T {
+	if len(extra) == 0 {
+		return current
+	}
+	seen := make(map[string]bool, len(current)+len(extra))
+	merged := make([]T, 0, len(current)+len(extra))
+	for _, items := range [][]T{current, extra} {
+		for _, item := range items {
+			k := key(item)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTweetsResponseDecodesObjectOrArray(t *testing.T) {
+	var single TweetsResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"data":{"id":"1","text":"one"}}`), &single))
+	require.Len(t, single.Data, 1)
+	require.Equal(t, "one", single.Data[0].Text)
+
+	var many TweetsResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"data":[{"id":"1"},{"id":"2"}],"meta":{"result_count":2,"next_token":"t"}}`), &many))
+	require.Len(t, many.Data, 2)
+	require.Equal(t, "t", many.Meta.NextToken)
+
+	var empty TweetsResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"meta":{"result_count":0}}`), &empty))
+	require.Empty(t, empty.Data)
+}
+
+func TestTweetsResponseResolvesExpansions(t *testing.T) {
+	payload := `{
+		"data": [{
+			"id": "1",
+			"text": "look",
+			"author_id": "9",
+			"attachments": {"media_keys": ["3_2", "3_1"], "poll_ids": ["p1"]},
+			"geo": {"place_id": "pl"},
+			"referenced_tweets": [{"type": "quoted", "id": "0"}],
+			"public_metrics": {"like_count": 4}
+		}],
+		"includes": {
+			"users": [{"id": "9", "name": "Gopher", "username": "gopher"}],
+			"media": [{"media_key": "3_1", "type": "photo"}, {"media_key": "3_2", "type": "video"}],
+			"polls": [{"id": "p1", "options": [{"position": 1, "label": "yes", "votes": 2}]}],
+			"places": [{"id": "pl", "full_name": "Gothenburg, Sweden"}],
+			"tweets": [{"id": "0", "text": "original"}]
+		}
+	}`
+
+	var resp TweetsResponse
+	require.NoError(t, json.Unmarshal([]byte(payload), &resp))
+	tweet := resp.Data[0]
+
+	author, ok := resp.AuthorOf(tweet)
+	require.True(t, ok)
+	require.Equal(t, "gopher", author.Username)
+
+	media := resp.MediaOf(tweet)
+	require.Len(t, media, 2)
+	require.Equal(t, "video", media[0].Type)
+
+	poll, ok := resp.Includes.PollOf(tweet)
+	require.True(t, ok)
+	require.Equal(t, "yes", poll.Options[0].Label)
+
+	place, ok := resp.Includes.PlaceOf(tweet)
+	require.True(t, ok)
+	require.Equal(t, "Gothenburg, Sweden", place.FullName)
+
+	quoted, ok := resp.Includes.ReferencedTweetOf(tweet, "quoted")
+	require.True(t, ok)
+	require.Equal(t, "original", quoted.Text)
+
+	require.Equal(t, 4, tweet.PublicMetrics.LikeCount)
+}
+
+func TestNilIncludesResolveNothing(t *testing.T) {
+	var resp TweetsResponse
+	_, ok := resp.AuthorOf(Tweet{AuthorID: "9"})
+	require.False(t, ok)
+	require.Nil(t, resp.MediaOf(Tweet{Attachments: &Attachments{MediaKeys: []string{"1"}}}))
+}
+
+func TestTweetOmitsUnrequestedFields(t *testing.T) {
+	out, err := json.Marshal(Tweet{ID: "1", Text: "hi"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"1","text":"hi"}`, string(out))
+}
+
+func TestTweetsResponseAppendMergesPages(t *testing.T) {
+	resp := TweetsResponse{
+		Data:     []Tweet{{ID: "3"}, {ID: "2"}},
+		Includes: &Includes{Users: []User{{ID: "9"}}},
+		Meta:     Meta{ResultCount: 2, NewestID: "3", NextToken: "a"},
+	}
+	resp.Append(TweetsResponse{
+		Data:     []Tweet{{ID: "1"}},
+		Includes: &Includes{Users: []User{{ID: "9"}, {ID: "8"}}},
+		Meta:     Meta{ResultCount: 1, NewestID: "1", OldestID: "1"},
+	})
+
+	require.Len(t, resp.Data, 3)
+	require.Len(t, resp.Includes.Users, 2)
+	require.Equal(t, Meta{ResultCount: 3, NewestID: "3", OldestID: "1"}, resp.Meta)
+}
@@ -0,0 +1,98 @@
+// Package ratelimit paces requests against the API's rate-limit windows.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+)
+
+// Pacer spaces the starts of concurrent requests so they do not run into the
+// rate limit. While the window has fewer calls left than requests pending,
+// starts are spread evenly until the reset; once it is exhausted everything
+// waits for the reset. A Pacer is safe for concurrent use.
+type Pacer struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	next     time.Time
+	interval time.Duration
+	latest   client.RateLimitSnapshot
+	seen     bool
+}
+
+// NewPacer returns a Pacer reading the time from now, or time.Now when nil.
+func NewPacer(now func() time.Time) *Pacer {
+	if now == nil {
+		now = time.Now
+	}
+	return &Pacer{now: now}
+}
+
+// Wait blocks until the caller may start a request.
+func (p *Pacer) Wait(ctx context.Context) error {
+	p.mu.Lock()
+	now := p.now()
+	start := now
+	if p.next.After(start) {
+		start = p.next
+	}
+	p.next = start.Add(p.interval)
+	p.mu.Unlock()
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		return context.Cause(ctx)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Observe adjusts the pace from a response's snapshot with pending requests
+// still to start. Responses of concurrent requests can arrive out of order,
+// so a snapshot of an older window, or with more calls left in the same
+// window, is ignored.
+func (p *Pacer) Observe(limits client.RateLimitSnapshot, pending int) {
+	if limits.Remaining < 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seen && (limits.Reset < p.latest.Reset || limits.Reset == p.latest.Reset && limits.Remaining > p.latest.Remaining) {
+		return
+	}
+	p.latest, p.seen = limits, true
+	if limits.Reset <= 0 {
+		return
+	}
+
+	reset := time.Unix(int64(limits.Reset), 0)
+	until := reset.Sub(p.now())
+	switch {
+	case until <= 0:
+		p.interval = 0
+	case limits.Remaining == 0:
+		p.interval = 0
+		if reset.After(p.next) {
+			p.next = reset
+		}
+	case pending > limits.Remaining:
+		p.interval = until / time.Duration(limits.Remaining)
+	default:
+		p.interval = 0
+	}
+}
+
+// Snapshot returns the newest snapshot observed.
+func (p *Pacer) Snapshot() client.RateLimitSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.latest
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacerWaitsForReset(t *testing.T) {
+	reset := time.Unix(1_700_000_000, 0)
+	now := reset.Add(-20 * time.Millisecond)
+	pacer := NewPacer(func() time.Time { return now })
+
+	pacer.Observe(client.RateLimitSnapshot{Limit: -1, Remaining: -1, Reset: -1}, 5)
+	require.NoError(t, pacer.Wait(context.Background()))
+
+	pacer.Observe(client.RateLimitSnapshot{Limit: 300, Remaining: 0, Reset: int(reset.Unix())}, 5)
+	start := time.Now()
+	require.NoError(t, pacer.Wait(context.Background()))
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	require.Equal(t, 0, pacer.Snapshot().Remaining)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, pacer.Wait(ctx), context.Canceled)
+}
+
+func TestPacerSpreadsAndIgnoresStaleSnapshots(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	pacer := NewPacer(func() time.Time { return now })
+
+	pacer.Observe(client.RateLimitSnapshot{Limit: 15, Remaining: 2, Reset: 1_000_010}, 10)
+	require.Equal(t, 5*time.Second, pacer.interval)
+
+	// An older response of the same window does not loosen the pace.
+	pacer.Observe(client.RateLimitSnapshot{Limit: 15, Remaining: 9, Reset: 1_000_010}, 10)
+	require.Equal(t, 5*time.Second, pacer.interval)
+	require.Equal(t, 2, pacer.Snapshot().Remaining)
+
+	// A new window does.
+	pacer.Observe(client.RateLimitSnapshot{Limit: 15, Remaining: 15, Reset: 1_000_900}, 10)
+	require.Zero(t, pacer.interval)
+}
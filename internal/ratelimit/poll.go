@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+)
+
+// NextPoll returns how long a poller waits before its next call: the
+// remaining calls of the rate-limit window are spread until its reset, and
+// polls are never closer together than interval.
+func NextPoll(limits client.RateLimitSnapshot, interval time.Duration, now time.Time) time.Duration {
+	if limits.Remaining < 0 || limits.Reset <= 0 {
+		return interval
+	}
+	until := time.Unix(int64(limits.Reset), 0).Sub(now)
+	if until <= 0 {
+		return interval
+	}
+	if limits.Remaining == 0 {
+		return max(interval, until)
+	}
+	return max(interval, until/time.Duration(limits.Remaining))
+}
+
+// PollFunc makes one poll. An error stops Poll; errors worth retrying should
+// be handled, or logged, by the function itself.
+type PollFunc func(ctx context.Context) (client.RateLimitSnapshot, error)
+
+// Poll calls poll until ctx is cancelled, waiting NextPoll between calls.
+// It returns nil once ctx is done and the error of a poll that failed.
+func Poll(ctx context.Context, interval time.Duration, now func() time.Time, poll PollFunc) error {
+	if now == nil {
+		now = time.Now
+	}
+	for {
+		limits, err := poll(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		timer := time.NewTimer(NextPoll(limits, interval, now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
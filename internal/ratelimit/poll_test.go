@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextPollFollowsRateLimit(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+
+	require.Equal(t, time.Minute, NextPoll(client.RateLimitSnapshot{Limit: -1, Remaining: -1, Reset: -1}, time.Minute, now))
+	require.Equal(t, 10*time.Minute, NextPoll(client.RateLimitSnapshot{Limit: 15, Remaining: 0, Reset: 1_000_600}, time.Minute, now))
+	require.Equal(t, 5*time.Minute, NextPoll(client.RateLimitSnapshot{Limit: 15, Remaining: 2, Reset: 1_000_600}, time.Minute, now))
+	require.Equal(t, time.Minute, NextPoll(client.RateLimitSnapshot{Limit: 15, Remaining: 14, Reset: 1_000_600}, time.Minute, now))
+	require.Equal(t, time.Minute, NextPoll(client.RateLimitSnapshot{Limit: 15, Remaining: 0, Reset: 999_000}, time.Minute, now))
+}
+
+func TestPollStopsOnErrorOrCancel(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := Poll(context.Background(), time.Millisecond, nil, func(ctx context.Context) (client.RateLimitSnapshot, error) {
+		calls++
+		if calls == 3 {
+			return client.RateLimitSnapshot{}, stop
+		}
+		return client.RateLimitSnapshot{Limit: -1, Remaining: -1, Reset: -1}, nil
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 3, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = Poll(ctx, time.Hour, nil, func(ctx context.Context) (client.RateLimitSnapshot, error) {
+		cancel()
+		return client.RateLimitSnapshot{}, ctx.Err()
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,154 @@
+package searchquery
+
+import (
+	"strings"
+)
+
+// Normalize parses query and returns it in the canonical form of Format.
+func Normalize(query string) (string, error) {
+	tree, err := Parse(query)
+	if err != nil {
+		return "", err
+	}
+	return Format(tree), nil
+}
+
+// Format renders n on one line: single spaces, OR in capitals, operator names
+// in lower case, nested groups of the same kind flattened and parentheses only
+// where they are needed or clarify an OR. Format's output parses back to the
+// same tree.
+func Format(n Node) string {
+	var b strings.Builder
+	writeNode(&b, n, false)
+	return b.String()
+}
+
+// Indent renders n across lines, one term per line and every OR group in its
+// own indented block. The result is still a valid query, since line breaks are
+// whitespace.
+func Indent(n Node) string {
+	var b strings.Builder
+	indentNode(&b, n, 0, "", false)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeNode writes n; nested asks for parentheses around a group of terms
+// that would otherwise read ambiguously next to its siblings.
+func writeNode(b *strings.Builder, n Node, nested bool) {
+	switch n := n.(type) {
+	case *Term:
+		b.WriteString(formatTerm(n))
+	case *Group:
+		parens := n.Negated || nested
+		if n.Negated {
+			b.WriteByte('-')
+		}
+		if parens {
+			b.WriteByte('(')
+		}
+		separator := " "
+		if n.Or {
+			separator = " OR "
+		}
+		for i, child := range flatten(n) {
+			if i > 0 {
+				b.WriteString(separator)
+			}
+			// An OR inside a group of terms needs parentheses; a group of
+			// terms inside an OR gets them for clarity.
+			group, ok := child.(*Group)
+			writeNode(b, child, ok && group.Or != n.Or)
+		}
+		if parens {
+			b.WriteByte(')')
+		}
+	}
+}
+
+// flatten returns g's children with nested groups of the same kind inlined.
+func flatten(g *Group) []Node {
+	var children []Node
+	for _, child := range g.Children {
+		if group, ok := child.(*Group); ok && group.Or == g.Or && !group.Negated {
+			children = append(children, flatten(group)...)
+			continue
+		}
+		children = append(children, child)
+	}
+	return children
+}
+
+func indentNode(b *strings.Builder, n Node, depth int, lead string, inOr bool) {
+	prefix := strings.Repeat("  ", depth) + lead
+	switch n := n.(type) {
+	case *Term:
+		b.WriteString(prefix + formatTerm(n) + "\n")
+	case *Group:
+		open := n.Or || n.Negated || inOr
+		inner := depth
+		if open {
+			neg := ""
+			if n.Negated {
+				neg = "-"
+			}
+			b.WriteString(prefix + neg + "(\n")
+			inner++
+		}
+		for i, child := range flatten(n) {
+			childLead := ""
+			if n.Or && i > 0 {
+				childLead = "OR "
+			}
+			indentNode(b, child, inner, childLead, n.Or)
+		}
+		if open {
+			b.WriteString(strings.Repeat("  ", depth) + ")\n")
+		}
+	}
+}
+
+func formatTerm(t *Term) string {
+	var b strings.Builder
+	if t.Negated {
+		b.WriteByte('-')
+	}
+	switch t.Kind {
+	case Phrase:
+		b.WriteString(quote(t.Value))
+	case Hashtag:
+		b.WriteString("#" + t.Value)
+	case Mention:
+		b.WriteString("@" + t.Value)
+	case Cashtag:
+		b.WriteString("$" + t.Value)
+	case Operator:
+		b.WriteString(t.Name + ":")
+		value := t.Value
+		switch t.Name {
+		case "is", "has", "lang":
+			value = strings.ToLower(value)
+		case "from", "to", "retweets_of":
+			value = strings.TrimPrefix(value, "@")
+		}
+		if needsQuotes(t, value) {
+			value = quote(value)
+		}
+		b.WriteString(value)
+	default:
+		b.WriteString(t.Value)
+	}
+	return b.String()
+}
+
+// needsQuotes reports whether an operator value must be quoted to lex back as
+// one word.
+func needsQuotes(t *Term, value string) bool {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		return false
+	}
+	return t.Quoted || value == "" || strings.ContainsAny(value, " \t\n()\"")
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
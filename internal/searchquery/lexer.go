@@ -0,0 +1,138 @@
+package searchquery
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokLParen
+	tokRParen
+	tokOr
+	// tokMinus is a '-' directly in front of a term or group.
+	tokMinus
+	// tokWord is a keyword, #hashtag, @mention, $cashtag or name:value.
+	tokWord
+	// tokPhrase is a double-quoted exact phrase; text is unquoted.
+	tokPhrase
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// quoted marks a word whose operator value was double-quoted.
+	quoted bool
+	// pos is the 1-based column of the token's first character.
+	pos int
+}
+
+// lex splits input into tokens. Parentheses and whitespace separate words;
+// an operator's value may be a quoted string or a [bracketed] list, which may
+// contain spaces.
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, pos: i + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, pos: i + 1})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, token{kind: tokMinus, pos: i + 1})
+			i++
+		case r == '"':
+			text, end, ok := readQuoted(runes, i)
+			if !ok {
+				return nil, &SyntaxError{Pos: i + 1, Msg: "unterminated quote"}
+			}
+			tokens = append(tokens, token{kind: tokPhrase, text: text, pos: i + 1})
+			i = end
+		default:
+			tok, end, err := readWord(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// readWord reads the word starting at runes[start].
+func readWord(runes []rune, start int) (token, int, error) {
+	tok := token{kind: tokWord, pos: start + 1}
+	var b strings.Builder
+	i := start
+	for i < len(runes) {
+		r := runes[i]
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			break
+		}
+		afterColon := i > start && runes[i-1] == ':'
+		switch {
+		case r == '"' && afterColon:
+			text, end, ok := readQuoted(runes, i)
+			if !ok {
+				return token{}, 0, &SyntaxError{Pos: i + 1, Msg: "unterminated quote"}
+			}
+			b.WriteString(text)
+			tok.quoted = true
+			i = end
+			continue
+		case r == '"':
+			// A quote inside a word starts the next phrase.
+			tok.text = b.String()
+			return tok, i, nil
+		case r == '[' && afterColon:
+			end := i
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return token{}, 0, &SyntaxError{Pos: i + 1, Msg: "missing ] after ["}
+			}
+			b.WriteString(string(runes[i : end+1]))
+			i = end + 1
+			continue
+		}
+		b.WriteRune(r)
+		i++
+	}
+	tok.text = b.String()
+	if tok.text == "OR" && !tok.quoted {
+		tok.kind = tokOr
+	}
+	return tok, i, nil
+}
+
+// readQuoted reads the double-quoted string at runes[start] and returns its
+// unescaped content and the index after the closing quote.
+func readQuoted(runes []rune, start int) (string, int, bool) {
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) && runes[i+1] == '"' {
+				b.WriteRune('"')
+				i++
+				continue
+			}
+			b.WriteRune('\\')
+		case '"':
+			return b.String(), i + 1, true
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, false
+}
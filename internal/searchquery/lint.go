@@ -0,0 +1,305 @@
+package searchquery
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks an Issue.
+type Severity string
+
+// Severities. Errors make the API reject the query; warnings flag queries
+// that are accepted but probably do not mean what was intended.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is one problem found in a query.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Pos is the 1-based column the issue refers to.
+	Pos     int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("column %d: %s: %s", i.Pos, i.Severity, i.Message)
+}
+
+// Options selects what a query is checked against.
+type Options struct {
+	// Endpoint defaults to EndpointSearch.
+	Endpoint Endpoint
+	// Tier is the caller's access level. When it is empty, operators that
+	// need a higher tier are not reported and only the highest length limit
+	// is enforced.
+	Tier Tier
+}
+
+// Result is the outcome of Lint.
+type Result struct {
+	Query string `json:"query"`
+	// Normalized is the query in canonical form; empty when it does not
+	// parse.
+	Normalized string  `json:"normalized,omitempty"`
+	Length     int     `json:"length"`
+	MaxLength  int     `json:"max_length"`
+	Issues     []Issue `json:"issues"`
+
+	tree Node
+}
+
+// Tree returns the parsed query, or nil when it does not parse.
+func (r Result) Tree() Node { return r.tree }
+
+// Err joins the error issues, or returns nil when there are none.
+func (r Result) Err() error {
+	var messages []string
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			messages = append(messages, fmt.Sprintf("column %d: %s", issue.Pos, issue.Message))
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.New("searchquery: invalid query: " + strings.Join(messages, "; "))
+}
+
+// Warnings returns the warning issues.
+func (r Result) Warnings() []Issue {
+	var warnings []Issue
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityWarning {
+			warnings = append(warnings, issue)
+		}
+	}
+	return warnings
+}
+
+// Lint parses query and checks it against the endpoint's operators, the
+// tier's access and the length limit.
+func Lint(query string, opts Options) Result {
+	if opts.Endpoint == "" {
+		opts.Endpoint = EndpointSearch
+	}
+	l := &linter{opts: opts}
+	result := Result{
+		Query:     query,
+		Length:    len([]rune(query)),
+		MaxLength: MaxLength(opts.Endpoint, opts.Tier),
+	}
+
+	if result.Length > result.MaxLength {
+		l.errorf(1, "query is %d characters; the %s limit is %d", result.Length, l.limitName(), result.MaxLength)
+	} else if opts.Tier == "" && result.Length > maxLength[opts.Endpoint][TierBasic] {
+		l.warnf(1, "query is %d characters; only pro and enterprise access accept more than %d", result.Length, maxLength[opts.Endpoint][TierBasic])
+	}
+
+	tree, err := Parse(query)
+	if err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			l.errorf(syntaxErr.Pos, "%s", syntaxErr.Msg)
+		} else {
+			l.errorf(1, "%v", err)
+		}
+		result.Issues = l.issues
+		return result
+	}
+
+	l.node(tree)
+	if !standalone(tree) {
+		l.errorf(1, "query needs at least one keyword, phrase, hashtag, mention or standalone operator that is not negated")
+	}
+	result.tree = tree
+	result.Normalized = Format(tree)
+	result.Issues = l.issues
+	return result
+}
+
+type linter struct {
+	opts   Options
+	issues []Issue
+}
+
+func (l *linter) errorf(pos int, format string, args ...any) {
+	l.issues = append(l.issues, Issue{Severity: SeverityError, Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) warnf(pos int, format string, args ...any) {
+	l.issues = append(l.issues, Issue{Severity: SeverityWarning, Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) limitName() string {
+	if l.opts.Tier == "" {
+		return string(l.opts.Endpoint)
+	}
+	return fmt.Sprintf("%s %s", l.opts.Tier, l.opts.Endpoint)
+}
+
+func (l *linter) node(n Node) {
+	switch n := n.(type) {
+	case *Term:
+		l.term(n)
+	case *Group:
+		for _, child := range n.Children {
+			if n.Or {
+				if group, ok := child.(*Group); ok && !group.Or && !group.Parens && !group.Negated {
+					l.warnf(group.Pos, "terms next to each other bind tighter than OR; add parentheses to make the grouping explicit")
+				}
+			}
+			l.node(child)
+		}
+	}
+}
+
+var (
+	langCode   = regexp.MustCompile(`^[a-z]{2,3}$`)
+	userHandle = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+	numericID  = regexp.MustCompile(`^[0-9]+$`)
+	countRange = regexp.MustCompile(`^[0-9]+(\.\.[0-9]+)?$`)
+)
+
+func (l *linter) term(t *Term) {
+	switch t.Kind {
+	case Keyword:
+		switch {
+		case strings.EqualFold(t.Value, "or"):
+			l.warnf(t.Pos, "%q is searched as a word; write OR in capitals to combine terms", t.Value)
+		case t.Value == "AND":
+			l.warnf(t.Pos, "AND is searched as a word; terms next to each other already must all match")
+		case t.Value == "NOT":
+			l.warnf(t.Pos, "NOT is searched as a word; put - in front of a term to exclude it")
+		}
+	case Phrase:
+		if strings.TrimSpace(t.Value) == "" {
+			l.errorf(t.Pos, "empty phrase")
+		}
+	case Mention:
+		if !userHandle.MatchString(t.Value) {
+			l.errorf(t.Pos, "invalid username @%s", t.Value)
+		}
+	case Cashtag:
+		l.access(t, "$ cashtags", cashtagSpec)
+	case Operator:
+		l.operator(t)
+	}
+}
+
+func (l *linter) operator(t *Term) {
+	if t.Value == "" {
+		l.errorf(t.Pos, "%s: needs a value", t.Name)
+		return
+	}
+	key, spec, ok := lookupOperator(t)
+	if !ok {
+		message := fmt.Sprintf("unknown operator %s:", t.Name)
+		if t.Name == "is" || t.Name == "has" {
+			message = fmt.Sprintf("unknown operator %s", key)
+		}
+		if suggestion := suggest(key); suggestion != "" {
+			if !strings.Contains(suggestion, ":") {
+				suggestion += ":"
+			}
+			message += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+		l.errorf(t.Pos, "%s", message)
+		return
+	}
+	if t.Name != "is" && t.Name != "has" {
+		key += ":"
+	}
+	l.access(t, key, spec)
+	if spec.negatedOnly && !t.Negated {
+		l.errorf(t.Pos, "%s can only be used negated, as -%s", key, key)
+	}
+
+	switch t.Name {
+	case "from", "to", "retweets_of":
+		value := t.Value
+		if trimmed, ok := strings.CutPrefix(value, "@"); ok {
+			l.warnf(t.Pos, "%s: takes the username without @", t.Name)
+			value = trimmed
+		}
+		if !userHandle.MatchString(value) && !numericID.MatchString(value) {
+			l.errorf(t.Pos, "%s: expects a username or user ID, got %q", t.Name, t.Value)
+		}
+	case "conversation_id", "in_reply_to_tweet_id", "retweets_of_tweet_id", "quotes_of_tweet_id", "list":
+		if !numericID.MatchString(t.Value) {
+			l.errorf(t.Pos, "%s: expects a numeric ID, got %q", t.Name, t.Value)
+		}
+	case "lang":
+		if value := strings.ToLower(t.Value); !langCode.MatchString(value) && value != "und" {
+			l.warnf(t.Pos, "lang: expects a BCP 47 language code such as en, got %q", t.Value)
+		}
+	case "point_radius", "bounding_box":
+		if !strings.HasPrefix(t.Value, "[") {
+			l.errorf(t.Pos, "%s: expects a [bracketed] list of coordinates", t.Name)
+		}
+	case "sample":
+		if n, err := strconv.Atoi(t.Value); err != nil || n < 1 || n > 100 {
+			l.errorf(t.Pos, "sample: expects a percentage from 1 to 100, got %q", t.Value)
+		}
+	case "followers_count", "tweets_count", "following_count", "listed_count":
+		if !countRange.MatchString(t.Value) {
+			l.errorf(t.Pos, "%s: expects a number or a range such as 100..1000, got %q", t.Name, t.Value)
+		}
+	}
+}
+
+// access reports an operator the endpoint or tier does not offer.
+func (l *linter) access(t *Term, name string, spec operatorSpec) {
+	switch l.opts.Endpoint {
+	case EndpointSearch:
+		if !spec.search {
+			l.errorf(t.Pos, "%s is only available in stream rules", name)
+			return
+		}
+	case EndpointStream:
+		if !spec.stream {
+			l.errorf(t.Pos, "%s is only available in search queries", name)
+			return
+		}
+	}
+	if l.opts.Tier != "" && tierRank[l.opts.Tier] < tierRank[spec.tier] {
+		l.errorf(t.Pos, "%s needs %s access or higher", name, spec.tier)
+	}
+}
+
+// standalone reports whether n matches tweets by itself: it has a term that
+// is not negated and is not a conjunction-only operator, in every
+// alternative of an OR.
+func standalone(n Node) bool {
+	switch n := n.(type) {
+	case *Term:
+		if n.Negated {
+			return false
+		}
+		switch n.Kind {
+		case Operator:
+			_, spec, ok := lookupOperator(n)
+			// Unknown operators are reported on their own.
+			return !ok || spec.standalone
+		default:
+			return true
+		}
+	case *Group:
+		if n.Negated {
+			return false
+		}
+		for _, child := range n.Children {
+			if s := standalone(child); n.Or && !s {
+				return false
+			} else if !n.Or && s {
+				return true
+			}
+		}
+		return n.Or
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package searchquery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Endpoint is where a query is sent; the two accept different operators.
+type Endpoint string
+
+// Endpoints.
+const (
+	EndpointSearch Endpoint = "search"
+	EndpointStream Endpoint = "stream"
+)
+
+// ParseEndpoint reads an endpoint name.
+func ParseEndpoint(s string) (Endpoint, error) {
+	switch e := Endpoint(strings.ToLower(strings.TrimSpace(s))); e {
+	case EndpointSearch, EndpointStream:
+		return e, nil
+	default:
+		return "", fmt.Errorf("searchquery: unknown endpoint %q (expected search or stream)", s)
+	}
+}
+
+// Tier is an API access level. The empty Tier stands for an unknown one.
+type Tier string
+
+// Tiers, in increasing order of access.
+const (
+	TierBasic      Tier = "basic"
+	TierPro        Tier = "pro"
+	TierEnterprise Tier = "enterprise"
+)
+
+var tierRank = map[Tier]int{"": 0, TierBasic: 1, TierPro: 2, TierEnterprise: 3}
+
+// ParseTier reads a tier name; the empty string is the unknown tier.
+func ParseTier(s string) (Tier, error) {
+	t := Tier(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := tierRank[t]; !ok {
+		return "", fmt.Errorf("searchquery: unknown tier %q (expected basic, pro or enterprise)", s)
+	}
+	return t, nil
+}
+
+// maxLength is the query length limit per endpoint and tier.
+var maxLength = map[Endpoint]map[Tier]int{
+	EndpointSearch: {TierBasic: 512, TierPro: 1024, TierEnterprise: 4096},
+	EndpointStream: {TierBasic: 512, TierPro: 1024, TierEnterprise: 2048},
+}
+
+// MaxLength returns the longest query endpoint accepts at tier. For the
+// unknown tier it is the highest limit.
+func MaxLength(endpoint Endpoint, tier Tier) int {
+	limits := maxLength[endpoint]
+	if tier == "" {
+		return limits[TierEnterprise]
+	}
+	return limits[tier]
+}
+
+// operatorSpec describes one operator. is: and has: are listed per value.
+type operatorSpec struct {
+	// standalone operators can form a query on their own; the others only
+	// narrow down a query that already has a standalone term.
+	standalone bool
+	search     bool
+	stream     bool
+	// tier is the lowest tier with access; empty for every tier.
+	tier Tier
+	// negatedOnly operators must be written with a leading -.
+	negatedOnly bool
+}
+
+var (
+	everywhere     = operatorSpec{standalone: true, search: true, stream: true}
+	conjunction    = operatorSpec{search: true, stream: true}
+	streamOnly     = operatorSpec{standalone: true, stream: true, tier: TierPro}
+	enterpriseOnly = operatorSpec{stream: true, tier: TierEnterprise}
+)
+
+func withTier(spec operatorSpec, tier Tier) operatorSpec {
+	spec.tier = tier
+	return spec
+}
+
+// operators is the catalog of name:value operators.
+var operators = map[string]operatorSpec{
+	"from":                 everywhere,
+	"to":                   everywhere,
+	"url":                  everywhere,
+	"retweets_of":          everywhere,
+	"context":              everywhere,
+	"entity":               everywhere,
+	"conversation_id":      everywhere,
+	"in_reply_to_tweet_id": everywhere,
+	"retweets_of_tweet_id": everywhere,
+	"quotes_of_tweet_id":   everywhere,
+	"list":                 withTier(everywhere, TierPro),
+	"place":                withTier(everywhere, TierPro),
+	"place_country":        withTier(everywhere, TierPro),
+	"point_radius":         withTier(everywhere, TierPro),
+	"bounding_box":         withTier(everywhere, TierPro),
+	"lang":                 conjunction,
+	"bio":                  streamOnly,
+	"bio_name":             streamOnly,
+	"bio_location":         streamOnly,
+	"sample":               {stream: true, tier: TierPro},
+	"followers_count":      enterpriseOnly,
+	"tweets_count":         enterpriseOnly,
+	"following_count":      enterpriseOnly,
+	"listed_count":         enterpriseOnly,
+	"url_title":            enterpriseOnly,
+	"url_description":      enterpriseOnly,
+	"url_contains":         enterpriseOnly,
+
+	"is:retweet":     conjunction,
+	"is:reply":       conjunction,
+	"is:quote":       conjunction,
+	"is:verified":    conjunction,
+	"is:nullcast":    {search: true, stream: true, tier: TierPro, negatedOnly: true},
+	"has:hashtags":   conjunction,
+	"has:links":      conjunction,
+	"has:mentions":   conjunction,
+	"has:media":      conjunction,
+	"has:images":     conjunction,
+	"has:video_link": conjunction,
+	"has:cashtags":   withTier(conjunction, TierPro),
+	"has:geo":        withTier(conjunction, TierPro),
+}
+
+// cashtagSpec applies to $TICKER terms.
+var cashtagSpec = withTier(everywhere, TierPro)
+
+// lookupOperator returns the catalog key and spec of an operator term.
+func lookupOperator(term *Term) (string, operatorSpec, bool) {
+	key := term.Name
+	if key == "is" || key == "has" {
+		key += ":" + strings.ToLower(term.Value)
+	}
+	spec, ok := operators[key]
+	return key, spec, ok
+}
+
+// suggest returns the catalog entry closest to key, if it is a likely typo.
+func suggest(key string) string {
+	prefix := ""
+	if name, _, ok := strings.Cut(key, ":"); ok {
+		prefix = name + ":"
+	}
+	names := make([]string, 0, len(operators))
+	for name := range operators {
+		if strings.HasPrefix(name, prefix) && (prefix != "" || !strings.Contains(name, ":")) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := editDistance(key, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
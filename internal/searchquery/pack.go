@@ -0,0 +1,297 @@
+package searchquery
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultTagPrefix starts the tag of every packed rule.
+const DefaultTagPrefix = "pack"
+
+// maxRules is the number of filtered stream rules per tier.
+var maxRules = map[Tier]int{TierBasic: 25, TierPro: 1000, TierEnterprise: 25000}
+
+// MaxRules returns how many stream rules tier allows. The filtered stream
+// needs at least pro access, so the unknown tier gets the pro cap.
+func MaxRules(tier Tier) int {
+	if tier == "" {
+		return maxRules[TierPro]
+	}
+	return maxRules[tier]
+}
+
+// PackOptions tunes Pack.
+type PackOptions struct {
+	// Filters is appended to every rule, e.g. "lang:en -is:retweet"; a
+	// top-level OR is put in parentheses so it still applies to every keyword.
+	Filters string
+	// Tier selects the length and rule limits; the unknown tier packs for pro
+	// access, the lowest tier with the filtered stream.
+	Tier Tier
+	// MaxLength and MaxRules override the tier's limits when positive.
+	// MaxRules should leave room for rules not managed by the packer.
+	MaxLength int
+	MaxRules  int
+	// TagPrefix starts every rule's tag; it defaults to DefaultTagPrefix.
+	TagPrefix string
+}
+
+// PackedRule is a stream rule that matches any of its keywords.
+type PackedRule struct {
+	Value    string   `json:"value"`
+	Tag      string   `json:"tag"`
+	Keywords []string `json:"keywords"`
+}
+
+// Match returns the keywords of r that text contains, the way the stream
+// matches them: case-insensitively and on word boundaries. Operators cannot be
+// checked against text alone and are assumed to match.
+func (r PackedRule) Match(text string) []string {
+	text = strings.ToLower(text)
+	var matched []string
+	for _, keyword := range r.Keywords {
+		if tree, err := Parse(keyword); err == nil && matchText(tree, text) {
+			matched = append(matched, keyword)
+		}
+	}
+	return matched
+}
+
+// Pack combines keywords into as few stream rules as fit the length and rule
+// limits: (k1 OR k2 OR ...) followed by the filters. Each keyword may be a
+// word, a #hashtag or @mention, a query fragment, or several words, which
+// become an exact phrase. Rules are tagged <prefix>-1, <prefix>-2, ... and
+// list their keywords so matches can be mapped back to them. Pack checks only
+// the limits; Lint each rule's Value before sending it.
+func Pack(keywords []string, opts PackOptions) ([]PackedRule, error) {
+	if opts.Tier == "" {
+		opts.Tier = TierPro
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = MaxLength(EndpointStream, opts.Tier)
+	}
+	if opts.MaxRules <= 0 {
+		opts.MaxRules = MaxRules(opts.Tier)
+	}
+	if opts.TagPrefix == "" {
+		opts.TagPrefix = DefaultTagPrefix
+	}
+	filters := ""
+	if strings.TrimSpace(opts.Filters) != "" {
+		tree, err := Parse(opts.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("searchquery: filters: %w", err)
+		}
+		filters = Format(tree)
+		// A top-level OR would otherwise bind looser than the keywords:
+		// (k1 OR k2) from:a OR from:b matches every tweet from b.
+		if group, ok := tree.(*Group); ok && group.Or && !group.Negated {
+			filters = "(" + filters + ")"
+		}
+		filters = " " + filters
+	}
+
+	type item struct {
+		index  int
+		term   string
+		length int
+	}
+	var items []item
+	seen := make(map[string]bool)
+	for _, keyword := range keywords {
+		if strings.TrimSpace(keyword) == "" {
+			continue
+		}
+		term, err := keywordTerm(keyword)
+		if err != nil {
+			return nil, fmt.Errorf("searchquery: keyword %q: %w", keyword, err)
+		}
+		if seen[strings.ToLower(term)] {
+			continue
+		}
+		seen[strings.ToLower(term)] = true
+		items = append(items, item{index: len(items), term: term, length: len([]rune(term))})
+	}
+	if len(items) == 0 {
+		return nil, errors.New("searchquery: no keywords to pack")
+	}
+
+	// First-fit decreasing: place the longest keywords first, each into the
+	// first rule with room left.
+	order := make([]item, len(items))
+	copy(order, items)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].length > order[j].length })
+
+	type bin struct {
+		items []item
+		sum   int
+	}
+	ruleLength := func(count, sum int) int {
+		length := sum + len([]rune(filters))
+		if count > 1 {
+			length += 2 + len(" OR ")*(count-1)
+		}
+		return length
+	}
+	var bins []*bin
+	for _, it := range order {
+		if ruleLength(1, it.length) > opts.MaxLength {
+			return nil, fmt.Errorf("searchquery: keyword %q does not fit in a rule of %d characters", it.term, opts.MaxLength)
+		}
+		var target *bin
+		for _, b := range bins {
+			if ruleLength(len(b.items)+1, b.sum+it.length) <= opts.MaxLength {
+				target = b
+				break
+			}
+		}
+		if target == nil {
+			target = &bin{}
+			bins = append(bins, target)
+		}
+		target.items = append(target.items, it)
+		target.sum += it.length
+	}
+	if len(bins) > opts.MaxRules {
+		return nil, fmt.Errorf("searchquery: %d keywords need %d rules of up to %d characters; only %d are allowed", len(items), len(bins), opts.MaxLength, opts.MaxRules)
+	}
+
+	// Keep the keywords' input order within and across rules, so the same
+	// list packs into the same rules on every run.
+	for _, b := range bins {
+		sort.Slice(b.items, func(i, j int) bool { return b.items[i].index < b.items[j].index })
+	}
+	sort.Slice(bins, func(i, j int) bool { return bins[i].items[0].index < bins[j].items[0].index })
+
+	rules := make([]PackedRule, 0, len(bins))
+	for i, b := range bins {
+		terms := make([]string, len(b.items))
+		for j, it := range b.items {
+			terms[j] = it.term
+		}
+		value := terms[0]
+		if len(terms) > 1 {
+			value = "(" + strings.Join(terms, " OR ") + ")"
+		}
+		rules = append(rules, PackedRule{
+			Value:    value + filters,
+			Tag:      fmt.Sprintf("%s-%d", opts.TagPrefix, i+1),
+			Keywords: terms,
+		})
+	}
+	return rules, nil
+}
+
+// keywordTerm renders keyword as one OR alternative. Plain words separated by
+// spaces become an exact phrase; other fragments are kept, in parentheses when
+// they have several terms.
+func keywordTerm(keyword string) (string, error) {
+	keyword = strings.TrimSpace(keyword)
+	tree, err := Parse(keyword)
+	if err != nil {
+		if strings.Contains(keyword, `"`) {
+			return "", err
+		}
+		return quote(keyword), nil
+	}
+	group, ok := tree.(*Group)
+	if !ok {
+		return Format(tree), nil
+	}
+	if plainWords(group) {
+		return quote(strings.Join(strings.Fields(keyword), " ")), nil
+	}
+	if group.Negated {
+		return Format(tree), nil
+	}
+	return "(" + Format(tree) + ")", nil
+}
+
+// AllWords returns keyword so that Pack keeps several plain words as separate
+// terms that must all appear, as in a rule of machine learning, instead of
+// the exact phrase "machine learning". Other keywords are returned as they
+// are.
+func AllWords(keyword string) string {
+	tree, err := Parse(strings.TrimSpace(keyword))
+	if err != nil {
+		return keyword
+	}
+	if group, ok := tree.(*Group); ok && plainWords(group) {
+		return "(" + Format(group) + ")"
+	}
+	return keyword
+}
+
+// plainWords reports whether g is only bare keywords, as in machine learning.
+func plainWords(g *Group) bool {
+	if g.Or || g.Negated || g.Parens {
+		return false
+	}
+	for _, child := range g.Children {
+		term, ok := child.(*Term)
+		if !ok || term.Kind != Keyword || term.Negated {
+			return false
+		}
+	}
+	return true
+}
+
+// matchText evaluates n against lower-case text.
+func matchText(n Node, text string) bool {
+	switch n := n.(type) {
+	case *Term:
+		var match bool
+		switch n.Kind {
+		case Keyword, Phrase:
+			match = containsWords(text, strings.ToLower(n.Value))
+		case Hashtag:
+			match = containsWords(text, "#"+strings.ToLower(n.Value))
+		case Mention:
+			match = containsWords(text, "@"+strings.ToLower(n.Value))
+		case Cashtag:
+			match = containsWords(text, "$"+strings.ToLower(n.Value))
+		default:
+			return true
+		}
+		return match != n.Negated
+	case *Group:
+		match := !n.Or
+		for _, child := range n.Children {
+			if matchText(child, text) == n.Or {
+				match = n.Or
+				break
+			}
+		}
+		return match != n.Negated
+	}
+	return false
+}
+
+// containsWords reports whether text contains s with no letter or digit
+// directly before or after it.
+func containsWords(text, s string) bool {
+	if s == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], s)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(s)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
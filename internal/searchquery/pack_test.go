@@ -0,0 +1,96 @@
+package searchquery
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPack(t *testing.T) {
+	rules, err := Pack([]string{"golang", "machine learning", "#rust", "Golang", "(go generics)", `"exact phrase"`}, PackOptions{
+		Filters: "lang:en  -is:retweet",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []PackedRule{{
+		Value:    `(golang OR "machine learning" OR #rust OR (go generics) OR "exact phrase") lang:en -is:retweet`,
+		Tag:      "pack-1",
+		Keywords: []string{"golang", `"machine learning"`, "#rust", "(go generics)", `"exact phrase"`},
+	}}, rules)
+
+	// Keywords are split across as few rules as the length limit allows, in
+	// input order, and every rule fits.
+	var keywords []string
+	for i := range 60 {
+		keywords = append(keywords, fmt.Sprintf("keyword%02d%s", i, strings.Repeat("x", i%7)))
+	}
+	rules, err = Pack(keywords, PackOptions{Filters: "lang:en", MaxLength: 120, TagPrefix: "brand"})
+	require.NoError(t, err)
+	total := 0
+	for i, rule := range rules {
+		require.Equal(t, fmt.Sprintf("brand-%d", i+1), rule.Tag)
+		require.LessOrEqual(t, len(rule.Value), 120)
+		require.True(t, strings.HasSuffix(rule.Value, ") lang:en"), rule.Value)
+		total += len(rule.Keywords)
+	}
+	require.Equal(t, 60, total)
+	require.Equal(t, "keyword00", rules[0].Keywords[0])
+	require.Len(t, rules, 9)
+
+	_, err = Pack(keywords, PackOptions{MaxLength: 120, MaxRules: 3})
+	require.ErrorContains(t, err, "60 keywords need 9 rules of up to 120 characters; only 3 are allowed")
+
+	_, err = Pack([]string{strings.Repeat("a", 600)}, PackOptions{Tier: TierBasic})
+	require.ErrorContains(t, err, "does not fit in a rule of 512 characters")
+
+	_, err = Pack([]string{"golang"}, PackOptions{Filters: "lang:(en"})
+	require.EqualError(t, err, "searchquery: filters: column 6: missing ) for this (")
+
+	_, err = Pack([]string{" ", ""}, PackOptions{})
+	require.EqualError(t, err, "searchquery: no keywords to pack")
+}
+
+func TestAllWords(t *testing.T) {
+	require.Equal(t, "(machine learning)", AllWords("  machine   learning "))
+	require.Equal(t, "golang", AllWords("golang"))
+	require.Equal(t, `"machine learning"`, AllWords(`"machine learning"`))
+	require.Equal(t, "go -crypto", AllWords("go -crypto"))
+
+	rules, err := Pack([]string{AllWords("machine learning"), "deep learning"}, PackOptions{})
+	require.NoError(t, err)
+	require.Equal(t, `((machine learning) OR "deep learning")`, rules[0].Value)
+	require.Equal(t, []string{"(machine learning)"}, rules[0].Match("learning about machines and machine vision"))
+}
+
+func TestPackParenthesizesOrFilters(t *testing.T) {
+	rules, err := Pack([]string{"k1", "k2"}, PackOptions{Filters: "from:a OR from:b"})
+	require.NoError(t, err)
+	require.Equal(t, "(k1 OR k2) (from:a OR from:b)", rules[0].Value)
+
+	// The rule still requires a keyword: it is an AND of the two groups.
+	tree, err := Parse(rules[0].Value)
+	require.NoError(t, err)
+	group, ok := tree.(*Group)
+	require.True(t, ok)
+	require.False(t, group.Or)
+	require.Len(t, group.Children, 2)
+
+	rules, err = Pack([]string{"k1"}, PackOptions{Filters: "(from:a OR from:b) lang:en"})
+	require.NoError(t, err)
+	require.Equal(t, "k1 (from:a OR from:b) lang:en", rules[0].Value)
+
+	rules, err = Pack([]string{"k1"}, PackOptions{Filters: "-(from:a OR from:b)"})
+	require.NoError(t, err)
+	require.Equal(t, "k1 -(from:a OR from:b)", rules[0].Value)
+}
+
+func TestPackedRuleMatch(t *testing.T) {
+	rule := PackedRule{Keywords: []string{"go", `"machine learning"`, "#rust", "(ai -crypto)", "from:golang"}}
+
+	require.Equal(t, []string{"go", "from:golang"}, rule.Match("Go 1.25 is out"))
+	require.Equal(t, []string{"from:golang"}, rule.Match("golang release"))
+	require.Equal(t, []string{`"machine learning"`, "#rust", "from:golang"}, rule.Match("Machine Learning in #Rust!"))
+	require.Equal(t, []string{"(ai -crypto)", "from:golang"}, rule.Match("AI news"))
+	require.Equal(t, []string{"from:golang"}, rule.Match("AI crypto news"))
+}
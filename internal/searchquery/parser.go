@@ -0,0 +1,251 @@
+// Package searchquery parses, lints and formats the query language shared by
+// recent search and filtered stream rules, such as
+// ("bitcoin" OR ethereum) -is:retweet lang:en.
+package searchquery
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Node is a parsed query element: a *Term or a *Group.
+type Node interface {
+	// Position is the 1-based column where the element starts.
+	Position() int
+}
+
+// TermKind classifies a Term.
+type TermKind string
+
+// Term kinds.
+const (
+	Keyword  TermKind = "keyword"
+	Phrase   TermKind = "phrase"
+	Hashtag  TermKind = "hashtag"
+	Mention  TermKind = "mention"
+	Cashtag  TermKind = "cashtag"
+	Operator TermKind = "operator"
+)
+
+// Term is a single keyword, phrase, entity or name:value operator.
+type Term struct {
+	Kind TermKind
+	// Name is the lower-case operator name, e.g. "lang"; empty for others.
+	Name string
+	// Value is the keyword, the phrase without quotes, the entity without
+	// its #, @ or $, or the operator value without quotes.
+	Value string
+	// Quoted records that an operator value was written in quotes.
+	Quoted  bool
+	Negated bool
+	Pos     int
+}
+
+// Position implements Node.
+func (t *Term) Position() int { return t.Pos }
+
+// Group joins its children: all must match, or with Or at least one.
+type Group struct {
+	Or       bool
+	Children []Node
+	Negated  bool
+	// Parens records that the group was written in parentheses.
+	Parens bool
+	Pos    int
+}
+
+// Position implements Node.
+func (g *Group) Position() int { return g.Pos }
+
+// SyntaxError is a query that cannot be parsed.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+// operatorName matches the name part of name:value.
+var operatorName = regexp.MustCompile(`^[A-Za-z_]+$`)
+
+// Parse builds the syntax tree of input. Terms next to each other must all
+// match; OR binds more loosely, so a b OR c means (a b) OR c, as it does for
+// the API.
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, &SyntaxError{Pos: 1, Msg: "query is empty"}
+	}
+	p := &parser{tokens: tokens, end: len([]rune(input)) + 1}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unmatched )"}
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	end    int
+}
+
+func (p *parser) peek() token {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i]
+	}
+	return token{kind: tokEOF, pos: p.end}
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	if p.i < len(p.tokens) {
+		p.i++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	pos := p.peek().pos
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokOr {
+		return first, nil
+	}
+
+	group := &Group{Or: true, Children: []Node{first}, Pos: pos}
+	for p.peek().kind == tokOr {
+		or := p.next()
+		if kind := p.peek().kind; kind == tokEOF || kind == tokRParen || kind == tokOr {
+			return nil, &SyntaxError{Pos: or.pos, Msg: "OR needs a term on both sides"}
+		}
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		group.Children = append(group.Children, child)
+	}
+	return group, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	tok := p.peek()
+	group := &Group{Pos: tok.pos}
+	for {
+		switch p.peek().kind {
+		case tokEOF, tokRParen, tokOr:
+			switch len(group.Children) {
+			case 0:
+				if tok.kind == tokOr {
+					return nil, &SyntaxError{Pos: tok.pos, Msg: "OR needs a term on both sides"}
+				}
+				if tok.kind == tokRParen {
+					return nil, &SyntaxError{Pos: tok.pos, Msg: "unmatched )"}
+				}
+				return nil, &SyntaxError{Pos: tok.pos, Msg: "expected a term"}
+			case 1:
+				return group.Children[0], nil
+			default:
+				return group, nil
+			}
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		group.Children = append(group.Children, child)
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind != tokMinus {
+		return p.parsePrimary()
+	}
+	minus := p.next()
+	if p.peek().kind == tokMinus {
+		return nil, &SyntaxError{Pos: p.peek().pos, Msg: "double negation"}
+	}
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch n := node.(type) {
+	case *Term:
+		n.Negated, n.Pos = true, minus.pos
+	case *Group:
+		n.Negated, n.Pos = true, minus.pos
+	}
+	return node, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		if p.peek().kind == tokRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "empty parentheses"}
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "missing ) for this ("}
+		}
+		p.next()
+		if group, ok := node.(*Group); ok {
+			group.Parens, group.Pos = true, tok.pos
+		}
+		return node, nil
+	case tokPhrase:
+		return &Term{Kind: Phrase, Value: tok.text, Pos: tok.pos}, nil
+	case tokWord:
+		return classify(tok), nil
+	case tokRParen:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unmatched )"}
+	case tokOr:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "OR needs a term on both sides"}
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "expected a term"}
+	}
+}
+
+// classify turns a word into a keyword, entity or operator term.
+func classify(tok token) *Term {
+	word := tok.text
+	term := &Term{Kind: Keyword, Value: word, Pos: tok.pos}
+	if len(word) > 1 && !tok.quoted {
+		switch word[0] {
+		case '#':
+			term.Kind, term.Value = Hashtag, word[1:]
+			return term
+		case '@':
+			term.Kind, term.Value = Mention, word[1:]
+			return term
+		case '$':
+			if c := word[1]; c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' {
+				term.Kind, term.Value = Cashtag, word[1:]
+				return term
+			}
+		}
+	}
+	// URLs such as https://go.dev are keywords, not operators.
+	if name, value, ok := strings.Cut(word, ":"); ok && operatorName.MatchString(name) && !strings.HasPrefix(value, "//") {
+		term.Kind = Operator
+		term.Name = strings.ToLower(name)
+		term.Value = value
+		term.Quoted = tok.quoted
+	}
+	return term
+}
@@ -0,0 +1,135 @@
+package searchquery
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tree, err := Parse(`("bitcoin price" OR #btc) -is:retweet lang:en point_radius:[2.35 48.85 10km] bio:"go dev"`)
+	require.NoError(t, err)
+
+	root, ok := tree.(*Group)
+	require.True(t, ok)
+	require.False(t, root.Or)
+	require.Len(t, root.Children, 5)
+
+	or := root.Children[0].(*Group)
+	require.True(t, or.Or)
+	require.True(t, or.Parens)
+	require.Equal(t, &Term{Kind: Phrase, Value: "bitcoin price", Pos: 2}, or.Children[0])
+	require.Equal(t, &Term{Kind: Hashtag, Value: "btc", Pos: 21}, or.Children[1])
+
+	require.Equal(t, &Term{Kind: Operator, Name: "is", Value: "retweet", Negated: true, Pos: 27}, root.Children[1])
+	require.Equal(t, "[2.35 48.85 10km]", root.Children[3].(*Term).Value)
+	require.Equal(t, &Term{Kind: Operator, Name: "bio", Value: "go dev", Quoted: true, Pos: 78}, root.Children[4])
+
+	// AND binds tighter than OR, and URLs are keywords.
+	tree, err = Parse("a b OR https://go.dev")
+	require.NoError(t, err)
+	or = tree.(*Group)
+	require.True(t, or.Or)
+	require.Len(t, or.Children[0].(*Group).Children, 2)
+	require.Equal(t, Keyword, or.Children[1].(*Term).Kind)
+}
+
+func TestParseErrors(t *testing.T) {
+	for query, want := range map[string]string{
+		"":                  "column 1: query is empty",
+		"(a OR b":           "column 1: missing ) for this (",
+		"a b)":              "column 4: unmatched )",
+		"a OR":              "column 3: OR needs a term on both sides",
+		"OR a":              "column 1: OR needs a term on both sides",
+		`a "b c`:            "column 3: unterminated quote",
+		"a ()":              "column 3: empty parentheses",
+		"--a":               "column 2: double negation",
+		"point_radius:[1 2": "column 14: missing ] after [",
+	} {
+		_, err := Parse(query)
+		require.EqualError(t, err, want, query)
+	}
+}
+
+func TestLint(t *testing.T) {
+	issues := func(query string, opts Options) []string {
+		var messages []string
+		for _, issue := range Lint(query, opts).Issues {
+			messages = append(messages, issue.String())
+		}
+		return messages
+	}
+
+	require.Empty(t, issues(`(bitcoin OR #btc) -is:retweet lang:en`, Options{}))
+	require.Equal(t, []string{"column 1: error: unknown operator lnag: (did you mean lang:?)"},
+		issues("lnag:en", Options{}))
+	require.Equal(t, []string{"column 5: error: unknown operator is:retweeet (did you mean is:retweet?)"},
+		issues("cat -is:retweeet", Options{}))
+	require.Equal(t, []string{"column 5: error: bio: is only available in stream rules"},
+		issues("cat bio:gopher", Options{Endpoint: EndpointSearch}))
+	require.Empty(t, issues("cat bio:gopher", Options{Endpoint: EndpointStream}))
+	require.Equal(t, []string{"column 5: error: bio: needs pro access or higher"},
+		issues("cat bio:gopher", Options{Endpoint: EndpointStream, Tier: TierBasic}))
+	require.Equal(t, []string{"column 5: error: is:nullcast can only be used negated, as -is:nullcast"},
+		issues("cat is:nullcast", Options{}))
+	require.Equal(t, []string{"column 1: error: query needs at least one keyword, phrase, hashtag, mention or standalone operator that is not negated"},
+		issues("-cat lang:en", Options{}))
+	require.Equal(t, []string{"column 1: error: query needs at least one keyword, phrase, hashtag, mention or standalone operator that is not negated"},
+		issues("cat OR has:media", Options{}))
+	require.Empty(t, issues("from:golang has:media", Options{}))
+	require.Equal(t, []string{
+		`column 3: warning: "or" is searched as a word; write OR in capitals to combine terms`,
+		"column 8: warning: from: takes the username without @",
+	}, issues("a or b from:@golang", Options{}))
+	require.Equal(t, []string{"column 1: warning: terms next to each other bind tighter than OR; add parentheses to make the grouping explicit"},
+		issues("a b OR c", Options{}))
+
+	long := strings.Repeat("gopher ", 100)
+	result := Lint(long, Options{Tier: TierBasic})
+	require.Equal(t, 512, result.MaxLength)
+	require.EqualError(t, result.Err(), "searchquery: invalid query: column 1: query is 700 characters; the basic search limit is 512")
+	result = Lint(long, Options{})
+	require.NoError(t, result.Err())
+	require.Len(t, result.Warnings(), 1)
+
+	result = Lint("a (b", Options{})
+	require.Empty(t, result.Normalized)
+	require.Nil(t, result.Tree())
+	require.EqualError(t, result.Err(), "searchquery: invalid query: column 3: missing ) for this (")
+}
+
+func TestFormat(t *testing.T) {
+	for query, want := range map[string]string{
+		"  cat   dog ":                        "cat dog",
+		"(cat (dog bird)) LANG:EN":            "cat dog bird lang:en",
+		"a OR (b OR c)":                       "a OR b OR c",
+		"a b OR c":                            "(a b) OR c",
+		"x (a OR b) -(c OR d)":                "x (a OR b) -(c OR d)",
+		`from:@Golang bio:"go \"dev\"" "a b"`: `from:Golang bio:"go \"dev\"" "a b"`,
+		"point_radius:[1 2 3km] cat":          "point_radius:[1 2 3km] cat",
+	} {
+		got, err := Normalize(query)
+		require.NoError(t, err, query)
+		require.Equal(t, want, got, query)
+
+		again, err := Normalize(got)
+		require.NoError(t, err, got)
+		require.Equal(t, got, again, got)
+	}
+
+	tree, err := Parse("(bitcoin OR (eth gas)) -is:retweet")
+	require.NoError(t, err)
+	indented := Indent(tree)
+	require.Equal(t, `(
+  bitcoin
+  OR (
+    eth
+    gas
+  )
+)
+-is:retweet`, indented)
+	normalized, err := Normalize(indented)
+	require.NoError(t, err)
+	require.Equal(t, "(bitcoin OR (eth gas)) -is:retweet", normalized)
+}
@@ -0,0 +1,320 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Export formats.
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// parquetBatch is the number of rows buffered per WriteRows call.
+const parquetBatch = 1024
+
+// Export runs query and writes its rows to w as CSV with a header, or as a
+// Parquet file. It returns the number of rows written.
+func (s *Store) Export(ctx context.Context, w io.Writer, format, query string, args ...any) (int, error) {
+	rows, err := s.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var n int
+	switch format {
+	case FormatCSV:
+		n, err = exportCSV(w, rows)
+	case FormatParquet:
+		n, err = exportParquet(w, rows)
+	default:
+		return 0, fmt.Errorf("store: unsupported export format %q (use %s or %s)", format, FormatCSV, FormatParquet)
+	}
+	if err != nil {
+		return n, fmt.Errorf("store: export: %w", err)
+	}
+	return n, nil
+}
+
+// ScanRows reads every row as column name to value, with values as the
+// driver returns them: int64, float64, string or nil.
+func ScanRows(rows *sql.Rows) ([]string, []map[string]any, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	var records []map[string]any
+	for rows.Next() {
+		values, err := scanValues(rows, len(columns))
+		if err != nil {
+			return nil, nil, err
+		}
+		record := make(map[string]any, len(columns))
+		for i, column := range columns {
+			record[column] = values[i]
+		}
+		records = append(records, record)
+	}
+	return columns, records, rows.Err()
+}
+
+func scanValues(rows *sql.Rows, n int) ([]any, error) {
+	values := make([]any, n)
+	pointers := make([]any, n)
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			values[i] = string(b)
+		}
+	}
+	return values, nil
+}
+
+func exportCSV(w io.Writer, rows *sql.Rows) (int, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	record := make([]string, len(columns))
+	for rows.Next() {
+		values, err := scanValues(rows, len(columns))
+		if err != nil {
+			return n, err
+		}
+		for i, value := range values {
+			record[i] = formatValue(value)
+		}
+		if err := cw.Write(record); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	cw.Flush()
+	return n, cw.Error()
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parquetKind is the Parquet type a result column is written as.
+type parquetKind int
+
+const (
+	kindString parquetKind = iota
+	kindInt
+	kindDouble
+	kindTimestamp
+)
+
+// parquetColumn maps a result column to its Parquet leaf.
+type parquetColumn struct {
+	name  string
+	kind  parquetKind
+	index int // position in the result row
+}
+
+// exportParquet writes rows with one optional column per result column.
+// Types follow the declared SQLite column type, or the first row's value for
+// expressions such as count(*); timestamp columns of the schema (created_at,
+// first_seen, ...) become Parquet timestamps.
+func exportParquet(w io.Writer, rows *sql.Rows) (int, error) {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+	var first []any
+	if rows.Next() {
+		if first, err = scanValues(rows, len(types)); err != nil {
+			return 0, err
+		}
+	}
+
+	group := parquet.Group{}
+	columns := make([]parquetColumn, 0, len(types))
+	for i, columnType := range types {
+		column := parquetColumn{name: uniqueName(group, columnType.Name()), index: i}
+		decl := strings.ToUpper(columnType.DatabaseTypeName())
+		if decl == "" && first != nil {
+			switch first[i].(type) {
+			case int64:
+				decl = "INTEGER"
+			case float64:
+				decl = "REAL"
+			}
+		}
+		switch {
+		case strings.Contains(decl, "INT"):
+			column.kind = kindInt
+			group[column.name] = parquet.Optional(parquet.Int(64))
+		case strings.Contains(decl, "REAL"), strings.Contains(decl, "FLOA"), strings.Contains(decl, "DOUB"):
+			column.kind = kindDouble
+			group[column.name] = parquet.Optional(parquet.Leaf(parquet.DoubleType))
+		case isTimestampColumn(columnType.Name()):
+			column.kind = kindTimestamp
+			group[column.name] = parquet.Optional(parquet.Timestamp(parquet.Millisecond))
+		default:
+			column.kind = kindString
+			group[column.name] = parquet.Optional(parquet.String())
+		}
+		columns = append(columns, column)
+	}
+
+	schema := parquet.NewSchema("ctw", group)
+	// Parquet orders group fields by name; emit values in that order.
+	leaves := make([]parquetColumn, 0, len(columns))
+	for _, field := range schema.Fields() {
+		for _, column := range columns {
+			if column.name == field.Name() {
+				leaves = append(leaves, column)
+			}
+		}
+	}
+
+	writer := parquet.NewWriter(w, schema, parquet.Compression(&parquet.Zstd))
+	batch := make([]parquet.Row, 0, parquetBatch)
+	add := func(values []any) error {
+		row := make(parquet.Row, len(leaves))
+		for leaf, column := range leaves {
+			value := parquetValue(column.kind, values[column.index])
+			if value.IsNull() {
+				row[leaf] = value.Level(0, 0, leaf)
+			} else {
+				row[leaf] = value.Level(0, 1, leaf)
+			}
+		}
+		batch = append(batch, row)
+		if len(batch) < parquetBatch {
+			return nil
+		}
+		_, err := writer.WriteRows(batch)
+		batch = batch[:0]
+		return err
+	}
+
+	n := 0
+	if first != nil {
+		if err := add(first); err != nil {
+			return n, err
+		}
+		n++
+	}
+	for first != nil && rows.Next() {
+		values, err := scanValues(rows, len(types))
+		if err != nil {
+			return n, err
+		}
+		if err := add(values); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	if _, err := writer.WriteRows(batch); err != nil {
+		return n, err
+	}
+	return n, writer.Close()
+}
+
+// parquetValue converts a SQLite value to kind. Values that do not convert,
+// which SQLite's dynamic typing allows, are written as null.
+func parquetValue(kind parquetKind, value any) parquet.Value {
+	if value == nil {
+		return parquet.Value{}
+	}
+	switch kind {
+	case kindInt:
+		switch v := value.(type) {
+		case int64:
+			return parquet.Int64Value(v)
+		case float64:
+			return parquet.Int64Value(int64(v))
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return parquet.Int64Value(i)
+			}
+		}
+	case kindDouble:
+		switch v := value.(type) {
+		case int64:
+			return parquet.DoubleValue(float64(v))
+		case float64:
+			return parquet.DoubleValue(v)
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return parquet.DoubleValue(f)
+			}
+		}
+	case kindTimestamp:
+		switch v := value.(type) {
+		case time.Time:
+			return parquet.Int64Value(v.UnixMilli())
+		case string:
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return parquet.Int64Value(t.UnixMilli())
+			}
+		}
+	default:
+		return parquet.ByteArrayValue([]byte(formatValue(value)))
+	}
+	return parquet.Value{}
+}
+
+// isTimestampColumn reports whether name is one of the RFC 3339 text columns
+// of the schema.
+func isTimestampColumn(name string) bool {
+	return strings.HasSuffix(name, "_at") || name == "first_seen" || name == "last_seen"
+}
+
+// uniqueName suffixes repeated result column names, as in SELECT t.id, u.id.
+func uniqueName(group parquet.Group, name string) string {
+	if name == "" {
+		name = "column"
+	}
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := group[unique]; !ok {
+			return unique
+		}
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+}
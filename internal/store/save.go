@@ -0,0 +1,315 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+)
+
+// Upserts keep the newest value of every column the response carried, and
+// merge raw JSON so fields requested only once are not lost when a later
+// response asks for fewer.
+const (
+	upsertTweet = `
+INSERT INTO tweets (id, author_id, conversation_id, in_reply_to_user_id, created_at, lang, text, source,
+	possibly_sensitive, retweet_count, reply_count, like_count, quote_count, bookmark_count, impression_count,
+	raw, first_seen, last_seen)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	author_id = coalesce(excluded.author_id, author_id),
+	conversation_id = coalesce(excluded.conversation_id, conversation_id),
+	in_reply_to_user_id = coalesce(excluded.in_reply_to_user_id, in_reply_to_user_id),
+	created_at = coalesce(excluded.created_at, created_at),
+	lang = coalesce(excluded.lang, lang),
+	text = coalesce(excluded.text, text),
+	source = coalesce(excluded.source, source),
+	possibly_sensitive = coalesce(excluded.possibly_sensitive, possibly_sensitive),
+	retweet_count = coalesce(excluded.retweet_count, retweet_count),
+	reply_count = coalesce(excluded.reply_count, reply_count),
+	like_count = coalesce(excluded.like_count, like_count),
+	quote_count = coalesce(excluded.quote_count, quote_count),
+	bookmark_count = coalesce(excluded.bookmark_count, bookmark_count),
+	impression_count = coalesce(excluded.impression_count, impression_count),
+	raw = json_patch(raw, excluded.raw),
+	last_seen = excluded.last_seen`
+
+	upsertUser = `
+INSERT INTO users (id, username, name, description, location, url, created_at, verified, protected,
+	followers_count, following_count, tweet_count, listed_count, raw, first_seen, last_seen)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	username = coalesce(excluded.username, username),
+	name = coalesce(excluded.name, name),
+	description = coalesce(excluded.description, description),
+	location = coalesce(excluded.location, location),
+	url = coalesce(excluded.url, url),
+	created_at = coalesce(excluded.created_at, created_at),
+	verified = coalesce(excluded.verified, verified),
+	protected = coalesce(excluded.protected, protected),
+	followers_count = coalesce(excluded.followers_count, followers_count),
+	following_count = coalesce(excluded.following_count, following_count),
+	tweet_count = coalesce(excluded.tweet_count, tweet_count),
+	listed_count = coalesce(excluded.listed_count, listed_count),
+	raw = json_patch(raw, excluded.raw),
+	last_seen = excluded.last_seen`
+
+	upsertMedia = `
+INSERT INTO media (media_key, type, url, preview_image_url, alt_text, width, height, duration_ms, view_count,
+	raw, first_seen, last_seen)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (media_key) DO UPDATE SET
+	type = coalesce(excluded.type, type),
+	url = coalesce(excluded.url, url),
+	preview_image_url = coalesce(excluded.preview_image_url, preview_image_url),
+	alt_text = coalesce(excluded.alt_text, alt_text),
+	width = coalesce(excluded.width, width),
+	height = coalesce(excluded.height, height),
+	duration_ms = coalesce(excluded.duration_ms, duration_ms),
+	view_count = coalesce(excluded.view_count, view_count),
+	raw = json_patch(raw, excluded.raw),
+	last_seen = excluded.last_seen`
+
+	insertTweetMedia     = `INSERT OR IGNORE INTO tweet_media (tweet_id, media_key, position) VALUES (?, ?, ?)`
+	insertTweetReference = `INSERT OR IGNORE INTO tweet_references (tweet_id, type, referenced_tweet_id) VALUES (?, ?, ?)`
+	deleteTweetEntities  = `DELETE FROM tweet_entities WHERE tweet_id = ?`
+	insertTweetEntity    = `INSERT OR IGNORE INTO tweet_entities (tweet_id, type, value) VALUES (?, ?, ?)`
+	insertCollected      = `INSERT OR IGNORE INTO collected (tweet_id, source, collected_at) VALUES (?, ?, ?)`
+)
+
+// SaveResult counts the rows a save touched.
+type SaveResult struct {
+	Tweets int `json:"tweets"`
+	Users  int `json:"users"`
+	Media  int `json:"media"`
+}
+
+// SaveTweets upserts the tweets of response together with the users, media
+// and referenced tweets in its includes, in one transaction. Each tweet in
+// response.Data is recorded as collected by source, such as
+// "search recent: golang"; included tweets are archived without a source.
+func (s *Store) SaveTweets(ctx context.Context, response model.TweetsResponse, source string) (SaveResult, error) {
+	var result SaveResult
+	now := s.now().UTC().Format(time.RFC3339)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("store: save: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, tweet := range response.Data {
+		if err := saveTweet(ctx, tx, tweet, now); err != nil {
+			return result, err
+		}
+		if source != "" {
+			if _, err := tx.ExecContext(ctx, insertCollected, tweet.ID, source, now); err != nil {
+				return result, fmt.Errorf("store: save tweet %s: %w", tweet.ID, err)
+			}
+		}
+		result.Tweets++
+	}
+
+	if includes := response.Includes; includes != nil {
+		for _, tweet := range includes.Tweets {
+			if err := saveTweet(ctx, tx, tweet, now); err != nil {
+				return result, err
+			}
+			result.Tweets++
+		}
+		for _, user := range includes.Users {
+			if err := saveUser(ctx, tx, user, now); err != nil {
+				return result, err
+			}
+			result.Users++
+		}
+		for _, media := range includes.Media {
+			if err := saveMedia(ctx, tx, media, now); err != nil {
+				return result, err
+			}
+			result.Media++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return SaveResult{}, fmt.Errorf("store: save: %w", err)
+	}
+	return result, nil
+}
+
+// SaveTweet upserts a single tweet, such as one delivered by the filtered
+// stream, with its includes.
+func (s *Store) SaveTweet(ctx context.Context, tweet model.Tweet, includes *model.Includes, source string) (SaveResult, error) {
+	return s.SaveTweets(ctx, model.TweetsResponse{Data: []model.Tweet{tweet}, Includes: includes}, source)
+}
+
+// SaveUsers upserts users, such as a followers page.
+func (s *Store) SaveUsers(ctx context.Context, users []model.User) (SaveResult, error) {
+	now := s.now().UTC().Format(time.RFC3339)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return SaveResult{}, fmt.Errorf("store: save: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, user := range users {
+		if err := saveUser(ctx, tx, user, now); err != nil {
+			return SaveResult{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return SaveResult{}, fmt.Errorf("store: save: %w", err)
+	}
+	return SaveResult{Users: len(users)}, nil
+}
+
+func saveTweet(ctx context.Context, tx *sql.Tx, tweet model.Tweet, now string) error {
+	raw, err := json.Marshal(tweet)
+	if err != nil {
+		return fmt.Errorf("store: encode tweet %s: %w", tweet.ID, err)
+	}
+
+	var metrics [6]any
+	if m := tweet.PublicMetrics; m != nil {
+		metrics = [6]any{m.RetweetCount, m.ReplyCount, m.LikeCount, m.QuoteCount, m.BookmarkCount, m.ImpressionCount}
+	}
+	var sensitive any
+	if tweet.PossiblySensitive {
+		// The field is omitted when false, so only a true value is known.
+		sensitive = 1
+	}
+
+	if _, err := tx.ExecContext(ctx, upsertTweet,
+		tweet.ID, text(tweet.AuthorID), text(tweet.ConversationID), text(tweet.InReplyToUserID),
+		timestamp(tweet.CreatedAt), text(tweet.Lang), text(tweet.FullText()), text(tweet.Source), sensitive,
+		metrics[0], metrics[1], metrics[2], metrics[3], metrics[4], metrics[5],
+		string(raw), now, now,
+	); err != nil {
+		return fmt.Errorf("store: save tweet %s: %w", tweet.ID, err)
+	}
+
+	for _, ref := range tweet.ReferencedTweets {
+		if _, err := tx.ExecContext(ctx, insertTweetReference, tweet.ID, ref.Type, ref.ID); err != nil {
+			return fmt.Errorf("store: save tweet %s: %w", tweet.ID, err)
+		}
+	}
+	if tweet.Attachments != nil {
+		for i, key := range tweet.Attachments.MediaKeys {
+			if _, err := tx.ExecContext(ctx, insertTweetMedia, tweet.ID, key, i); err != nil {
+				return fmt.Errorf("store: save tweet %s: %w", tweet.ID, err)
+			}
+		}
+	}
+	if tweet.Entities != nil {
+		// Entities are only replaced when the response requested them.
+		if _, err := tx.ExecContext(ctx, deleteTweetEntities, tweet.ID); err != nil {
+			return fmt.Errorf("store: save tweet %s: %w", tweet.ID, err)
+		}
+		for _, entity := range entityValues(tweet.Entities) {
+			if _, err := tx.ExecContext(ctx, insertTweetEntity, tweet.ID, entity[0], entity[1]); err != nil {
+				return fmt.Errorf("store: save tweet %s: %w", tweet.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func saveUser(ctx context.Context, tx *sql.Tx, user model.User, now string) error {
+	raw, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("store: encode user %s: %w", user.ID, err)
+	}
+	var metrics [4]any
+	if m := user.PublicMetrics; m != nil {
+		metrics = [4]any{m.FollowersCount, m.FollowingCount, m.TweetCount, m.ListedCount}
+	}
+	if _, err := tx.ExecContext(ctx, upsertUser,
+		user.ID, text(user.Username), text(user.Name), text(user.Description), text(user.Location), text(user.URL),
+		timestamp(user.CreatedAt), flag(user.Verified), flag(user.Protected),
+		metrics[0], metrics[1], metrics[2], metrics[3],
+		string(raw), now, now,
+	); err != nil {
+		return fmt.Errorf("store: save user %s: %w", user.ID, err)
+	}
+	return nil
+}
+
+func saveMedia(ctx context.Context, tx *sql.Tx, media model.Media, now string) error {
+	raw, err := json.Marshal(media)
+	if err != nil {
+		return fmt.Errorf("store: encode media %s: %w", media.MediaKey, err)
+	}
+	var views any
+	if count, ok := media.PublicMetrics["view_count"]; ok {
+		views = count
+	}
+	if _, err := tx.ExecContext(ctx, upsertMedia,
+		media.MediaKey, text(media.Type), text(media.DownloadURL()), text(media.PreviewImageURL), text(media.AltText),
+		number(media.Width), number(media.Height), number(media.DurationMS), views,
+		string(raw), now, now,
+	); err != nil {
+		return fmt.Errorf("store: save media %s: %w", media.MediaKey, err)
+	}
+	return nil
+}
+
+// entityValues flattens entities to (type, value) pairs: hashtags and
+// cashtags without their symbol, mentioned usernames and expanded URLs.
+func entityValues(entities *model.Entities) [][2]string {
+	var values [][2]string
+	for _, tag := range entities.Hashtags {
+		values = append(values, [2]string{"hashtag", tag.Tag})
+	}
+	for _, tag := range entities.Cashtags {
+		values = append(values, [2]string{"cashtag", tag.Tag})
+	}
+	for _, mention := range entities.Mentions {
+		values = append(values, [2]string{"mention", mention.Username})
+	}
+	for _, url := range entities.URLs {
+		value := url.ExpandedURL
+		if url.UnwoundURL != "" {
+			value = url.UnwoundURL
+		}
+		if value == "" {
+			value = url.URL
+		}
+		values = append(values, [2]string{"url", value})
+	}
+	return values
+}
+
+// text, number and timestamp map unset fields to NULL so upserts keep the
+// stored value.
+func text(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func number(value int) any {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+func timestamp(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+// flag stores true as 1; false is indistinguishable from unrequested because
+// the API omits false booleans.
+func flag(value bool) any {
+	if value {
+		return 1
+	}
+	return nil
+}
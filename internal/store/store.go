@@ -0,0 +1,194 @@
+// Package store archives tweets, users and media from API responses in a
+// local SQLite database. Rows are upserted, so collecting the same tweets
+// repeatedly refreshes their metrics instead of duplicating them.
+//
+// The driver is modernc.org/sqlite, a pure-Go SQLite, so the ctw binary stays
+// free of cgo.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// schemaVersion is stored in PRAGMA user_version. Bump it and extend migrate
+// when the schema changes.
+const schemaVersion = 1
+
+const schema = `
+CREATE TABLE IF NOT EXISTS tweets (
+	id                  TEXT PRIMARY KEY,
+	author_id           TEXT,
+	conversation_id     TEXT,
+	in_reply_to_user_id TEXT,
+	created_at          TEXT,
+	lang                TEXT,
+	text                TEXT,
+	source              TEXT,
+	possibly_sensitive  INTEGER,
+	retweet_count       INTEGER,
+	reply_count         INTEGER,
+	like_count          INTEGER,
+	quote_count         INTEGER,
+	bookmark_count      INTEGER,
+	impression_count    INTEGER,
+	raw                 TEXT NOT NULL,
+	first_seen          TEXT NOT NULL,
+	last_seen           TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS tweets_author_id ON tweets (author_id);
+CREATE INDEX IF NOT EXISTS tweets_conversation_id ON tweets (conversation_id);
+CREATE INDEX IF NOT EXISTS tweets_created_at ON tweets (created_at);
+
+CREATE TABLE IF NOT EXISTS users (
+	id              TEXT PRIMARY KEY,
+	username        TEXT,
+	name            TEXT,
+	description     TEXT,
+	location        TEXT,
+	url             TEXT,
+	created_at      TEXT,
+	verified        INTEGER,
+	protected       INTEGER,
+	followers_count INTEGER,
+	following_count INTEGER,
+	tweet_count     INTEGER,
+	listed_count    INTEGER,
+	raw             TEXT NOT NULL,
+	first_seen      TEXT NOT NULL,
+	last_seen       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS users_username ON users (username COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS media (
+	media_key         TEXT PRIMARY KEY,
+	type              TEXT,
+	url               TEXT,
+	preview_image_url TEXT,
+	alt_text          TEXT,
+	width             INTEGER,
+	height            INTEGER,
+	duration_ms       INTEGER,
+	view_count        INTEGER,
+	raw               TEXT NOT NULL,
+	first_seen        TEXT NOT NULL,
+	last_seen         TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tweet_media (
+	tweet_id  TEXT NOT NULL,
+	media_key TEXT NOT NULL,
+	position  INTEGER NOT NULL,
+	PRIMARY KEY (tweet_id, media_key)
+);
+
+CREATE TABLE IF NOT EXISTS tweet_references (
+	tweet_id            TEXT NOT NULL,
+	type                TEXT NOT NULL,
+	referenced_tweet_id TEXT NOT NULL,
+	PRIMARY KEY (tweet_id, type, referenced_tweet_id)
+);
+CREATE INDEX IF NOT EXISTS tweet_references_referenced ON tweet_references (referenced_tweet_id);
+
+CREATE TABLE IF NOT EXISTS tweet_entities (
+	tweet_id TEXT NOT NULL,
+	type     TEXT NOT NULL,
+	value    TEXT NOT NULL,
+	PRIMARY KEY (tweet_id, type, value)
+);
+CREATE INDEX IF NOT EXISTS tweet_entities_value ON tweet_entities (type, value COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS collected (
+	tweet_id     TEXT NOT NULL,
+	source       TEXT NOT NULL,
+	collected_at TEXT NOT NULL,
+	PRIMARY KEY (tweet_id, source)
+);
+`
+
+// Tables lists the tables of the schema, in the order db export documents
+// them.
+var Tables = []string{"tweets", "users", "media", "tweet_media", "tweet_references", "tweet_entities", "collected"}
+
+// Store is an open archive.
+type Store struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// Open opens or creates the archive at path and brings its schema up to
+// date.
+func Open(ctx context.Context, path string) (*Store, error) {
+	return open(ctx, path, false)
+}
+
+// OpenReadOnly opens an existing archive for queries. Statements that would
+// modify it fail.
+func OpenReadOnly(ctx context.Context, path string) (*Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	return open(ctx, path, true)
+}
+
+func open(ctx context.Context, path string, readOnly bool) (*Store, error) {
+	// WAL lets db query read while watch is writing; busy_timeout makes the
+	// writer wait instead of failing with SQLITE_BUSY.
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	if readOnly {
+		dsn += "&_pragma=query_only(1)"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	// SQLite allows one writer; a single connection serialises upserts.
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db, now: time.Now}
+	if err := s.migrate(ctx, readOnly); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *Store) migrate(ctx context.Context, readOnly bool) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > schemaVersion {
+		return fmt.Errorf("schema version %d is newer than this ctw supports (%d)", version, schemaVersion)
+	}
+	if version == schemaVersion {
+		return nil
+	}
+	if readOnly {
+		return fmt.Errorf("schema version %d is older than %d; open it with --store once to upgrade", version, schemaVersion)
+	}
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", schemaVersion))
+	return err
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Query runs a SQL statement and returns its rows. The caller closes them.
+func (s *Store) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: query: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ctw.db")
+	s, err := Open(context.Background(), path)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	s.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	return s, path
+}
+
+func sampleResponse() model.TweetsResponse {
+	return model.TweetsResponse{
+		Data: []model.Tweet{{
+			ID:               "1",
+			Text:             "Hello #golang @gopher",
+			AuthorID:         "10",
+			CreatedAt:        time.Date(2024, 4, 30, 8, 0, 0, 0, time.UTC),
+			Lang:             "en",
+			PublicMetrics:    &model.PublicMetrics{LikeCount: 5, RetweetCount: 1},
+			ReferencedTweets: []model.ReferencedTweet{{Type: "quoted", ID: "2"}},
+			Attachments:      &model.Attachments{MediaKeys: []string{"3_1"}},
+			Entities: &model.Entities{
+				Hashtags: []model.Tag{{Tag: "golang"}},
+				Mentions: []model.Mention{{Username: "gopher"}},
+			},
+		}},
+		Includes: &model.Includes{
+			Users:  []model.User{{ID: "10", Username: "alice", Name: "Alice", PublicMetrics: &model.UserPublicMetrics{FollowersCount: 42}}},
+			Tweets: []model.Tweet{{ID: "2", Text: "quoted", AuthorID: "11"}},
+			Media:  []model.Media{{MediaKey: "3_1", Type: "photo", URL: "https://pbs.example/1.jpg", Width: 800}},
+		},
+	}
+}
+
+func queryValue(t *testing.T, s *Store, query string) any {
+	t.Helper()
+	rows, err := s.Query(context.Background(), query)
+	require.NoError(t, err)
+	defer rows.Close()
+	_, records, err := ScanRows(rows)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	for _, value := range records[0] {
+		return value
+	}
+	return nil
+}
+
+func TestSaveTweetsNormalizesAndUpserts(t *testing.T) {
+	s, _ := openTestStore(t)
+	ctx := context.Background()
+
+	result, err := s.SaveTweets(ctx, sampleResponse(), "search recent: golang")
+	require.NoError(t, err)
+	require.Equal(t, SaveResult{Tweets: 2, Users: 1, Media: 1}, result)
+
+	require.Equal(t, int64(5), queryValue(t, s, "SELECT like_count FROM tweets WHERE id = '1'"))
+	require.Equal(t, "2024-04-30T08:00:00Z", queryValue(t, s, "SELECT created_at FROM tweets WHERE id = '1'"))
+	require.Equal(t, "alice", queryValue(t, s, "SELECT u.username FROM tweets t JOIN users u ON u.id = t.author_id WHERE t.id = '1'"))
+	require.Equal(t, "2", queryValue(t, s, "SELECT referenced_tweet_id FROM tweet_references WHERE tweet_id = '1'"))
+	require.Equal(t, "https://pbs.example/1.jpg", queryValue(t, s, "SELECT m.url FROM tweet_media tm JOIN media m USING (media_key)"))
+	require.Equal(t, int64(2), queryValue(t, s, "SELECT count(*) FROM tweet_entities WHERE tweet_id = '1'"))
+
+	// A later, sparser response refreshes metrics and keeps everything else.
+	later := model.TweetsResponse{Data: []model.Tweet{{ID: "1", Text: "Hello #golang @gopher", PublicMetrics: &model.PublicMetrics{LikeCount: 9}}}}
+	_, err = s.SaveTweets(ctx, later, "timelines user: 10")
+	require.NoError(t, err)
+
+	require.Equal(t, int64(2), queryValue(t, s, "SELECT count(*) FROM tweets"))
+	require.Equal(t, int64(9), queryValue(t, s, "SELECT like_count FROM tweets WHERE id = '1'"))
+	require.Equal(t, "en", queryValue(t, s, "SELECT lang FROM tweets WHERE id = '1'"))
+	require.Equal(t, "en", queryValue(t, s, "SELECT raw ->> '$.lang' FROM tweets WHERE id = '1'"))
+	require.Equal(t, int64(2), queryValue(t, s, "SELECT count(*) FROM tweet_entities WHERE tweet_id = '1'"))
+	require.Equal(t, int64(2), queryValue(t, s, "SELECT count(*) FROM collected WHERE tweet_id = '1'"))
+}
+
+func TestOpenReadOnlyRejectsWrites(t *testing.T) {
+	s, path := openTestStore(t)
+	_, err := s.SaveTweets(context.Background(), sampleResponse(), "")
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	ro, err := OpenReadOnly(context.Background(), path)
+	require.NoError(t, err)
+	defer ro.Close()
+	_, err = ro.Query(context.Background(), "DELETE FROM tweets")
+	require.Error(t, err)
+
+	_, err = OpenReadOnly(context.Background(), filepath.Join(t.TempDir(), "missing.db"))
+	require.Error(t, err)
+}
+
+func TestExport(t *testing.T) {
+	s, _ := openTestStore(t)
+	ctx := context.Background()
+	_, err := s.SaveTweets(ctx, sampleResponse(), "search recent: golang")
+	require.NoError(t, err)
+
+	var csvOut bytes.Buffer
+	n, err := s.Export(ctx, &csvOut, FormatCSV, "SELECT id, like_count, text FROM tweets ORDER BY id")
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, "id,like_count,text\n1,5,Hello #golang @gopher\n2,,quoted\n", csvOut.String())
+
+	var parquetOut bytes.Buffer
+	n, err = s.Export(ctx, &parquetOut, FormatParquet, "SELECT id, like_count, created_at, count(*) OVER () AS total FROM tweets ORDER BY id")
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	file, err := parquet.OpenFile(bytes.NewReader(parquetOut.Bytes()), int64(parquetOut.Len()))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), file.NumRows())
+
+	type exported struct {
+		ID        string     `parquet:"id,optional"`
+		LikeCount *int64     `parquet:"like_count,optional"`
+		CreatedAt *time.Time `parquet:"created_at,optional,timestamp(millisecond)"`
+		Total     *int64     `parquet:"total,optional"`
+	}
+	rows := make([]exported, 2)
+	reader := parquet.NewGenericReader[exported](file)
+	_, _ = reader.Read(rows)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "1", rows[0].ID)
+	require.Equal(t, int64(5), *rows[0].LikeCount)
+	require.True(t, rows[0].CreatedAt.Equal(time.Date(2024, 4, 30, 8, 0, 0, 0, time.UTC)))
+	require.Equal(t, int64(2), *rows[1].Total)
+	require.Nil(t, rows[1].LikeCount)
+
+	_, err = s.Export(ctx, &csvOut, "xlsx", "SELECT 1")
+	require.ErrorContains(t, err, "unsupported export format")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
 const (
@@ -13,7 +14,8 @@ const (
 	removeBookmarkPathFormat = "/2/users/%s/bookmarks/%s"
 )
 
-// Service coordinates Twitter bookmark operations.
+// Service coordinates Twitter bookmark operations. An empty userID stands for
+// the authenticated user.
 type Service struct {
 	client *client.Client
 }
@@ -31,8 +33,9 @@ func (s *Service) Add(ctx context.Context, userID, tweetID string) (BookmarkResp
 	if s == nil {
 		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: nil service")
 	}
-	if userID == "" {
-		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: user id is required")
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: %w", err)
 	}
 	if tweetID == "" {
 		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: tweet id is required")
@@ -65,8 +68,9 @@ func (s *Service) Remove(ctx context.Context, userID, tweetID string) (BookmarkR
 	if s == nil {
 		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: nil service")
 	}
-	if userID == "" {
-		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: user id is required")
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: %w", err)
 	}
 	if tweetID == "" {
 		return BookmarkResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: tweet id is required")
@@ -94,30 +98,31 @@ func (s *Service) Remove(ctx context.Context, userID, tweetID string) (BookmarkR
 }
 
 // List fetches the bookmarked tweets for the specified user.
-func (s *Service) List(ctx context.Context, userID string, params map[string]string) (BookmarksListResponse, client.RateLimitSnapshot, error) {
+func (s *Service) List(ctx context.Context, userID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return BookmarksListResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: nil service")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: nil service")
 	}
-	if userID == "" {
-		return BookmarksListResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: user id is required")
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("bookmarks: %w", err)
 	}
 
 	path := fmt.Sprintf(bookmarksPathFormat, userID)
 
 	resp, err := s.client.Get(ctx, path, params)
 	if err != nil {
-		return BookmarksListResponse{}, client.RateLimitSnapshot{}, err
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return BookmarksListResponse{}, rateLimits, err
+		return model.TweetsResponse{}, rateLimits, err
 	}
 
-	var result BookmarksListResponse
+	var result model.TweetsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return BookmarksListResponse{}, rateLimits, fmt.Errorf("bookmarks: decode response: %w", err)
+		return model.TweetsResponse{}, rateLimits, fmt.Errorf("bookmarks: decode response: %w", err)
 	}
 
 	return result, rateLimits, nil
@@ -64,3 +64,23 @@ func TestList(t *testing.T) {
 	require.Equal(t, "bookmarked tweet", resp.Data[0].Text)
 	require.Equal(t, 75, rateLimits.Limit)
 }
+
+func TestListDefaultsToAuthenticatedUser(t *testing.T) {
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/2/users/me" {
+			_, _ = w.Write([]byte(`{"data":{"id":"321","name":"Me","username":"me"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[],"meta":{"result_count":0}}`))
+	}
+
+	service := newTestService(t, handler)
+
+	for range 2 {
+		_, _, err := service.List(context.Background(), "", nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"/2/users/me", "/2/users/321/bookmarks", "/2/users/321/bookmarks"}, paths)
+}
@@ -0,0 +1,175 @@
+// Package conversation reconstructs reply threads from search and lookup
+// results.
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/tweet/lookup"
+	recentsearch "github.com/0dayfall/ctw/internal/tweet/recentsearch"
+)
+
+// threadFields are the tweet fields a thread cannot be built without.
+var threadFields = []string{"author_id", "conversation_id", "created_at", "in_reply_to_user_id", "referenced_tweets"}
+
+// Service fetches conversations.
+type Service struct {
+	lookup *lookup.Service
+	search *recentsearch.Service
+}
+
+// NewService constructs a Service backed by the supplied client.
+func NewService(c *client.Client) *Service {
+	if c == nil {
+		panic("conversation: nil client")
+	}
+	return &Service{lookup: lookup.NewService(c), search: recentsearch.NewService(c)}
+}
+
+// Options tunes Fetch.
+type Options struct {
+	// FullArchive searches /2/tweets/search/all instead of the last seven
+	// days of recent search.
+	FullArchive bool
+	// MaxPages stops the search after this many pages; 0 reads every page.
+	MaxPages int
+	// Fields holds tweet.fields, user.fields, expansions and the like for
+	// every request. The fields a thread needs and the author expansion are
+	// always added.
+	Fields map[string]string
+	// SearchParams are added to the search requests only, e.g. start_time.
+	SearchParams map[string]string
+}
+
+// Fetch reconstructs the conversation tweetID belongs to. It looks the tweet
+// up for its conversation_id, searches conversation_id:<id> page by page and
+// then looks up replied-to tweets the search did not return, such as the root
+// or replies older than the search window, until every parent is known or
+// reported missing.
+func (s *Service) Fetch(ctx context.Context, tweetID string, opts Options) (Thread, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return Thread{}, client.RateLimitSnapshot{}, fmt.Errorf("conversation: nil service")
+	}
+	if strings.TrimSpace(tweetID) == "" {
+		return Thread{}, client.RateLimitSnapshot{}, fmt.Errorf("conversation: tweet id is required")
+	}
+	params := threadParams(opts.Fields)
+
+	start, rateLimits, err := s.lookup.GetTweet(ctx, tweetID, params)
+	if err != nil {
+		return Thread{}, rateLimits, err
+	}
+	if len(start.Data) == 0 {
+		if missing := lookup.MissingTweets([]string{tweetID}, start); len(missing) > 0 && missing[0].Detail != "" {
+			return Thread{}, rateLimits, fmt.Errorf("conversation: tweet %s: %s", tweetID, missing[0].Detail)
+		}
+		return Thread{}, rateLimits, fmt.Errorf("conversation: tweet %s not found", tweetID)
+	}
+	conversationID := start.Data[0].ConversationID
+	if conversationID == "" {
+		return Thread{}, rateLimits, fmt.Errorf("conversation: tweet %s has no conversation_id", tweetID)
+	}
+
+	all := model.TweetsResponse{Data: start.Data, Includes: start.Includes}
+	search := s.search.SearchRecent
+	if opts.FullArchive {
+		search = s.search.SearchAll
+	}
+	searchParams := make(map[string]string, len(params)+len(opts.SearchParams)+1)
+	for k, v := range opts.SearchParams {
+		searchParams[k] = v
+	}
+	for k, v := range params {
+		searchParams[k] = v
+	}
+	if _, ok := searchParams["max_results"]; !ok {
+		searchParams["max_results"] = "100"
+	}
+	next, err := client.Paginate(searchParams, client.PaginationTokenParam, opts.MaxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := search(ctx, "conversation_id:"+conversationID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Data = append(all.Data, resp.Data...)
+		all.Includes = all.Includes.Merge(resp.Includes)
+		return resp.Meta.NextToken, nil
+	})
+	if err != nil {
+		return Thread{}, rateLimits, fmt.Errorf("conversation: search: %w", err)
+	}
+	truncated := next != ""
+
+	var missing []lookup.Missing
+	tried := make(map[string]bool)
+	for {
+		wanted := missingParents(conversationID, all.Data, tried)
+		if len(wanted) == 0 {
+			break
+		}
+		resp, limits, err := s.lookup.GetTweets(ctx, wanted, params)
+		rateLimits = limits
+		if err != nil {
+			return Thread{}, rateLimits, fmt.Errorf("conversation: look up parents: %w", err)
+		}
+		all.Data = append(all.Data, resp.Data...)
+		all.Includes = all.Includes.Merge(resp.Includes)
+		missing = append(missing, lookup.MissingTweets(wanted, resp)...)
+	}
+
+	thread := Build(conversationID, all)
+	thread.Missing = missing
+	thread.Truncated = truncated
+	return thread, rateLimits, nil
+}
+
+// missingParents returns the replied-to tweets of tweets, and the root, that
+// are neither among tweets nor tried yet. They are marked as tried.
+func missingParents(conversationID string, tweets []model.Tweet, tried map[string]bool) []string {
+	have := make(map[string]bool, len(tweets))
+	for _, tweet := range tweets {
+		have[tweet.ID] = true
+	}
+	var wanted []string
+	want := func(id string) {
+		if id != "" && !have[id] && !tried[id] {
+			tried[id] = true
+			wanted = append(wanted, id)
+		}
+	}
+	want(conversationID)
+	for _, tweet := range tweets {
+		want(parentID(tweet))
+	}
+	return wanted
+}
+
+// threadParams returns params with the thread fields, the author expansion
+// and usernames added to whatever the caller selected.
+func threadParams(params map[string]string) map[string]string {
+	out := make(map[string]string, len(params)+3)
+	for k, v := range params {
+		out[k] = v
+	}
+	out["tweet.fields"] = addNames(out["tweet.fields"], threadFields...)
+	out["expansions"] = addNames(out["expansions"], "author_id")
+	out["user.fields"] = addNames(out["user.fields"], "username")
+	return out
+}
+
+// addNames appends names missing from the comma-separated list.
+func addNames(list string, names ...string) string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range append(strings.Split(list, ","), names...) {
+		if item = strings.TrimSpace(item); item != "" && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, ",")
+}
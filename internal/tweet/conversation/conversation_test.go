@@ -0,0 +1,122 @@
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/tweet/lookup"
+	"github.com/stretchr/testify/require"
+)
+
+func tweetJSON(id, parent, text string) string {
+	refs := ""
+	if parent != "" {
+		refs = fmt.Sprintf(`,"referenced_tweets":[{"type":"replied_to","id":%q}]`, parent)
+	}
+	author := "u" + id
+	return fmt.Sprintf(`{"id":%q,"text":%q,"author_id":%q,"conversation_id":"1","created_at":"2024-05-01T12:0%s:00Z"%s}`, id, text, author, id, refs)
+}
+
+func TestFetch(t *testing.T) {
+	var requests []string
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		require.Contains(t, query.Get("tweet.fields"), "conversation_id")
+		require.Contains(t, query.Get("tweet.fields"), "public_metrics")
+		require.Equal(t, "author_id", query.Get("expansions"))
+		requests = append(requests, r.URL.Path+" "+query.Get("ids")+query.Get("pagination_token"))
+
+		users := `"includes":{"users":[{"id":"u1","username":"root"},{"id":"u2","username":"two"},{"id":"u3","username":"three"},{"id":"u4","username":"four"},{"id":"u5","username":"five"},{"id":"u6","username":"six"}]}`
+		switch {
+		case r.URL.Path == "/2/tweets/5":
+			fmt.Fprintf(w, `{"data":%s,%s}`, tweetJSON("5", "4", "five"), users)
+		case r.URL.Path == "/2/tweets/search/recent":
+			require.Equal(t, "conversation_id:1", query.Get("query"))
+			require.Equal(t, "2024-04-30T00:00:00Z", query.Get("start_time"))
+			require.Equal(t, "100", query.Get("max_results"))
+			if query.Get("pagination_token") == "" {
+				fmt.Fprintf(w, `{"data":[%s,%s],%s,"meta":{"next_token":"p2"}}`, tweetJSON("5", "4", "five"), tweetJSON("3", "1", "three"), users)
+				return
+			}
+			fmt.Fprintf(w, `{"data":[%s],%s,"meta":{}}`, tweetJSON("6", "9", "six"), users)
+		case r.URL.Path == "/2/tweets" && query.Get("ids") == "1,4,9":
+			require.Empty(t, query.Get("start_time"))
+			fmt.Fprintf(w, `{"data":[%s,%s],%s,"errors":[{"resource_id":"9","resource_type":"tweet","type":"https://api.twitter.com/2/problems/resource-not-found"}]}`,
+				tweetJSON("1", "", "root\ntweet"), tweetJSON("4", "2", "four"), users)
+		case r.URL.Path == "/2/tweets" && query.Get("ids") == "2":
+			fmt.Fprintf(w, `{"data":[%s],%s}`, tweetJSON("2", "1", "two"), users)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	thread, _, err := service.Fetch(context.Background(), "5", Options{
+		Fields:       map[string]string{"tweet.fields": "public_metrics"},
+		SearchParams: map[string]string{"start_time": "2024-04-30T00:00:00Z"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"/2/tweets/5 ", "/2/tweets/search/recent ", "/2/tweets/search/recent p2", "/2/tweets 1,4,9", "/2/tweets 2"}, requests)
+
+	require.Equal(t, "1", thread.ConversationID)
+	require.Equal(t, 6, thread.TweetCount)
+	require.Equal(t, []lookup.Missing{{ID: "9", Reason: lookup.ReasonNotFound}}, thread.Missing)
+	require.Equal(t, strings.Join([]string{
+		"@root: root tweet [1]",
+		"  @two: two [2]",
+		"    @four: four [4]",
+		"      @five: five [5]",
+		"  @three: three [3]",
+		"[tweet 9 unavailable: not_found]",
+		"  @six: six [6]",
+		"",
+	}, "\n"), thread.Indented())
+}
+
+func TestFetchMaxPages(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2/tweets/1":
+			fmt.Fprintf(w, `{"data":%s}`, tweetJSON("1", "", "root"))
+		case "/2/tweets/search/all":
+			fmt.Fprintf(w, `{"data":[%s],"meta":{"next_token":"more"}}`, tweetJSON("2", "1", "two"))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	thread, _, err := service.Fetch(context.Background(), "1", Options{FullArchive: true, MaxPages: 1})
+	require.NoError(t, err)
+	require.True(t, thread.Truncated)
+	require.Equal(t, "1", thread.Root.ID)
+	require.Len(t, thread.Root.Replies, 1)
+}
+
+func TestFetchReportsUnavailableTweet(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"resource_id":"7","resource_type":"tweet","detail":"Could not find tweet with id: [7].","type":"https://api.twitter.com/2/problems/resource-not-found"}]}`))
+	})
+
+	_, _, err := service.Fetch(context.Background(), "7", Options{})
+	require.EqualError(t, err, "conversation: tweet 7: Could not find tweet with id: [7].")
+}
+
+func TestBuildWithoutRoot(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	thread := Build("1", model.TweetsResponse{Data: []model.Tweet{
+		{ID: "3", ConversationID: "1", CreatedAt: at.Add(time.Minute), ReferencedTweets: []model.ReferencedTweet{{Type: "replied_to", ID: "1"}}},
+		{ID: "2", ConversationID: "1", CreatedAt: at, ReferencedTweets: []model.ReferencedTweet{{Type: "replied_to", ID: "1"}}},
+		{ID: "8", ConversationID: "other"},
+	}})
+
+	require.Equal(t, 2, thread.TweetCount)
+	require.True(t, thread.Root.Unavailable)
+	require.Equal(t, "2", thread.Root.Replies[0].ID)
+	require.Equal(t, "3", thread.Root.Replies[1].ID)
+	require.Empty(t, thread.Detached)
+}
@@ -0,0 +1,146 @@
+package conversation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/tweet/lookup"
+)
+
+// Thread is a conversation as a reply tree.
+type Thread struct {
+	ConversationID string `json:"conversation_id"`
+	// TweetCount is the number of tweets in the tree, not counting
+	// unavailable ones.
+	TweetCount int `json:"tweet_count"`
+	// Root is the tweet that started the conversation.
+	Root *Node `json:"root,omitempty"`
+	// Detached holds replies to unavailable tweets whose own parent is
+	// unknown, so they cannot be placed under Root.
+	Detached []*Node `json:"detached,omitempty"`
+	// Missing lists the replied-to tweets the lookup did not return.
+	Missing []lookup.Missing `json:"missing,omitempty"`
+	// Truncated reports that the search stopped at the page limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Node is a tweet and the replies to it, oldest first.
+type Node struct {
+	model.Tweet
+	Username string `json:"username,omitempty"`
+	// Unavailable marks a tweet that was replied to but could not be
+	// fetched; only its ID is set.
+	Unavailable bool    `json:"unavailable,omitempty"`
+	Replies     []*Node `json:"replies,omitempty"`
+}
+
+// Build arranges the tweets of resp that belong to conversationID into a
+// reply tree. Authors' usernames come from the response's includes. A reply
+// whose parent is not in resp hangs off an unavailable placeholder.
+func Build(conversationID string, resp model.TweetsResponse) Thread {
+	thread := Thread{ConversationID: conversationID}
+	nodes := make(map[string]*Node)
+	var tweets []model.Tweet
+	for _, tweet := range resp.Data {
+		if nodes[tweet.ID] != nil || tweet.ConversationID != "" && tweet.ConversationID != conversationID {
+			continue
+		}
+		node := &Node{Tweet: tweet}
+		if author, ok := resp.AuthorOf(tweet); ok {
+			node.Username = author.Username
+		}
+		nodes[tweet.ID] = node
+		tweets = append(tweets, tweet)
+	}
+	thread.TweetCount = len(tweets)
+
+	sort.SliceStable(tweets, func(i, j int) bool { return olderThan(tweets[i], tweets[j]) })
+	var placeholders []*Node
+	for _, tweet := range tweets {
+		if tweet.ID == conversationID {
+			continue
+		}
+		parent := parentID(tweet)
+		if parent == "" {
+			parent = conversationID
+		}
+		node := nodes[parent]
+		if node == nil {
+			node = &Node{Tweet: model.Tweet{ID: parent}, Unavailable: true}
+			nodes[parent] = node
+			placeholders = append(placeholders, node)
+		}
+		node.Replies = append(node.Replies, nodes[tweet.ID])
+	}
+
+	thread.Root = nodes[conversationID]
+	for _, node := range placeholders {
+		if node.ID != conversationID {
+			thread.Detached = append(thread.Detached, node)
+		}
+	}
+	return thread
+}
+
+// Indented renders the thread one tweet per line, replies indented under
+// their parent:
+//
+//	@gopher: Go 1.25 is out [1]
+//	  @alice: congrats! [2]
+func (t Thread) Indented() string {
+	reasons := make(map[string]string, len(t.Missing))
+	for _, m := range t.Missing {
+		reasons[m.ID] = m.Reason
+	}
+
+	var b strings.Builder
+	var write func(node *Node, depth int)
+	write = func(node *Node, depth int) {
+		b.WriteString(strings.Repeat("  ", depth))
+		switch {
+		case node.Unavailable:
+			reason := reasons[node.ID]
+			if reason == "" {
+				reason = lookup.ReasonMissing
+			}
+			fmt.Fprintf(&b, "[tweet %s unavailable: %s]\n", node.ID, reason)
+		default:
+			author := node.AuthorID
+			if node.Username != "" {
+				author = "@" + node.Username
+			}
+			fmt.Fprintf(&b, "%s: %s [%s]\n", author, strings.Join(strings.Fields(node.FullText()), " "), node.ID)
+		}
+		for _, reply := range node.Replies {
+			write(reply, depth+1)
+		}
+	}
+	if t.Root != nil {
+		write(t.Root, 0)
+	}
+	for _, node := range t.Detached {
+		write(node, 0)
+	}
+	return b.String()
+}
+
+// parentID returns the ID of the tweet that tweet replies to.
+func parentID(tweet model.Tweet) string {
+	for _, ref := range tweet.ReferencedTweets {
+		if ref.Type == "replied_to" {
+			return ref.ID
+		}
+	}
+	return ""
+}
+
+// olderThan orders tweets by creation time, then by ID, which grows with
+// time.
+func olderThan(a, b model.Tweet) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return model.NewerID(b.ID, a.ID)
+}
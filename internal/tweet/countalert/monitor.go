@@ -0,0 +1,352 @@
+// Package countalert watches the per-minute tweet count of a query and
+// notifies when it spikes above a rolling baseline.
+package countalert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/ratelimit"
+	"github.com/0dayfall/ctw/internal/tweet/countseries"
+	recentcount "github.com/0dayfall/ctw/internal/tweet/recentcount"
+)
+
+const (
+	// DefaultInterval is the time between polls.
+	DefaultInterval = 5 * time.Minute
+	// DefaultBaseline is the window of minutes a bucket is compared with.
+	DefaultBaseline = time.Hour
+	// DefaultSettle is how long after its end a minute is considered
+	// complete; the counts of the newest minutes still grow for a while.
+	DefaultSettle = time.Minute
+	// DefaultNotifyTimeout bounds one notifier call.
+	DefaultNotifyTimeout = 30 * time.Second
+
+	// minBaseline is the fewest baseline minutes a bucket is judged against.
+	minBaseline = 10
+	// maxLookback is how far back the recent counts endpoint reaches.
+	maxLookback = 7*24*time.Hour - time.Hour
+
+	granularity = "minute"
+)
+
+// Counter is the part of recentcount.Service the monitor reads from.
+type Counter interface {
+	GetRecentCount(ctx context.Context, query, granularity string, params map[string]string) (recentcount.CountResponse, client.RateLimitSnapshot, error)
+}
+
+// Threshold is a level a minute's count is compared with: a z-score against
+// the baseline ("3sigma") or an absolute count ("500").
+type Threshold struct {
+	Value float64
+	Sigma bool
+}
+
+// ParseThreshold reads "3sigma", "2.5σ" or a plain count such as "500".
+func ParseThreshold(s string) (Threshold, error) {
+	text := strings.TrimSpace(strings.ToLower(s))
+	t := Threshold{}
+	for _, suffix := range []string{"sigma", "σ"} {
+		if trimmed, ok := strings.CutSuffix(text, suffix); ok {
+			text, t.Sigma = strings.TrimSpace(trimmed), true
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value <= 0 {
+		return Threshold{}, fmt.Errorf("countalert: invalid threshold %q (expected e.g. 3sigma or 500)", s)
+	}
+	t.Value = value
+	return t, nil
+}
+
+// String formats t as ParseThreshold reads it.
+func (t Threshold) String() string {
+	value := strconv.FormatFloat(t.Value, 'f', -1, 64)
+	if t.Sigma {
+		return value + "sigma"
+	}
+	return value
+}
+
+// reached reports whether a minute with count and z-score z is at or above t.
+func (t Threshold) reached(count int, z float64) bool {
+	if t.Sigma {
+		return z >= t.Value
+	}
+	return float64(count) >= t.Value
+}
+
+// Config tunes a Monitor. Zero values select the defaults.
+type Config struct {
+	Query string
+	// StatePath is the file holding the baseline and alert status.
+	StatePath string
+	// Interval is the time between polls.
+	Interval time.Duration
+	// Baseline is the window of earlier minutes a minute is compared with.
+	Baseline time.Duration
+	// Threshold starts an alert.
+	Threshold Threshold
+	// Clear ends it once a minute falls below it, which keeps a count that
+	// hovers around Threshold from alerting again and again. It defaults to
+	// half of Threshold.
+	Clear Threshold
+	// Settle is how long after its end a minute is judged.
+	Settle time.Duration
+	// NotifyTimeout bounds one notifier call.
+	NotifyTimeout time.Duration
+}
+
+// Monitor polls a query's per-minute counts and notifies on spikes.
+type Monitor struct {
+	cfg       Config
+	counter   Counter
+	notifiers []Notifier
+	logger    *slog.Logger
+
+	// DryRun logs alerts instead of sending them and never writes the state
+	// file.
+	DryRun bool
+
+	now   func() time.Time
+	state *state
+}
+
+// New constructs a Monitor. It panics when the counter is missing, like the
+// service constructors.
+func New(cfg Config, counter Counter, notifiers []Notifier, logger *slog.Logger) (*Monitor, error) {
+	if counter == nil {
+		panic("countalert: nil counter")
+	}
+	if strings.TrimSpace(cfg.Query) == "" {
+		return nil, errors.New("countalert: query is required")
+	}
+	if cfg.Threshold.Value <= 0 {
+		return nil, errors.New("countalert: threshold is required")
+	}
+	if cfg.Clear.Value <= 0 {
+		cfg.Clear = Threshold{Value: cfg.Threshold.Value / 2, Sigma: cfg.Threshold.Sigma}
+	}
+	if cfg.Clear.Sigma == cfg.Threshold.Sigma && cfg.Clear.Value > cfg.Threshold.Value {
+		return nil, fmt.Errorf("countalert: clear level %s is above the threshold %s", cfg.Clear, cfg.Threshold)
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Baseline <= 0 {
+		cfg.Baseline = DefaultBaseline
+	}
+	if cfg.Baseline < minBaseline*time.Minute || cfg.Baseline > maxLookback {
+		return nil, fmt.Errorf("countalert: baseline must be between %d minutes and %s", minBaseline, maxLookback)
+	}
+	if cfg.Settle <= 0 {
+		cfg.Settle = DefaultSettle
+	}
+	if cfg.NotifyTimeout <= 0 {
+		cfg.NotifyTimeout = DefaultNotifyTimeout
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &Monitor{
+		cfg:       cfg,
+		counter:   counter,
+		notifiers: notifiers,
+		logger:    logger.With("query", cfg.Query),
+		now:       time.Now,
+	}, nil
+}
+
+// CheckResult summarises one poll.
+type CheckResult struct {
+	// Evaluated is the number of new complete minutes judged.
+	Evaluated int `json:"evaluated"`
+	// Latest is the newest judged minute's count and z-score.
+	Latest int     `json:"latest"`
+	Z      float64 `json:"z"`
+	Firing bool    `json:"firing"`
+	Alerts []Alert `json:"alerts,omitempty"`
+}
+
+// Run polls until ctx is cancelled. Poll errors are logged and retried on the
+// next tick, except authentication errors, which stop the monitor.
+func (m *Monitor) Run(ctx context.Context) error {
+	return ratelimit.Poll(ctx, m.cfg.Interval, m.now, func(ctx context.Context) (client.RateLimitSnapshot, error) {
+		result, rateLimits, err := m.Check(ctx)
+		switch {
+		case ctx.Err() != nil:
+		case client.IsAuth(err):
+			return rateLimits, err
+		case err != nil:
+			m.logger.Warn("counts poll failed", "error", err)
+		default:
+			m.logger.Debug("counts poll", "evaluated", result.Evaluated, "latest", result.Latest, "z", result.Z, "firing", result.Firing)
+		}
+		return rateLimits, nil
+	})
+}
+
+// Check fetches the minutes since the last poll, adds them to the baseline
+// and judges each complete one in order. An alert fires when a minute reaches
+// Threshold and resolves when one falls below Clear. On the first poll the
+// whole baseline window is fetched and only the newest minute is judged, so
+// starting the monitor never reports old spikes.
+func (m *Monitor) Check(ctx context.Context) (CheckResult, client.RateLimitSnapshot, error) {
+	if m.state == nil {
+		state, err := loadState(m.cfg.StatePath, m.cfg.Query)
+		if err != nil {
+			return CheckResult{}, client.RateLimitSnapshot{}, err
+		}
+		m.state = state
+	}
+
+	now := m.now().UTC()
+	oldest := now.Add(-m.cfg.Baseline - m.cfg.Settle).Truncate(time.Minute)
+	start := oldest
+	if next := m.state.Evaluated.Add(time.Minute); next.After(start) {
+		start = next
+	}
+
+	response, rateLimits, err := m.counter.GetRecentCount(ctx, m.cfg.Query, granularity, map[string]string{
+		"start_time": start.Format(time.RFC3339),
+	})
+	if err != nil {
+		return CheckResult{}, rateLimits, err
+	}
+
+	complete := now.Add(-m.cfg.Settle)
+	var pending []recentcount.Data
+	for _, data := range response.Data {
+		data.Start, data.End = data.Start.UTC(), data.End.UTC()
+		if data.End.After(complete) {
+			continue
+		}
+		m.state.Buckets[data.Start] = data.TweetCount
+		if data.Start.After(m.state.Evaluated) {
+			pending = append(pending, data)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Start.Before(pending[j].Start) })
+	if m.state.Evaluated.IsZero() && len(pending) > 1 {
+		pending = pending[len(pending)-1:]
+	}
+
+	result := CheckResult{Firing: m.state.Firing}
+	for _, data := range pending {
+		alert, z, judged := m.judge(data)
+		m.state.Evaluated = data.Start
+		if !judged {
+			continue
+		}
+		result.Evaluated++
+		result.Latest, result.Z = data.TweetCount, z
+		if alert == nil {
+			continue
+		}
+		if err := m.notify(ctx, *alert); err != nil {
+			// Keep the old status so the next minute over the threshold
+			// tries again.
+			m.logger.Warn("alert not delivered", "status", alert.Status, "error", err)
+			continue
+		}
+		m.apply(*alert)
+		result.Alerts = append(result.Alerts, *alert)
+	}
+	result.Firing = m.state.Firing
+
+	m.state.prune(now.Add(-m.cfg.Baseline - m.cfg.Settle - m.cfg.Interval).Truncate(time.Minute))
+	return result, rateLimits, m.save()
+}
+
+// judge compares one minute with the baseline minutes before it and returns
+// the alert its status change calls for, if any. It reports false when the
+// baseline is too short to judge.
+func (m *Monitor) judge(data recentcount.Data) (*Alert, float64, bool) {
+	var baseline []int
+	for minute := data.Start.Add(-m.cfg.Baseline); minute.Before(data.Start); minute = minute.Add(time.Minute) {
+		if count, ok := m.state.Buckets[minute]; ok {
+			baseline = append(baseline, count)
+		}
+	}
+	if len(baseline) < minBaseline {
+		return nil, 0, false
+	}
+
+	mean, stddev, z := countseries.ZScore(baseline, data.TweetCount)
+	if m.state.Firing {
+		m.state.Peak = max(m.state.Peak, data.TweetCount)
+	}
+	var status string
+	switch {
+	case !m.state.Firing && m.cfg.Threshold.reached(data.TweetCount, z):
+		status = StatusFiring
+	case m.state.Firing && !m.cfg.Clear.reached(data.TweetCount, z):
+		status = StatusResolved
+	default:
+		return nil, z, true
+	}
+
+	alert := &Alert{
+		Status:      status,
+		Query:       m.cfg.Query,
+		BucketStart: data.Start,
+		BucketEnd:   data.End,
+		Count:       data.TweetCount,
+		Mean:        mean,
+		StdDev:      stddev,
+		Z:           z,
+		Threshold:   m.cfg.Threshold.String(),
+	}
+	if status == StatusResolved {
+		alert.FiredAt, alert.Peak = m.state.FiredAt, m.state.Peak
+	}
+	return alert, z, true
+}
+
+// apply records a delivered alert's status change.
+func (m *Monitor) apply(alert Alert) {
+	switch alert.Status {
+	case StatusFiring:
+		m.state.Firing, m.state.FiredAt, m.state.Peak = true, alert.BucketStart, alert.Count
+	case StatusResolved:
+		m.state.Firing, m.state.FiredAt, m.state.Peak = false, time.Time{}, 0
+	}
+}
+
+// notify sends alert to every notifier. It fails only when all of them do.
+func (m *Monitor) notify(ctx context.Context, alert Alert) error {
+	m.logger.Info("alert "+alert.Status, "minute", alert.BucketStart, "count", alert.Count, "z", alert.Z, "threshold", alert.Threshold)
+	if m.DryRun || len(m.notifiers) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, notifier := range m.notifiers {
+		notifyCtx, cancel := context.WithTimeout(ctx, m.cfg.NotifyTimeout)
+		err := notifier.Notify(notifyCtx, alert)
+		cancel()
+		if err != nil {
+			m.logger.Warn("notifier failed", "error", err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(m.notifiers) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func (m *Monitor) save() error {
+	if m.DryRun || m.cfg.StatePath == "" {
+		return nil
+	}
+	return m.state.save(m.cfg.StatePath)
+}
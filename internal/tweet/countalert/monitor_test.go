@@ -0,0 +1,192 @@
+package countalert
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	recentcount "github.com/0dayfall/ctw/internal/tweet/recentcount"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCounter serves per-minute counts from a map and honours start_time.
+type fakeCounter struct {
+	counts map[time.Time]int
+	now    *time.Time
+	starts []string
+}
+
+func (f *fakeCounter) GetRecentCount(_ context.Context, _, granularity string, params map[string]string) (recentcount.CountResponse, client.RateLimitSnapshot, error) {
+	f.starts = append(f.starts, params["start_time"])
+	start, err := time.Parse(time.RFC3339, params["start_time"])
+	if err != nil || granularity != "minute" {
+		return recentcount.CountResponse{}, client.RateLimitSnapshot{}, errors.New("bad request")
+	}
+	var response recentcount.CountResponse
+	for minute := start; minute.Before(*f.now); minute = minute.Add(time.Minute) {
+		response.Data = append(response.Data, recentcount.Data{Start: minute, End: minute.Add(time.Minute), TweetCount: f.counts[minute]})
+	}
+	return response, client.RateLimitSnapshot{}, nil
+}
+
+func newTestMonitor(t *testing.T, counts map[time.Time]int, now *time.Time) (*Monitor, *[]Alert) {
+	t.Helper()
+	var alerts []Alert
+	notifier := NotifierFunc(func(_ context.Context, alert Alert) error {
+		alerts = append(alerts, alert)
+		return nil
+	})
+	threshold, err := ParseThreshold("3sigma")
+	require.NoError(t, err)
+	m, err := New(Config{
+		Query:     "brand",
+		StatePath: filepath.Join(t.TempDir(), "alert.json"),
+		Baseline:  30 * time.Minute,
+		Threshold: threshold,
+	}, &fakeCounter{counts: counts, now: now}, []Notifier{notifier}, nil)
+	require.NoError(t, err)
+	m.now = func() time.Time { return *now }
+	return m, &alerts
+}
+
+func TestMonitorFiresOnceAndResolves(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	counts := map[time.Time]int{}
+	for i := -40; i < 60; i++ {
+		counts[base.Add(time.Duration(i)*time.Minute)] = 10 + i%3
+	}
+	now := base.Add(2 * time.Minute)
+	m, alerts := newTestMonitor(t, counts, &now)
+
+	// The first poll only judges the newest complete minute.
+	result, _, err := m.Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Evaluated)
+	require.Empty(t, *alerts)
+
+	// A spike over two minutes alerts once, and the first normal minute
+	// resolves it.
+	counts[base.Add(1*time.Minute)] = 80
+	counts[base.Add(2*time.Minute)] = 90
+	counts[base.Add(3*time.Minute)] = 13
+	counts[base.Add(4*time.Minute)] = 11
+	now = base.Add(6 * time.Minute)
+	result, _, err = m.Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, result.Evaluated)
+	require.Len(t, *alerts, 2)
+	require.Equal(t, StatusFiring, (*alerts)[0].Status)
+	require.Equal(t, 80, (*alerts)[0].Count)
+	require.Equal(t, "3sigma", (*alerts)[0].Threshold)
+	require.Equal(t, StatusResolved, (*alerts)[1].Status)
+	require.Equal(t, 90, (*alerts)[1].Peak)
+	require.Equal(t, base.Add(time.Minute), (*alerts)[1].FiredAt)
+	require.False(t, result.Firing)
+
+	// A restarted monitor resumes from the state file without re-judging.
+	fake := m.counter.(*fakeCounter)
+	restarted, err := New(m.cfg, fake, nil, nil)
+	require.NoError(t, err)
+	restarted.now = m.now
+	result, _, err = restarted.Check(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, result.Evaluated)
+	require.Equal(t, base.Add(5*time.Minute).Format(time.RFC3339), fake.starts[len(fake.starts)-1])
+}
+
+func TestMonitorRetriesUndeliveredAlert(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	counts := map[time.Time]int{}
+	for i := -40; i < 60; i++ {
+		counts[base.Add(time.Duration(i)*time.Minute)] = 10
+	}
+	now := base.Add(2 * time.Minute)
+	m, _ := newTestMonitor(t, counts, &now)
+	_, _, err := m.Check(context.Background())
+	require.NoError(t, err)
+
+	failing := true
+	var delivered []Alert
+	m.notifiers = []Notifier{NotifierFunc(func(_ context.Context, alert Alert) error {
+		if failing {
+			return errors.New("webhook down")
+		}
+		delivered = append(delivered, alert)
+		return nil
+	})}
+
+	counts[base.Add(1*time.Minute)] = 100
+	now = base.Add(3 * time.Minute)
+	result, _, err := m.Check(context.Background())
+	require.NoError(t, err)
+	require.False(t, result.Firing)
+
+	failing = false
+	counts[base.Add(2*time.Minute)] = 100
+	now = base.Add(4 * time.Minute)
+	result, _, err = m.Check(context.Background())
+	require.NoError(t, err)
+	require.True(t, result.Firing)
+	require.Len(t, delivered, 1)
+}
+
+func TestParseThreshold(t *testing.T) {
+	for input, want := range map[string]Threshold{
+		"3sigma":   {Value: 3, Sigma: true},
+		"2.5 σ":    {Value: 2.5, Sigma: true},
+		"500":      {Value: 500},
+		" 4SIGMA ": {Value: 4, Sigma: true},
+	} {
+		got, err := ParseThreshold(input)
+		require.NoError(t, err, input)
+		require.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"", "sigma", "-1sigma", "lots"} {
+		_, err := ParseThreshold(input)
+		require.Error(t, err, input)
+	}
+	require.Equal(t, "1.5sigma", Threshold{Value: 1.5, Sigma: true}.String())
+}
+
+func TestNotifiers(t *testing.T) {
+	alert := Alert{Status: StatusFiring, Query: "brand", Count: 80, Z: 5.5, Threshold: "3sigma"}
+
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+	webhook, err := NewWebhookNotifier(server.URL, server.Client())
+	require.NoError(t, err)
+	require.NoError(t, webhook.Notify(context.Background(), alert))
+	require.Contains(t, body, `"status":"firing"`)
+	require.Contains(t, body, `"text":"[ctw] spike: \"brand\" at 80 tweets/min`)
+
+	command, err := NewCommandNotifier([]string{"sh", "-c", `test "$CTW_ALERT_STATUS/$CTW_ALERT_COUNT" = firing/80`})
+	require.NoError(t, err)
+	require.NoError(t, command.Notify(context.Background(), alert))
+
+	notifier, err := NewEmailNotifier(EmailConfig{Addr: "localhost:25", From: "ctw@example.com", To: []string{"ops@example.com"}})
+	require.NoError(t, err)
+	email := notifier.(*emailNotifier)
+	var sent string
+	email.send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		require.Equal(t, "localhost:25", addr)
+		require.Equal(t, []string{"ops@example.com"}, to)
+		sent = string(msg)
+		return nil
+	}
+	require.NoError(t, email.Notify(context.Background(), alert))
+	require.Contains(t, sent, "Subject: [ctw] spike: \"brand\" at 80 tweets/min (threshold 3sigma)\r\n")
+
+	_, err = NewEmailNotifier(EmailConfig{Addr: "localhost:25", From: "a@example.com\r\nBcc: x@example.com", To: []string{"ops@example.com"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package countalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/utils"
+)
+
+// Alert statuses.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Alert is a change of a monitor's state, sent to every notifier.
+type Alert struct {
+	Status      string    `json:"status"`
+	Query       string    `json:"query"`
+	BucketStart time.Time `json:"bucket_start"`
+	BucketEnd   time.Time `json:"bucket_end"`
+	Count       int       `json:"count"`
+	Mean        float64   `json:"baseline_mean"`
+	StdDev      float64   `json:"baseline_stddev"`
+	Z           float64   `json:"z"`
+	Threshold   string    `json:"threshold"`
+	// FiredAt and Peak describe the episode a resolved alert ends.
+	FiredAt time.Time `json:"fired_at,omitzero"`
+	Peak    int       `json:"peak,omitempty"`
+}
+
+// Subject is a one-line summary, used as the email subject.
+func (a Alert) Subject() string {
+	if a.Status == StatusResolved {
+		return fmt.Sprintf("[ctw] resolved: %q back to %d tweets/min", a.Query, a.Count)
+	}
+	return fmt.Sprintf("[ctw] spike: %q at %d tweets/min (threshold %s)", a.Query, a.Count, a.Threshold)
+}
+
+// Text describes the alert in a few lines.
+func (a Alert) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", a.Subject())
+	fmt.Fprintf(&b, "Query:     %s\n", a.Query)
+	fmt.Fprintf(&b, "Minute:    %s\n", a.BucketStart.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Count:     %d\n", a.Count)
+	fmt.Fprintf(&b, "Baseline:  %.1f ± %.1f (z %.1f)\n", a.Mean, a.StdDev, a.Z)
+	fmt.Fprintf(&b, "Threshold: %s\n", a.Threshold)
+	if a.Status == StatusResolved {
+		fmt.Fprintf(&b, "Fired at:  %s\n", a.FiredAt.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&b, "Peak:      %d\n", a.Peak)
+	}
+	return b.String()
+}
+
+// Notifier delivers alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc adapts a function to Notifier.
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// NewWebhookNotifier POSTs the Alert as JSON to url, with a "text" field
+// holding Text so Slack-compatible incoming webhooks display it.
+func NewWebhookNotifier(url string, httpClient *http.Client) (Notifier, error) {
+	if url == "" {
+		return nil, errors.New("countalert: webhook url is empty")
+	}
+	return NotifierFunc(func(ctx context.Context, alert Alert) error {
+		payload := struct {
+			Alert
+			Text string `json:"text"`
+		}{Alert: alert, Text: alert.Text()}
+		if _, _, err := utils.PostJSON(ctx, httpClient, url, payload); err != nil {
+			return fmt.Errorf("countalert: webhook: %w", err)
+		}
+		return nil
+	}), nil
+}
+
+// NewCommandNotifier runs command for each alert with the Alert as JSON on
+// stdin and CTW_ALERT_STATUS, CTW_ALERT_QUERY, CTW_ALERT_COUNT and
+// CTW_ALERT_Z in its environment.
+func NewCommandNotifier(command []string) (Notifier, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, errors.New("countalert: command is empty")
+	}
+	return NotifierFunc(func(ctx context.Context, alert Alert) error {
+		input, err := json.Marshal(alert)
+		if err != nil {
+			return err
+		}
+
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Env = append(os.Environ(),
+			"CTW_ALERT_STATUS="+alert.Status,
+			"CTW_ALERT_QUERY="+alert.Query,
+			"CTW_ALERT_COUNT="+strconv.Itoa(alert.Count),
+			"CTW_ALERT_Z="+strconv.FormatFloat(alert.Z, 'f', 2, 64),
+		)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("countalert: command: %w: %s", err, msg)
+			}
+			return fmt.Errorf("countalert: command: %w", err)
+		}
+		return nil
+	}), nil
+}
+
+// EmailConfig addresses alert emails.
+type EmailConfig struct {
+	// Addr is the SMTP server, e.g. localhost:25. Mail is sent without
+	// authentication, as to a local relay; STARTTLS is used when offered.
+	Addr string
+	From string
+	To   []string
+}
+
+// emailNotifier sends alerts as plain-text mail.
+type emailNotifier struct {
+	cfg  EmailConfig
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+	now  func() time.Time
+}
+
+// NewEmailNotifier mails Text to cfg.To with Subject as the subject.
+func NewEmailNotifier(cfg EmailConfig) (Notifier, error) {
+	if cfg.Addr == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("countalert: email needs an SMTP address, a sender and at least one recipient")
+	}
+	for _, address := range append([]string{cfg.From}, cfg.To...) {
+		if strings.ContainsAny(address, "\r\n") {
+			return nil, fmt.Errorf("countalert: invalid email address %q", address)
+		}
+	}
+	return &emailNotifier{cfg: cfg, send: smtp.SendMail, now: time.Now}, nil
+}
+
+// Notify sends one message. SMTP has no context support, so ctx only stops
+// a send that has not started.
+func (n *emailNotifier) Notify(ctx context.Context, alert Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.ReplaceAll(alert.Subject(), "\n", " "))
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(alert.Text(), "\n", "\r\n"))
+
+	if err := n.send(n.cfg.Addr, nil, n.cfg.From, n.cfg.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("countalert: email: %w", err)
+	}
+	return nil
+}
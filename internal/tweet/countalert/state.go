@@ -0,0 +1,67 @@
+package countalert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/utils"
+)
+
+// state is the rolling baseline and alert status kept between polls.
+type state struct {
+	Query string `json:"query"`
+	// Buckets holds the per-minute counts of the baseline window, keyed by
+	// bucket start.
+	Buckets map[time.Time]int `json:"buckets"`
+	// Evaluated is the start of the newest bucket already judged.
+	Evaluated time.Time `json:"evaluated,omitzero"`
+	Firing    bool      `json:"firing"`
+	FiredAt   time.Time `json:"fired_at,omitzero"`
+	Peak      int       `json:"peak,omitempty"`
+}
+
+func loadState(path, query string) (*state, error) {
+	s := &state{}
+	data, err := os.ReadFile(path)
+	switch {
+	case path == "" || errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("countalert: read state: %w", err)
+	default:
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("countalert: decode state %s: %w", path, err)
+		}
+	}
+	// A baseline recorded for another query says nothing about this one.
+	if s.Query != query {
+		s = &state{Query: query}
+	}
+	if s.Buckets == nil {
+		s.Buckets = map[time.Time]int{}
+	}
+	return s, nil
+}
+
+// prune drops buckets that started before oldest.
+func (s *state) prune(oldest time.Time) {
+	for start := range s.Buckets {
+		if start.Before(oldest) {
+			delete(s.Buckets, start)
+		}
+	}
+}
+
+// save writes the state atomically.
+func (s *state) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("countalert: encode state: %w", err)
+	}
+	if err := utils.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("countalert: write state: %w", err)
+	}
+	return nil
+}
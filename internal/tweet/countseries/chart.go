@@ -0,0 +1,160 @@
+package countseries
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	sparkLevels = []rune("▁▂▃▄▅▆▇█")
+	// barEighths are the partial blocks for the fraction of a bar cell.
+	barEighths = []rune(" ▏▎▍▌▋▊▉")
+)
+
+// Sparkline renders counts as one block character per bucket, scaled to the
+// largest count.
+func Sparkline(counts []int) string {
+	return sparkline(counts, maxCount(counts))
+}
+
+func sparkline(counts []int, peak int) string {
+	var b strings.Builder
+	for _, count := range counts {
+		level := 0
+		if peak > 0 {
+			level = count * (len(sparkLevels) - 1) / peak
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// WriteSparklines prints one sparkline per series, scaled to the largest
+// count across all of them so the lines compare, followed by the series'
+// total, peak and spikes.
+func (a Analysis) WriteSparklines(w io.Writer) error {
+	peak := 0
+	labelWidth := 0
+	for _, series := range a.Series {
+		peak = max(peak, maxCount(series.Counts))
+		labelWidth = max(labelWidth, utf8.RuneCountInString(series.Query))
+	}
+	if len(a.Buckets) > 0 {
+		if _, err := fmt.Fprintf(w, "%s  %s .. %s\n", strings.Repeat(" ", labelWidth), a.label(0), a.label(len(a.Buckets)-1)); err != nil {
+			return err
+		}
+	}
+
+	for _, series := range a.Series {
+		line := fmt.Sprintf("%s  %s  total %d", padRight(series.Query, labelWidth), sparkline(series.Counts, peak), series.Total)
+		if i := argMax(series.Counts); i >= 0 {
+			line += fmt.Sprintf("  peak %d at %s", series.Counts[i], a.label(i))
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		for _, spike := range series.Spikes {
+			if _, err := fmt.Fprintf(w, "%s  spike %s: %d (baseline %.1f, z %.1f)\n", strings.Repeat(" ", labelWidth), a.label(spike.Index), spike.Count, spike.Mean, spike.Z); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteBars prints a horizontal bar per bucket for every series, at most
+// width cells long and scaled to the largest count across all series.
+// Spikes are marked with an asterisk.
+func (a Analysis) WriteBars(w io.Writer, width int) error {
+	if width < 1 {
+		width = 40
+	}
+	peak := 0
+	for _, series := range a.Series {
+		peak = max(peak, maxCount(series.Counts))
+	}
+	countWidth := len(fmt.Sprint(peak))
+
+	for n, series := range a.Series {
+		if n > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s (total %d)\n", series.Query, series.Total); err != nil {
+			return err
+		}
+		spikes := make(map[int]bool, len(series.Spikes))
+		for _, spike := range series.Spikes {
+			spikes[spike.Index] = true
+		}
+		for i, count := range series.Counts {
+			mark := ""
+			if spikes[i] {
+				mark = " *"
+			}
+			if _, err := fmt.Fprintf(w, "%s %*d %s%s\n", a.label(i), countWidth, count, bar(count, peak, width), mark); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// label formats a bucket start, dropping the time of day when every bucket
+// starts at midnight (day granularity).
+func (a Analysis) label(i int) string {
+	layout := "2006-01-02"
+	for _, bucket := range a.Buckets {
+		if !bucket.Start.Equal(bucket.Start.Truncate(24 * time.Hour)) {
+			layout = "2006-01-02 15:04"
+			break
+		}
+	}
+	return a.Buckets[i].Start.UTC().Format(layout)
+}
+
+func bar(count, peak, width int) string {
+	if peak <= 0 || count <= 0 {
+		return ""
+	}
+	eighths := count * width * 8 / peak
+	if eighths == 0 {
+		eighths = 1
+	}
+	bar := strings.Repeat("█", eighths/8)
+	if rest := eighths % 8; rest > 0 {
+		bar += string(barEighths[rest])
+	}
+	return bar
+}
+
+func maxCount(counts []int) int {
+	peak := 0
+	for _, count := range counts {
+		peak = max(peak, count)
+	}
+	return peak
+}
+
+// argMax returns the index of the first largest count, or -1 when there are
+// no counts.
+func argMax(counts []int) int {
+	best := -1
+	for i, count := range counts {
+		if best < 0 || count > counts[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,174 @@
+// Package countseries analyses tweet count time series: it aligns the counts
+// of several queries on shared buckets and derives totals, moving averages
+// and spikes, and renders them as terminal charts.
+package countseries
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	recentcount "github.com/0dayfall/ctw/internal/tweet/recentcount"
+)
+
+// minBaseline is the fewest earlier buckets a spike is judged against.
+const minBaseline = 3
+
+// Input is the counts returned for one query.
+type Input struct {
+	Query  string
+	Counts []recentcount.Data
+}
+
+// Bucket is one time bucket shared by every series.
+type Bucket struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Series is one query's counts on the aligned buckets.
+type Series struct {
+	Query         string    `json:"query"`
+	Total         int       `json:"total"`
+	Counts        []int     `json:"counts"`
+	MovingAverage []float64 `json:"moving_average,omitempty"`
+	Spikes        []Spike   `json:"spikes,omitempty"`
+}
+
+// Spike is a bucket whose count stands out from the buckets before it.
+type Spike struct {
+	Index  int       `json:"index"`
+	Start  time.Time `json:"start"`
+	Count  int       `json:"count"`
+	Mean   float64   `json:"baseline_mean"`
+	StdDev float64   `json:"baseline_stddev"`
+	Z      float64   `json:"z"`
+}
+
+// Analysis is a set of series aligned on the same buckets.
+type Analysis struct {
+	Buckets []Bucket `json:"buckets"`
+	Series  []Series `json:"series"`
+}
+
+// Align places every input on the union of their buckets, ordered by start.
+// A bucket missing from one query's response counts as zero.
+func Align(inputs []Input) Analysis {
+	index := map[time.Time]Bucket{}
+	for _, input := range inputs {
+		for _, data := range input.Counts {
+			start := data.Start.UTC()
+			if _, ok := index[start]; !ok {
+				index[start] = Bucket{Start: start, End: data.End.UTC()}
+			}
+		}
+	}
+
+	var analysis Analysis
+	for _, bucket := range index {
+		analysis.Buckets = append(analysis.Buckets, bucket)
+	}
+	sort.Slice(analysis.Buckets, func(i, j int) bool { return analysis.Buckets[i].Start.Before(analysis.Buckets[j].Start) })
+
+	position := make(map[time.Time]int, len(analysis.Buckets))
+	for i, bucket := range analysis.Buckets {
+		position[bucket.Start] = i
+	}
+	for _, input := range inputs {
+		series := Series{Query: input.Query, Counts: make([]int, len(analysis.Buckets))}
+		for _, data := range input.Counts {
+			series.Counts[position[data.Start.UTC()]] += data.TweetCount
+		}
+		series.Total = Total(series.Counts)
+		analysis.Series = append(analysis.Series, series)
+	}
+	return analysis
+}
+
+// Detect fills in each series' moving average over average buckets (0 skips
+// it) and the spikes against a baseline of up to baseline earlier buckets.
+func (a *Analysis) Detect(average, baseline int, threshold float64) {
+	for i := range a.Series {
+		series := &a.Series[i]
+		if average > 0 {
+			series.MovingAverage = MovingAverage(series.Counts, average)
+		}
+		series.Spikes = Spikes(series.Counts, baseline, threshold)
+		for j := range series.Spikes {
+			series.Spikes[j].Start = a.Buckets[series.Spikes[j].Index].Start
+		}
+	}
+}
+
+// Total sums counts.
+func Total(counts []int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+// MovingAverage returns the trailing mean of up to window buckets ending at
+// each bucket; the first buckets average over what is available.
+func MovingAverage(counts []int, window int) []float64 {
+	if window < 1 {
+		window = 1
+	}
+	averages := make([]float64, len(counts))
+	sum := 0
+	for i, count := range counts {
+		sum += count
+		if i >= window {
+			sum -= counts[i-window]
+		}
+		averages[i] = float64(sum) / float64(min(i+1, window))
+	}
+	return averages
+}
+
+// Spikes returns the buckets whose z-score against the previous window
+// buckets reaches threshold. Only rises count, and a bucket needs at least
+// three earlier buckets to be judged.
+func Spikes(counts []int, window int, threshold float64) []Spike {
+	if window < minBaseline {
+		window = minBaseline
+	}
+	var spikes []Spike
+	for i := minBaseline; i < len(counts); i++ {
+		mean, stddev, z := ZScore(counts[max(0, i-window):i], counts[i])
+		if z >= threshold {
+			spikes = append(spikes, Spike{Index: i, Count: counts[i], Mean: mean, StdDev: stddev, Z: z})
+		}
+	}
+	return spikes
+}
+
+// ZScore measures count against baseline and returns the baseline's mean and
+// standard deviation and the z-score rounded to two decimals. The deviation
+// the score divides by is floored at the square root of the mean, as for
+// Poisson counts, so a flat or near-zero baseline does not turn every small
+// wobble into a spike.
+func ZScore(baseline []int, count int) (mean, stddev, z float64) {
+	mean, stddev = meanStdDev(baseline)
+	spread := max(stddev, math.Sqrt(mean), 1)
+	z = (float64(count) - mean) / spread
+	return mean, stddev, math.Round(z*100) / 100
+}
+
+func meanStdDev(values []int) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		d := float64(v) - mean
+		variance += d * d
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,75 @@
+package countseries
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	recentcount "github.com/0dayfall/ctw/internal/tweet/recentcount"
+	"github.com/stretchr/testify/require"
+)
+
+func hourly(start time.Time, counts ...int) []recentcount.Data {
+	data := make([]recentcount.Data, len(counts))
+	for i, count := range counts {
+		from := start.Add(time.Duration(i) * time.Hour)
+		data[i] = recentcount.Data{Start: from, End: from.Add(time.Hour), TweetCount: count}
+	}
+	return data
+}
+
+func TestAlignFillsMissingBuckets(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	analysis := Align([]Input{
+		{Query: "golang", Counts: hourly(start, 1, 2, 3)},
+		{Query: "rust", Counts: hourly(start.Add(time.Hour), 5, 6, 7)},
+	})
+
+	require.Len(t, analysis.Buckets, 4)
+	require.Equal(t, start, analysis.Buckets[0].Start)
+	require.Equal(t, []int{1, 2, 3, 0}, analysis.Series[0].Counts)
+	require.Equal(t, []int{0, 5, 6, 7}, analysis.Series[1].Counts)
+	require.Equal(t, 6, analysis.Series[0].Total)
+	require.Equal(t, 18, analysis.Series[1].Total)
+}
+
+func TestMovingAverage(t *testing.T) {
+	require.Equal(t, []float64{2, 3, 4, 6}, MovingAverage([]int{2, 4, 6, 8}, 3))
+	require.Equal(t, []float64{2, 4}, MovingAverage([]int{2, 4}, 0))
+}
+
+func TestSpikes(t *testing.T) {
+	counts := []int{10, 12, 9, 11, 10, 60, 11, 10}
+	spikes := Spikes(counts, 4, 3)
+	require.Len(t, spikes, 1)
+	require.Equal(t, 5, spikes[0].Index)
+	require.Equal(t, 60, spikes[0].Count)
+	require.InDelta(t, 10.5, spikes[0].Mean, 0.001)
+
+	// A flat baseline is judged against Poisson noise, not a zero spread.
+	require.Empty(t, Spikes([]int{100, 100, 100, 110}, 24, 3))
+	require.Len(t, Spikes([]int{100, 100, 100, 140}, 24, 3), 1)
+	// Too little history to judge.
+	require.Empty(t, Spikes([]int{0, 50}, 24, 3))
+}
+
+func TestCharts(t *testing.T) {
+	require.Equal(t, "▁▄█", Sparkline([]int{0, 5, 10}))
+
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	days := []recentcount.Data{
+		{Start: start, End: start.AddDate(0, 0, 1), TweetCount: 2},
+		{Start: start.AddDate(0, 0, 1), End: start.AddDate(0, 0, 2), TweetCount: 8},
+	}
+	analysis := Align([]Input{{Query: "golang", Counts: days}})
+	analysis.Series[0].Spikes = []Spike{{Index: 1, Count: 8}}
+
+	var bars bytes.Buffer
+	require.NoError(t, analysis.WriteBars(&bars, 4))
+	require.Equal(t, "golang (total 10)\n2024-05-01 2 █\n2024-05-02 8 ████ *\n", bars.String())
+
+	var lines bytes.Buffer
+	require.NoError(t, analysis.WriteSparklines(&lines))
+	require.True(t, strings.HasPrefix(lines.String(), "        2024-05-01 .. 2024-05-02\ngolang  ▂█  total 10  peak 8 at 2024-05-02\n"), lines.String())
+}
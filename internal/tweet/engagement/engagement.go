@@ -0,0 +1,196 @@
+// Package engagement summarises who retweeted, liked and quoted a tweet.
+package engagement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/tweet/likes"
+	"github.com/0dayfall/ctw/internal/tweet/retweets"
+)
+
+// DefaultTop is how many accounts a Summary ranks by default.
+const DefaultTop = 10
+
+// Kinds of engagement.
+const (
+	Retweeted = "retweeted"
+	Liked     = "liked"
+	Quoted    = "quoted"
+)
+
+// Service collects the engagement of a tweet.
+type Service struct {
+	retweets *retweets.Service
+	likes    *likes.Service
+}
+
+// NewService constructs a Service backed by the supplied client.
+func NewService(c *client.Client) *Service {
+	if c == nil {
+		panic("engagement: nil client")
+	}
+	return &Service{retweets: retweets.NewService(c), likes: likes.NewService(c)}
+}
+
+// Options tunes Collect.
+type Options struct {
+	// MaxPages stops each list after this many pages; 0 reads every page.
+	MaxPages int
+	// Top is how many accounts to rank; zero means DefaultTop.
+	Top int
+}
+
+// Summary reports a tweet's engagement.
+type Summary struct {
+	TweetID    string `json:"tweet_id"`
+	Retweeters int    `json:"retweeters"`
+	Likers     int    `json:"likers"`
+	Quotes     int    `json:"quotes"`
+	Quoters    int    `json:"quoters"`
+	// Accounts counts the users behind all of the above once each, and
+	// Repeat those who engaged in more than one way.
+	Accounts int `json:"accounts"`
+	Repeat   int `json:"repeat_accounts"`
+	// TopAccounts are the engaged users with the most followers.
+	TopAccounts []Account `json:"top_accounts"`
+}
+
+// Account is a user who engaged with the tweet.
+type Account struct {
+	ID        string   `json:"id"`
+	Username  string   `json:"username,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Followers int      `json:"followers"`
+	Kinds     []string `json:"engagement"`
+}
+
+// Collect pages through the retweeters, liking users and quote tweets of
+// tweetID and summarises them. Follower counts are requested with the users.
+func (s *Service) Collect(ctx context.Context, tweetID string, opts Options) (Summary, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return Summary{}, client.RateLimitSnapshot{}, fmt.Errorf("engagement: nil service")
+	}
+	if strings.TrimSpace(tweetID) == "" {
+		return Summary{}, client.RateLimitSnapshot{}, fmt.Errorf("engagement: tweet id is required")
+	}
+
+	userParams := map[string]string{"user.fields": "public_metrics", "max_results": "100"}
+	retweeters, rateLimits, err := s.retweets.AllRetweeters(ctx, tweetID, userParams, opts.MaxPages)
+	if err != nil {
+		return Summary{}, rateLimits, fmt.Errorf("engagement: retweeters: %w", err)
+	}
+	likers, rateLimits, err := s.likes.AllLikingUsers(ctx, tweetID, userParams, opts.MaxPages)
+	if err != nil {
+		return Summary{}, rateLimits, fmt.Errorf("engagement: liking users: %w", err)
+	}
+	quotes, rateLimits, err := s.retweets.AllQuoteTweets(ctx, tweetID, map[string]string{
+		"expansions":  "author_id",
+		"user.fields": "public_metrics",
+		"max_results": "100",
+	}, opts.MaxPages)
+	if err != nil {
+		return Summary{}, rateLimits, fmt.Errorf("engagement: quote tweets: %w", err)
+	}
+
+	return Summarize(tweetID, retweeters.Data, likers.Data, quotes, opts.Top), rateLimits, nil
+}
+
+// Summarize counts the engagement, merging users who engaged in several ways,
+// and ranks the top accounts by followers, then by how many ways they
+// engaged. Quoters come from the quote tweets' author expansion; authors
+// missing from it are counted by ID.
+func Summarize(tweetID string, retweeters, likers []model.User, quotes model.TweetsResponse, top int) Summary {
+	if top <= 0 {
+		top = DefaultTop
+	}
+	summary := Summary{TweetID: tweetID, Quotes: len(quotes.Data)}
+
+	accounts := make(map[string]*Account)
+	var order []*Account
+	add := func(user model.User, kind string) bool {
+		account := accounts[user.ID]
+		if account == nil {
+			account = &Account{ID: user.ID}
+			accounts[user.ID] = account
+			order = append(order, account)
+		}
+		if account.Username == "" {
+			account.Username, account.Name = user.Username, user.Name
+		}
+		if user.PublicMetrics != nil && user.PublicMetrics.FollowersCount > account.Followers {
+			account.Followers = user.PublicMetrics.FollowersCount
+		}
+		for _, k := range account.Kinds {
+			if k == kind {
+				return false
+			}
+		}
+		account.Kinds = append(account.Kinds, kind)
+		return true
+	}
+
+	for _, user := range retweeters {
+		if add(user, Retweeted) {
+			summary.Retweeters++
+		}
+	}
+	for _, user := range likers {
+		if add(user, Liked) {
+			summary.Likers++
+		}
+	}
+	for _, tweet := range quotes.Data {
+		author, ok := quotes.AuthorOf(tweet)
+		if !ok {
+			author = model.User{ID: tweet.AuthorID}
+		}
+		if author.ID != "" && add(author, Quoted) {
+			summary.Quoters++
+		}
+	}
+
+	summary.Accounts = len(order)
+	for _, account := range order {
+		if len(account.Kinds) > 1 {
+			summary.Repeat++
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Followers != order[j].Followers {
+			return order[i].Followers > order[j].Followers
+		}
+		return len(order[i].Kinds) > len(order[j].Kinds)
+	})
+	summary.TopAccounts = make([]Account, 0, min(top, len(order)))
+	for _, account := range order[:min(top, len(order))] {
+		summary.TopAccounts = append(summary.TopAccounts, *account)
+	}
+	return summary
+}
+
+// Text renders the summary for a terminal.
+func (s Summary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tweet %s\n", s.TweetID)
+	fmt.Fprintf(&b, "  Retweeters: %d\n", s.Retweeters)
+	fmt.Fprintf(&b, "  Likers:     %d\n", s.Likers)
+	fmt.Fprintf(&b, "  Quotes:     %d (from %d accounts)\n", s.Quotes, s.Quoters)
+	fmt.Fprintf(&b, "  Accounts:   %d (%d engaged more than once)\n", s.Accounts, s.Repeat)
+	if len(s.TopAccounts) == 0 {
+		return b.String()
+	}
+	b.WriteString("\nTop accounts by followers:\n")
+	for i, account := range s.TopAccounts {
+		name := "@" + account.Username
+		if account.Username == "" {
+			name = account.ID
+		}
+		fmt.Fprintf(&b, "%3d. %-20s %10d  %s\n", i+1, name, account.Followers, strings.Join(account.Kinds, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package engagement
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func user(id string, followers int) model.User {
+	return model.User{ID: id, Username: "user" + id, PublicMetrics: &model.UserPublicMetrics{FollowersCount: followers}}
+}
+
+func TestSummarize(t *testing.T) {
+	quotes := model.TweetsResponse{
+		Data: []model.Tweet{
+			{ID: "q1", AuthorID: "2"},
+			{ID: "q2", AuthorID: "2"},
+			{ID: "q3", AuthorID: "9"},
+		},
+		Includes: &model.Includes{Users: []model.User{user("2", 50)}},
+	}
+
+	summary := Summarize("t1",
+		[]model.User{user("1", 10), user("2", 50), user("1", 10)},
+		[]model.User{user("2", 50), user("3", 500), user("4", 10)},
+		quotes, 3)
+
+	require.Equal(t, 2, summary.Retweeters)
+	require.Equal(t, 3, summary.Likers)
+	require.Equal(t, 3, summary.Quotes)
+	require.Equal(t, 2, summary.Quoters)
+	require.Equal(t, 5, summary.Accounts)
+	require.Equal(t, 1, summary.Repeat)
+	require.Equal(t, []Account{
+		{ID: "3", Username: "user3", Followers: 500, Kinds: []string{Liked}},
+		{ID: "2", Username: "user2", Followers: 50, Kinds: []string{Retweeted, Liked, Quoted}},
+		{ID: "1", Username: "user1", Followers: 10, Kinds: []string{Retweeted}},
+	}, summary.TopAccounts)
+	require.Contains(t, summary.Text(), "  2. @user2                       50  retweeted, liked, quoted\n")
+}
+
+func TestCollect(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "public_metrics", r.URL.Query().Get("user.fields"))
+		switch r.URL.Path {
+		case "/2/tweets/t1/retweeted_by":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","username":"one","public_metrics":{"followers_count":5}}],"meta":{"result_count":1}}`))
+		case "/2/tweets/t1/liking_users":
+			if r.URL.Query().Get("pagination_token") == "" {
+				_, _ = w.Write([]byte(`{"data":[{"id":"1","username":"one","public_metrics":{"followers_count":5}}],"meta":{"next_token":"p2"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"2","username":"two","public_metrics":{"followers_count":7}}],"meta":{}}`))
+		case "/2/tweets/t1/quote_tweets":
+			require.Equal(t, "author_id", r.URL.Query().Get("expansions"))
+			_, _ = w.Write([]byte(`{"data":[{"id":"q","text":"quoting","author_id":"3"}],"includes":{"users":[{"id":"3","username":"three","public_metrics":{"followers_count":9}}]},"meta":{}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	summary, _, err := service.Collect(context.Background(), "t1", Options{})
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.Retweeters)
+	require.Equal(t, 2, summary.Likers)
+	require.Equal(t, 1, summary.Quoters)
+	require.Equal(t, 3, summary.Accounts)
+	require.Equal(t, "three", summary.TopAccounts[0].Username)
+}
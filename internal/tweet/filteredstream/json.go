@@ -1,6 +1,11 @@
 package tweet
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/model"
+)
 
 type DeleteIdCommand struct {
 	Delete DeleteId `json:"delete"`
@@ -90,34 +95,34 @@ type StreamResponse struct {
 
 // StreamEnvelope captures a response from the filtered stream endpoint.
 type StreamEnvelope struct {
-	Data     []StreamTweet  `json:"data"`
-	Includes StreamIncludes `json:"includes"`
-	Errors   []RulesError   `json:"errors,omitempty"`
-	Meta     StreamMeta     `json:"meta,omitempty"`
-}
-
-// StreamTweet represents a Tweet entry in the filtered stream.
-type StreamTweet struct {
-	ID                string    `json:"id"`
-	Text              string    `json:"text"`
-	AuthorID          string    `json:"author_id"`
-	CreatedAt         time.Time `json:"created_at"`
-	PossiblySensitive bool      `json:"possibly_sensitive"`
-	Source            string    `json:"source"`
-	Lang              string    `json:"lang"`
-}
-
-// StreamIncludes captures expanded entities such as users.
-type StreamIncludes struct {
-	Users []StreamUser `json:"users,omitempty"`
-}
-
-// StreamUser represents a user record included alongside tweets.
-type StreamUser struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Username  string    `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
+	Data          []model.Tweet   `json:"data"`
+	Includes      *model.Includes `json:"includes,omitempty"`
+	MatchingRules []MatchingRule  `json:"matching_rules,omitempty"`
+	Errors        []RulesError    `json:"errors,omitempty"`
+	Meta          StreamMeta      `json:"meta,omitempty"`
+}
+
+// UnmarshalJSON accepts both the single-object data payload sent on the live
+// stream and the array form used by batched responses.
+func (e *StreamEnvelope) UnmarshalJSON(b []byte) error {
+	type envelope StreamEnvelope
+	var raw struct {
+		envelope
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*e = StreamEnvelope(raw.envelope)
+	e.Data = nil
+	return model.DecodeOneOrMany(raw.Data, &e.Data)
+}
+
+// MatchingRule identifies the stream rule that caused a tweet to be delivered.
+type MatchingRule struct {
+	ID  string `json:"id"`
+	Tag string `json:"tag,omitempty"`
 }
 
 // StreamMeta carries additional metadata from the endpoint.
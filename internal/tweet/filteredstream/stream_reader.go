@@ -2,28 +2,33 @@ package tweet
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
-// TweetHandler is a function that processes tweets from the stream.
-// Return an error to stop the stream.
-type TweetHandler func(tweet StreamTweet, includes StreamIncludes) error
+// TweetHandler is a function that processes tweets from the stream along with
+// the rules that matched them. Return an error to stop the stream.
+type TweetHandler func(tweet model.Tweet, includes *model.Includes, rules []MatchingRule) error
 
 // StreamReader connects to the filtered stream and processes tweets in real-time.
-func (s *Service) StreamReader(ctx context.Context, fields map[string]string, handler TweetHandler) error {
+// The returned snapshot holds the connection rate limits reported when the
+// stream was opened.
+func (s *Service) StreamReader(ctx context.Context, fields map[string]string, handler TweetHandler) (client.RateLimitSnapshot, error) {
 	resp, err := s.client.Get(ctx, streamPath, fields)
 	if err != nil {
-		return err
+		return client.RateLimitSnapshot{Limit: -1, Remaining: -1, Reset: -1}, err
 	}
 	defer client.SafeClose(resp.Body)
 
+	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return err
+		return rateLimits, err
 	}
 
 	scanner := bufio.NewScanner(resp.Body)
@@ -32,11 +37,11 @@ func (s *Service) StreamReader(ctx context.Context, fields map[string]string, ha
 	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return rateLimits, ctx.Err()
 		default:
 		}
 
-		line := scanner.Bytes()
+		line := bytes.TrimSpace(scanner.Bytes())
 		if len(line) == 0 {
 			continue // Skip empty lines (keep-alive)
 		}
@@ -44,24 +49,24 @@ func (s *Service) StreamReader(ctx context.Context, fields map[string]string, ha
 		var envelope StreamEnvelope
 		if err := json.Unmarshal(line, &envelope); err != nil {
 			// Log and continue on parse errors
-			fmt.Printf("Error parsing tweet: %v\n", err)
+			s.client.Logger().Warn("filteredstream: skipping malformed event", "error", err, "bytes", len(line))
 			continue
 		}
 
 		// Process each tweet in the response
 		for _, tweet := range envelope.Data {
-			if err := handler(tweet, envelope.Includes); err != nil {
+			if err := handler(tweet, envelope.Includes, envelope.MatchingRules); err != nil {
 				if err == io.EOF {
-					return nil // Clean stop
+					return rateLimits, nil // Clean stop
 				}
-				return fmt.Errorf("tweet handler error: %w", err)
+				return rateLimits, fmt.Errorf("tweet handler error: %w", err)
 			}
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("stream scanner error: %w", err)
+		return rateLimits, fmt.Errorf("stream scanner error: %w", err)
 	}
 
-	return nil
+	return rateLimits, nil
 }
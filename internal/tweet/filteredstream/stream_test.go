@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/0dayfall/ctw/internal/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +54,39 @@ func TestStreamSendsQueryParameters(t *testing.T) {
 	require.Len(t, response.Includes.Users, 1)
 	require.Equal(t, "Twitter Dev", response.Includes.Users[0].Name)
 }
+
+func TestStreamReaderDecodesLiveEvents(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/2/tweets/search/stream", req.URL.Path)
+		res.Header().Set("x-rate-limit-limit", "50")
+		res.Header().Set("x-rate-limit-remaining", "49")
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte("\r\n" +
+			`{"data":{"id":"1","text":"first"},"matching_rules":[{"id":"r1","tag":"golang"}]}` + "\r\n" +
+			"\r\n" +
+			`{"data":{"id":"2","text":"second"},"includes":{"users":[{"id":"9","username":"gopher"}]},"matching_rules":[{"id":"r2","tag":"rust"}]}` + "\r\n"))
+		require.NoError(t, err)
+	})
+
+	var (
+		ids     []string
+		tags    []string
+		authors []string
+	)
+	rateLimits, err := service.StreamReader(context.Background(), nil, func(tweet model.Tweet, includes *model.Includes, rules []MatchingRule) error {
+		ids = append(ids, tweet.ID)
+		if author, ok := includes.User("9"); ok {
+			authors = append(authors, author.Username)
+		}
+		for _, rule := range rules {
+			tags = append(tags, rule.Tag)
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, ids)
+	require.Equal(t, []string{"golang", "rust"}, tags)
+	require.Equal(t, []string{"gopher"}, authors)
+	require.Equal(t, 49, rateLimits.Remaining)
+}
@@ -6,15 +6,18 @@ import (
 	"fmt"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
 const (
 	likePathTemplate    = "/2/users/%s/likes"
 	unlikePathTemplate  = "/2/users/%s/likes/%s"
 	likedTweetsTemplate = "/2/users/%s/liked_tweets"
+	likingUsersTemplate = "/2/tweets/%s/liking_users"
 )
 
-// Service wraps Twitter like endpoints.
+// Service wraps Twitter like endpoints. Methods act for the authenticated user
+// when userID is empty.
 type Service struct {
 	client *client.Client
 }
@@ -32,6 +35,10 @@ func (s *Service) LikeTweet(ctx context.Context, userID, tweetID string) (Relati
 	if s == nil {
 		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: nil service")
 	}
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: %w", err)
+	}
 
 	path := fmt.Sprintf(likePathTemplate, userID)
 	payload := map[string]string{"tweet_id": tweetID}
@@ -59,6 +66,10 @@ func (s *Service) UnlikeTweet(ctx context.Context, userID, tweetID string) (Rela
 	if s == nil {
 		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: nil service")
 	}
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: %w", err)
+	}
 
 	path := fmt.Sprintf(unlikePathTemplate, userID, tweetID)
 	resp, err := s.client.Delete(ctx, path, nil)
@@ -81,27 +92,80 @@ func (s *Service) UnlikeTweet(ctx context.Context, userID, tweetID string) (Rela
 }
 
 // ListLikedTweets retrieves liked tweets for userID applying optional query params (pagination, expansions, etc.).
-func (s *Service) ListLikedTweets(ctx context.Context, userID string, params map[string]string) (LikedTweetsResponse, client.RateLimitSnapshot, error) {
+func (s *Service) ListLikedTweets(ctx context.Context, userID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return LikedTweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: nil service")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: nil service")
+	}
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: %w", err)
 	}
 
 	path := fmt.Sprintf(likedTweetsTemplate, userID)
 	resp, err := s.client.Get(ctx, path, params)
 	if err != nil {
-		return LikedTweetsResponse{}, client.RateLimitSnapshot{}, err
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return LikedTweetsResponse{}, rateLimits, err
+		return model.TweetsResponse{}, rateLimits, err
 	}
 
-	var payload LikedTweetsResponse
+	var payload model.TweetsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return LikedTweetsResponse{}, rateLimits, fmt.Errorf("likes: decode liked tweets response: %w", err)
+		return model.TweetsResponse{}, rateLimits, fmt.Errorf("likes: decode liked tweets response: %w", err)
 	}
 
 	return payload, rateLimits, nil
 }
+
+// ListLikingUsers fetches one page of the users who liked tweetID. Pass the
+// previous page's Meta.NextToken as pagination_token to continue.
+func (s *Service) ListLikingUsers(ctx context.Context, tweetID string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: nil service")
+	}
+	if tweetID == "" {
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("likes: tweet id is required")
+	}
+
+	path := fmt.Sprintf(likingUsersTemplate, tweetID)
+	resp, err := s.client.Get(ctx, path, params)
+	if err != nil {
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, err
+	}
+	defer client.SafeClose(resp.Body)
+
+	rateLimits := client.ParseRateLimits(resp)
+	if err := client.CheckResponse(resp); err != nil {
+		return model.UsersResponse{}, rateLimits, err
+	}
+
+	var payload model.UsersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return model.UsersResponse{}, rateLimits, fmt.Errorf("likes: decode liking users response: %w", err)
+	}
+
+	return payload, rateLimits, nil
+}
+
+// AllLikingUsers follows pagination until every liking user is fetched or
+// maxPages pages were read (0 means no limit).
+func (s *Service) AllLikingUsers(ctx context.Context, tweetID string, params map[string]string, maxPages int) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	var (
+		all        model.UsersResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(params, client.PaginationTokenParam, maxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := s.ListLikingUsers(ctx, tweetID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Append(resp)
+		return resp.Meta.NextToken, nil
+	})
+	return all, rateLimits, err
+}
@@ -67,3 +67,22 @@ func TestListLikedTweets(t *testing.T) {
 	require.Equal(t, "token-abc", resp.Meta.NextToken)
 	require.Equal(t, 75, rateLimits.Limit)
 }
+
+func TestAllLikingUsers(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/2/tweets/tweet-1/liking_users", r.URL.Path)
+		require.Equal(t, "public_metrics", r.URL.Query().Get("user.fields"))
+		if r.URL.Query().Get("pagination_token") == "" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"u1","username":"user1"}],"meta":{"result_count":1,"next_token":"p2"}}`))
+			return
+		}
+		require.Equal(t, "p2", r.URL.Query().Get("pagination_token"))
+		_, _ = w.Write([]byte(`{"data":[{"id":"u2","username":"user2","public_metrics":{"followers_count":10}}],"meta":{"result_count":1}}`))
+	})
+
+	resp, _, err := service.AllLikingUsers(context.Background(), "tweet-1", map[string]string{"user.fields": "public_metrics"}, 0)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	require.Equal(t, 10, resp.Data[1].PublicMetrics.FollowersCount)
+}
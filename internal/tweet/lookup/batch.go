@@ -0,0 +1,125 @@
+package lookup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/ratelimit"
+)
+
+const (
+	// MaxIDsPerRequest is the most IDs GET /2/tweets accepts.
+	MaxIDsPerRequest = 100
+	// DefaultConcurrency is how many batches GetTweets requests at once.
+	DefaultConcurrency = 4
+	// rateLimitAttempts bounds how often one batch is requested again after
+	// the client's own retries gave up on a 429.
+	rateLimitAttempts = 3
+)
+
+// GetTweets fetches tweets by ID with optional query parameters. Duplicate
+// and empty IDs are dropped, and lists longer than MaxIDsPerRequest are split
+// into batches requested concurrently and paced against the rate limit. A
+// batch that is rate limited anyway waits for the window's reset and is
+// retried. The batches' data, includes and errors are merged in request
+// order; the returned snapshot is the most recent one.
+func (s *Service) GetTweets(ctx context.Context, tweetIDs []string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: nil service")
+	}
+	ids := uniqueIDs(tweetIDs)
+	if len(ids) == 0 {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: tweet ids are required")
+	}
+	if len(ids) <= MaxIDsPerRequest {
+		return s.getBatch(ctx, ids, params)
+	}
+
+	var batches [][]string
+	for start := 0; start < len(ids); start += MaxIDsPerRequest {
+		batches = append(batches, ids[start:min(start+MaxIDsPerRequest, len(ids))])
+	}
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	pacer := ratelimit.NewPacer(nil)
+	var pending atomic.Int64
+	pending.Store(int64(len(batches)))
+	responses := make([]model.TweetsResponse, len(batches))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(batches)) {
+		wg.Go(func() {
+			for i := range queue {
+				response, err := s.getPacedBatch(ctx, pacer, &pending, batches[i], params)
+				if err != nil {
+					cancel(fmt.Errorf("lookup: batch %d of %d: %w", i+1, len(batches), err))
+					continue
+				}
+				responses[i] = response
+			}
+		})
+	}
+
+feed:
+	for i := range batches {
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return model.TweetsResponse{}, pacer.Snapshot(), err
+	}
+
+	var merged model.TweetsResponse
+	for _, response := range responses {
+		merged.Append(response)
+	}
+	return merged, pacer.Snapshot(), nil
+}
+
+// getPacedBatch requests one batch when the pacer allows it, retrying up to
+// rateLimitAttempts times when the response is a 429.
+func (s *Service) getPacedBatch(ctx context.Context, pacer *ratelimit.Pacer, pending *atomic.Int64, ids []string, params map[string]string) (model.TweetsResponse, error) {
+	defer pending.Add(-1)
+	for attempt := 1; ; attempt++ {
+		if err := pacer.Wait(ctx); err != nil {
+			return model.TweetsResponse{}, err
+		}
+		response, limits, err := s.getBatch(ctx, ids, params)
+		pacer.Observe(limits, int(pending.Load()))
+		if err == nil || !client.IsRateLimited(err) || attempt == rateLimitAttempts {
+			return response, err
+		}
+	}
+}
+
+// uniqueIDs trims ids and drops empty and repeated ones, keeping the order.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
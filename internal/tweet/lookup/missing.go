@@ -0,0 +1,121 @@
+package lookup
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/model"
+)
+
+// Reasons a requested tweet is missing from a lookup response.
+const (
+	ReasonNotFound    = "not_found"
+	ReasonProtected   = "protected"
+	ReasonUnavailable = "unavailable"
+	// ReasonMissing marks an ID the response neither returned nor explained.
+	ReasonMissing = "missing"
+)
+
+// Missing describes a requested tweet the lookup did not return.
+type Missing struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// MissingTweets reports the ids that resp holds no tweet for, in request
+// order. Deleted tweets come back as not_found, tweets of protected accounts
+// as protected and suspended ones as unavailable; other problem types are
+// reported by the last segment of their type URL.
+func MissingTweets(ids []string, resp model.TweetsResponse) []Missing {
+	found := make(map[string]bool, len(resp.Data))
+	for _, tweet := range resp.Data {
+		found[tweet.ID] = true
+	}
+	problems := make(map[string]Missing)
+	for _, e := range resp.Errors {
+		id := e.ResourceID
+		if id == "" {
+			id = e.Value
+		}
+		if id == "" || e.ResourceType != "" && e.ResourceType != "tweet" {
+			continue
+		}
+		if _, ok := problems[id]; !ok {
+			problems[id] = Missing{ID: id, Reason: problemReason(e.Type), Detail: e.Detail}
+		}
+	}
+
+	var missing []Missing
+	for _, id := range uniqueIDs(ids) {
+		if found[id] {
+			continue
+		}
+		problem, ok := problems[id]
+		if !ok {
+			problem = Missing{ID: id, Reason: ReasonMissing}
+		}
+		missing = append(missing, problem)
+	}
+	return missing
+}
+
+func problemReason(problemType string) string {
+	switch name := path.Base(problemType); name {
+	case "resource-not-found":
+		return ReasonNotFound
+	case "not-authorized-for-resource":
+		return ReasonProtected
+	case "resource-unavailable":
+		return ReasonUnavailable
+	case "", ".", "/":
+		return ReasonMissing
+	default:
+		return name
+	}
+}
+
+// ParseTweetID returns the tweet ID of s, which is either a numeric ID or a
+// tweet URL such as https://x.com/user/status/123 or
+// https://twitter.com/i/web/status/123?s=20.
+func ParseTweetID(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if isNumeric(s) {
+		return s, nil
+	}
+	raw := s
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !tweetHost(u.Hostname()) {
+		return "", fmt.Errorf("lookup: %q is not a tweet ID or URL", s)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments[:max(len(segments)-1, 0)] {
+		if (segment == "status" || segment == "statuses") && isNumeric(segments[i+1]) {
+			return segments[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("lookup: %q is not a tweet URL", s)
+}
+
+func tweetHost(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	host = strings.TrimPrefix(host, "mobile.")
+	return host == "x.com" || host == "twitter.com"
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
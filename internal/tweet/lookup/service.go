@@ -1,3 +1,4 @@
+// Package lookup provides helpers for fetching tweets by ID.
 package lookup
 
 import (
@@ -7,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
 const (
@@ -17,6 +19,10 @@ const (
 // Service coordinates Twitter tweet lookup operations.
 type Service struct {
 	client *client.Client
+
+	// Concurrency is how many batches GetTweets requests at once; zero
+	// means DefaultConcurrency.
+	Concurrency int
 }
 
 // NewService constructs a Service backed by the supplied client.
@@ -28,44 +34,37 @@ func NewService(c *client.Client) *Service {
 }
 
 // GetTweet fetches a single tweet by ID with optional query parameters.
-func (s *Service) GetTweet(ctx context.Context, tweetID string, params map[string]string) (TweetLookupResponse, client.RateLimitSnapshot, error) {
+func (s *Service) GetTweet(ctx context.Context, tweetID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return TweetLookupResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: nil service")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: nil service")
 	}
 	if strings.TrimSpace(tweetID) == "" {
-		return TweetLookupResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: tweet id is required")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: tweet id is required")
 	}
 
 	path := fmt.Sprintf(tweetLookupPath, tweetID)
 
 	resp, err := s.client.Get(ctx, path, params)
 	if err != nil {
-		return TweetLookupResponse{}, client.RateLimitSnapshot{}, err
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return TweetLookupResponse{}, rateLimits, err
+		return model.TweetsResponse{}, rateLimits, err
 	}
 
-	var result TweetLookupResponse
+	var result model.TweetsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return TweetLookupResponse{}, rateLimits, fmt.Errorf("lookup: decode response: %w", err)
+		return model.TweetsResponse{}, rateLimits, fmt.Errorf("lookup: decode response: %w", err)
 	}
 
 	return result, rateLimits, nil
 }
 
-// GetTweets fetches multiple tweets by IDs with optional query parameters.
-func (s *Service) GetTweets(ctx context.Context, tweetIDs []string, params map[string]string) (TweetLookupResponse, client.RateLimitSnapshot, error) {
-	if s == nil {
-		return TweetLookupResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: nil service")
-	}
-	if len(tweetIDs) == 0 {
-		return TweetLookupResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("lookup: tweet ids are required")
-	}
-
+// getBatch fetches up to MaxIDsPerRequest tweets in one request.
+func (s *Service) getBatch(ctx context.Context, tweetIDs []string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	queryParams := make(map[string]string)
 	for k, v := range params {
 		queryParams[k] = v
@@ -74,18 +73,18 @@ func (s *Service) GetTweets(ctx context.Context, tweetIDs []string, params map[s
 
 	resp, err := s.client.Get(ctx, tweetsLookupPath, queryParams)
 	if err != nil {
-		return TweetLookupResponse{}, client.RateLimitSnapshot{}, err
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return TweetLookupResponse{}, rateLimits, err
+		return model.TweetsResponse{}, rateLimits, err
 	}
 
-	var result TweetLookupResponse
+	var result model.TweetsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return TweetLookupResponse{}, rateLimits, fmt.Errorf("lookup: decode response: %w", err)
+		return model.TweetsResponse{}, rateLimits, fmt.Errorf("lookup: decode response: %w", err)
 	}
 
 	return result, rateLimits, nil
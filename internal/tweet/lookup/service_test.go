@@ -2,9 +2,17 @@ package lookup
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/0dayfall/ctw/internal/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,6 +36,19 @@ func TestGetTweet(t *testing.T) {
 	require.Equal(t, 300, rateLimits.Limit)
 }
 
+func TestGetTweetDecodesSingleObject(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":"tweet-1","text":"hello","author_id":"9"},"includes":{"users":[{"id":"9","username":"gopher"}]}}`))
+	})
+
+	resp, _, err := service.GetTweet(context.Background(), "tweet-1", nil)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	author, ok := resp.AuthorOf(resp.Data[0])
+	require.True(t, ok)
+	require.Equal(t, "gopher", author.Username)
+}
+
 func TestGetTweets(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		require.Equal(t, http.MethodGet, r.Method)
@@ -48,3 +69,147 @@ func TestGetTweets(t *testing.T) {
 	require.Equal(t, "tweet-2", resp.Data[1].ID)
 	require.Equal(t, 300, rateLimits.Limit)
 }
+
+func TestGetTweetsBatchesLongLists(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches []int
+	)
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		require.Equal(t, "author_id", r.URL.Query().Get("expansions"))
+		mu.Lock()
+		batches = append(batches, len(ids))
+		mu.Unlock()
+
+		resp := model.TweetsResponse{Includes: &model.Includes{}}
+		authors := map[string]bool{}
+		for _, id := range ids {
+			if id == "150" {
+				resp.Errors = append(resp.Errors, model.Error{
+					Value: id, ResourceID: id, ResourceType: "tweet", Detail: "Could not find tweet with ids: [150].",
+					Type: "https://api.twitter.com/2/problems/resource-not-found",
+				})
+				continue
+			}
+			author := "u" + id[:1]
+			resp.Data = append(resp.Data, model.Tweet{ID: id, AuthorID: author})
+			if !authors[author] {
+				authors[author] = true
+				resp.Includes.Users = append(resp.Includes.Users, model.User{ID: author})
+			}
+		}
+		w.Header().Set("x-rate-limit-limit", "300")
+		w.Header().Set("x-rate-limit-remaining", "290")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+	service.Concurrency = 2
+
+	var ids []string
+	for i := 1; i <= 250; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	ids = append(ids, "7", " ")
+
+	resp, rateLimits, err := service.GetTweets(context.Background(), ids, map[string]string{"expansions": "author_id"})
+	require.NoError(t, err)
+	sort.Ints(batches)
+	require.Equal(t, []int{50, 100, 100}, batches)
+	require.Len(t, resp.Data, 249)
+	require.Equal(t, "1", resp.Data[0].ID)
+	require.Equal(t, "250", resp.Data[248].ID)
+	require.Len(t, resp.Includes.Users, 9)
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, 300, rateLimits.Limit)
+
+	require.Equal(t, []Missing{{ID: "150", Reason: ReasonNotFound, Detail: "Could not find tweet with ids: [150]."}}, MissingTweets(ids, resp))
+}
+
+func TestGetTweetsStopsOnBatchError(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Query().Get("ids"), "101,") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"title":"Invalid Request"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	})
+
+	var ids []string
+	for i := 1; i <= 150; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	_, _, err := service.GetTweets(context.Background(), ids, nil)
+	require.ErrorContains(t, err, "lookup: batch 2 of 2:")
+}
+
+func TestMissingTweets(t *testing.T) {
+	resp := model.TweetsResponse{
+		Data: []model.Tweet{{ID: "1"}},
+		Errors: []model.Error{
+			{ResourceID: "2", ResourceType: "tweet", Type: "https://api.twitter.com/2/problems/not-authorized-for-resource", Detail: "Sorry, you are not authorized to see the Tweet with id: [2]."},
+			{ResourceID: "3", ResourceType: "tweet", Type: "https://api.twitter.com/2/problems/resource-unavailable"},
+			{ResourceID: "9", ResourceType: "user", Type: "https://api.twitter.com/2/problems/resource-not-found"},
+			{Value: "5", Type: "https://api.twitter.com/2/problems/something-new"},
+		},
+	}
+
+	require.Equal(t, []Missing{
+		{ID: "2", Reason: ReasonProtected, Detail: "Sorry, you are not authorized to see the Tweet with id: [2]."},
+		{ID: "3", Reason: ReasonUnavailable},
+		{ID: "4", Reason: ReasonMissing},
+		{ID: "5", Reason: "something-new"},
+	}, MissingTweets([]string{"1", "2", "3", "4", "5"}, resp))
+	require.Empty(t, MissingTweets([]string{"1"}, resp))
+}
+
+func TestParseTweetID(t *testing.T) {
+	for input, want := range map[string]string{
+		"1234567890":                                           "1234567890",
+		" https://x.com/gopher/status/123 ":                    "123",
+		"https://twitter.com/gopher/status/123?s=20&t=abc":     "123",
+		"https://mobile.twitter.com/gopher/status/123/photo/1": "123",
+		"https://www.x.com/i/web/status/123":                   "123",
+		"x.com/gopher/statuses/123":                            "123",
+	} {
+		id, err := ParseTweetID(input)
+		require.NoError(t, err, input)
+		require.Equal(t, want, id, input)
+	}
+
+	for _, input := range []string{"", "abc", "https://example.com/gopher/status/123", "https://x.com/gopher", "https://x.com/gopher/status/abc"} {
+		_, err := ParseTweetID(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestGetTweetsRetriesRateLimitedBatch(t *testing.T) {
+	var limited atomic.Bool
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		if ids[0] == "101" && limited.CompareAndSwap(false, true) {
+			w.Header().Set("x-rate-limit-limit", "300")
+			w.Header().Set("x-rate-limit-remaining", "0")
+			w.Header().Set("x-rate-limit-reset", strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"title":"Too Many Requests"}`))
+			return
+		}
+		resp := model.TweetsResponse{}
+		for _, id := range ids {
+			resp.Data = append(resp.Data, model.Tweet{ID: id})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+	service.Concurrency = 1
+
+	var ids []string
+	for i := 1; i <= 150; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	resp, _, err := service.GetTweets(context.Background(), ids, nil)
+	require.NoError(t, err)
+	require.True(t, limited.Load())
+	require.Len(t, resp.Data, 150)
+	require.Equal(t, "150", resp.Data[149].ID)
+}
@@ -0,0 +1,292 @@
+package mentionbot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/ratelimit"
+	"github.com/0dayfall/ctw/internal/tweet/publish"
+)
+
+const (
+	// DefaultInterval is the time between polls.
+	DefaultInterval = time.Minute
+	// DefaultMaxPerConversation caps replies into one conversation, which
+	// stops two bots from replying to each other forever.
+	DefaultMaxPerConversation = 3
+	// DefaultHandlerTimeout bounds one command or webhook call.
+	DefaultHandlerTimeout = 30 * time.Second
+
+	// maxPages bounds how far back one poll pages through new mentions.
+	maxPages = 5
+
+	mentionFields = "author_id,conversation_id,created_at,in_reply_to_user_id,referenced_tweets"
+)
+
+// MentionsLister is the part of timelines.Service the bot reads from.
+type MentionsLister interface {
+	GetUserMentions(ctx context.Context, userID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error)
+}
+
+// Publisher is the part of publish.Service the bot replies with.
+type Publisher interface {
+	CreateTweet(ctx context.Context, req publish.CreateTweetRequest) (publish.CreateTweetResponse, client.RateLimitSnapshot, error)
+}
+
+// Config tunes a Bot. Zero values select the defaults.
+type Config struct {
+	// StatePath is the file holding since_id and reply bookkeeping.
+	StatePath string
+	// Interval is the time between polls; it is stretched when rate-limit
+	// headers ask for slower polling.
+	Interval time.Duration
+	// Cooldown is the minimum time between replies to the same author.
+	Cooldown time.Duration
+	// MaxPerConversation caps the replies sent into one conversation.
+	MaxPerConversation int
+	// HandlerTimeout bounds one handler call.
+	HandlerTimeout time.Duration
+}
+
+// Bot replies to mentions of selfID.
+type Bot struct {
+	cfg       Config
+	mentions  MentionsLister
+	publisher Publisher
+	handler   Handler
+	selfID    string
+	logger    *slog.Logger
+
+	// DryRun logs replies instead of posting them and never writes the
+	// state file, so a later real run still answers the same mentions.
+	DryRun bool
+
+	now   func() time.Time
+	state *state
+}
+
+// New constructs a Bot. It panics when a dependency is missing, like the
+// service constructors.
+func New(cfg Config, mentions MentionsLister, publisher Publisher, handler Handler, selfID string, logger *slog.Logger) *Bot {
+	if mentions == nil || publisher == nil || handler == nil {
+		panic("mentionbot: nil dependency")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.MaxPerConversation <= 0 {
+		cfg.MaxPerConversation = DefaultMaxPerConversation
+	}
+	if cfg.HandlerTimeout <= 0 {
+		cfg.HandlerTimeout = DefaultHandlerTimeout
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &Bot{
+		cfg:       cfg,
+		mentions:  mentions,
+		publisher: publisher,
+		handler:   handler,
+		selfID:    selfID,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// PollResult summarises one poll.
+type PollResult struct {
+	Mentions int `json:"mentions"`
+	Replies  int `json:"replies"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+// Run polls until ctx is cancelled. Poll errors are logged and retried on the
+// next tick, except authentication errors, which stop the bot.
+func (b *Bot) Run(ctx context.Context) error {
+	return ratelimit.Poll(ctx, b.cfg.Interval, b.now, func(ctx context.Context) (client.RateLimitSnapshot, error) {
+		result, rateLimits, err := b.Poll(ctx)
+		switch {
+		case ctx.Err() != nil:
+		case client.IsAuth(err):
+			return rateLimits, err
+		case err != nil:
+			b.logger.Warn("mentions poll failed", "error", err)
+		case result.Mentions > 0:
+			b.logger.Info("mentions poll", "mentions", result.Mentions, "replies", result.Replies, "skipped", result.Skipped, "failed", result.Failed)
+		}
+		return rateLimits, nil
+	})
+}
+
+// Poll fetches mentions newer than the stored since_id and answers them
+// oldest first. The first poll only records the newest mention, so starting
+// the bot never replies to old ones.
+func (b *Bot) Poll(ctx context.Context) (PollResult, client.RateLimitSnapshot, error) {
+	if b.state == nil {
+		state, err := loadState(b.cfg.StatePath)
+		if err != nil {
+			return PollResult{}, client.RateLimitSnapshot{}, err
+		}
+		b.state = state
+	}
+
+	response, rateLimits, err := b.fetch(ctx)
+	if err != nil {
+		return PollResult{}, rateLimits, err
+	}
+
+	mentions := response.Data
+	sort.Slice(mentions, func(i, j int) bool { return model.NewerID(mentions[j].ID, mentions[i].ID) })
+
+	var result PollResult
+	if b.state.empty() {
+		if len(mentions) > 0 {
+			b.state.SinceID = mentions[len(mentions)-1].ID
+			b.logger.Info("recorded existing mentions without replying", "since_id", b.state.SinceID)
+		}
+		return result, rateLimits, b.save()
+	}
+
+	for _, tweet := range mentions {
+		mention := Mention{Tweet: tweet}
+		if author, ok := response.AuthorOf(tweet); ok {
+			mention.Author = &author
+		}
+
+		result.Mentions++
+		replied, err := b.handle(ctx, mention)
+		switch {
+		case client.IsAuth(err) || client.IsRateLimited(err) || ctx.Err() != nil:
+			// Leave this mention for the next poll.
+			if saveErr := b.save(); saveErr != nil {
+				b.logger.Warn("mention bot state not saved", "error", saveErr)
+			}
+			return result, rateLimits, errors.Join(err, ctx.Err())
+		case err != nil:
+			result.Failed++
+			b.logger.Warn("mention not answered", "tweet_id", tweet.ID, "error", err)
+		case replied:
+			result.Replies++
+		default:
+			result.Skipped++
+		}
+		b.state.SinceID = tweet.ID
+	}
+
+	return result, rateLimits, b.save()
+}
+
+// fetch pages through mentions newer than since_id.
+func (b *Bot) fetch(ctx context.Context) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	params := map[string]string{
+		"tweet.fields": mentionFields,
+		"expansions":   "author_id",
+		"user.fields":  "username,name",
+		"max_results":  "100",
+	}
+	if b.state.SinceID != "" {
+		params["since_id"] = b.state.SinceID
+	}
+
+	var (
+		all        model.TweetsResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(params, client.PaginationTokenParam, maxPages, func(page map[string]string) (string, error) {
+		response, limits, err := b.mentions.GetUserMentions(ctx, b.selfID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Append(response)
+		// Before the first poll only the newest page matters.
+		if b.state.empty() {
+			return "", nil
+		}
+		return response.Meta.NextToken, nil
+	})
+	if err != nil {
+		return model.TweetsResponse{}, rateLimits, err
+	}
+	return all, rateLimits, nil
+}
+
+// handle answers one mention unless a loop or duplicate guard skips it, and
+// reports whether a reply was sent.
+func (b *Bot) handle(ctx context.Context, mention Mention) (bool, error) {
+	tweet := mention.Tweet
+	logger := b.logger.With("tweet_id", tweet.ID, "author_id", tweet.AuthorID)
+	now := b.now()
+
+	if _, ok := b.state.Replied[tweet.ID]; ok {
+		logger.Debug("already replied")
+		return false, nil
+	}
+	if tweet.AuthorID == b.selfID {
+		logger.Debug("own tweet")
+		return false, nil
+	}
+	if _, ok := tweet.Reference("retweeted"); ok {
+		logger.Debug("retweet")
+		return false, nil
+	}
+	if last, ok := b.state.LastReply[tweet.AuthorID]; ok && now.Sub(last) < b.cfg.Cooldown {
+		logger.Debug("author in cooldown")
+		return false, nil
+	}
+	conversation := b.state.Conversations[tweet.ConversationID]
+	if tweet.ConversationID != "" && conversation.Replies >= b.cfg.MaxPerConversation {
+		logger.Info("conversation reply limit reached", "conversation_id", tweet.ConversationID)
+		return false, nil
+	}
+
+	handlerCtx, cancel := context.WithTimeout(ctx, b.cfg.HandlerTimeout)
+	text, err := b.handler.Reply(handlerCtx, mention)
+	cancel()
+	if err != nil || text == "" {
+		return false, err
+	}
+
+	replyID := ""
+	if b.DryRun {
+		logger.Info("dry run: would reply", "text", text)
+	} else {
+		response, _, err := b.publisher.CreateTweet(ctx, publish.CreateTweetRequest{
+			Text:  text,
+			Reply: &publish.Reply{InReplyToTweetID: tweet.ID},
+		})
+		if err != nil {
+			return false, err
+		}
+		replyID = response.Data.ID
+		logger.Info("replied", "reply_id", replyID)
+	}
+
+	b.state.Replied[tweet.ID] = reply{ReplyID: replyID, At: now.UTC()}
+	b.state.LastReply[tweet.AuthorID] = now.UTC()
+	if tweet.ConversationID != "" {
+		b.state.Conversations[tweet.ConversationID] = thread{Replies: conversation.Replies + 1, Last: now.UTC()}
+	}
+	// Persist each reply at once so a crash cannot cause a duplicate.
+	if err := b.save(); err != nil {
+		logger.Warn("mention bot state not saved", "error", err)
+	}
+	return true, nil
+}
+
+func (b *Bot) save() error {
+	if b.state.Started.IsZero() {
+		b.state.Started = b.now().UTC()
+	}
+	if b.DryRun || b.cfg.StatePath == "" {
+		return nil
+	}
+	return b.state.save(b.cfg.StatePath, b.now())
+}
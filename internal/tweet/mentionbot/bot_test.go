@@ -0,0 +1,110 @@
+package mentionbot
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/tweet/publish"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPI struct {
+	mentions []model.Tweet
+	users    []model.User
+	replies  []publish.CreateTweetRequest
+	params   []map[string]string
+}
+
+func (f *fakeAPI) GetUserMentions(ctx context.Context, userID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	f.params = append(f.params, params)
+	var data []model.Tweet
+	for _, tweet := range f.mentions {
+		if since := params["since_id"]; since == "" || model.NewerID(tweet.ID, since) {
+			data = append(data, tweet)
+		}
+	}
+	// The API lists newest first.
+	sort.Slice(data, func(i, j int) bool { return model.NewerID(data[i].ID, data[j].ID) })
+	return model.TweetsResponse{Data: data, Includes: &model.Includes{Users: f.users}}, client.RateLimitSnapshot{Limit: -1, Remaining: -1, Reset: -1}, nil
+}
+
+func (f *fakeAPI) CreateTweet(ctx context.Context, req publish.CreateTweetRequest) (publish.CreateTweetResponse, client.RateLimitSnapshot, error) {
+	f.replies = append(f.replies, req)
+	var response publish.CreateTweetResponse
+	response.Data.ID = "r" + req.Reply.InReplyToTweetID
+	return response, client.RateLimitSnapshot{}, nil
+}
+
+func (f *fakeAPI) mention(id, author, conversation string) {
+	f.mentions = append(f.mentions, model.Tweet{ID: id, AuthorID: author, ConversationID: conversation, Text: "@bot hi"})
+}
+
+func TestPollRepliesOnceWithLoopGuards(t *testing.T) {
+	api := &fakeAPI{users: []model.User{{ID: "100", Username: "alice"}, {ID: "300", Username: "carol"}, {ID: "400", Username: "dave"}}}
+	api.mention("10", "100", "10")
+
+	handler, err := NewTemplateHandler("Thanks @{{.Author.Username}}!")
+	require.NoError(t, err)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	statePath := filepath.Join(t.TempDir(), "mentions.json")
+	b := New(Config{StatePath: statePath, Cooldown: time.Minute, MaxPerConversation: 2}, api, api, handler, "7", nil)
+	b.now = func() time.Time { return now }
+
+	result, _, err := b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PollResult{}, result, "existing mentions are backlog")
+	require.Empty(t, api.replies)
+
+	api.mention("11", "100", "11")
+	api.mention("12", "7", "11")
+	api.mentions = append(api.mentions, model.Tweet{ID: "13", AuthorID: "200", ReferencedTweets: []model.ReferencedTweet{{Type: "retweeted", ID: "11"}}})
+	result, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PollResult{Mentions: 3, Replies: 1, Skipped: 2}, result)
+	require.Len(t, api.replies, 1)
+	require.Equal(t, "Thanks @alice!", api.replies[0].Text)
+	require.Equal(t, "11", api.replies[0].Reply.InReplyToTweetID)
+	require.Equal(t, "10", api.params[len(api.params)-1]["since_id"])
+
+	// A restarted bot resumes from the saved since_id and keeps cooldowns.
+	b = New(Config{StatePath: statePath, Cooldown: time.Minute, MaxPerConversation: 2}, api, api, handler, "7", nil)
+	b.now = func() time.Time { return now.Add(30 * time.Second) }
+	api.mention("14", "100", "11")
+	result, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "13", api.params[len(api.params)-1]["since_id"])
+	require.Equal(t, PollResult{Mentions: 1, Skipped: 1}, result, "author is in cooldown")
+
+	b.now = func() time.Time { return now.Add(2 * time.Minute) }
+	api.mention("15", "300", "11")
+	api.mention("16", "400", "11")
+	result, _, err = b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, PollResult{Mentions: 2, Replies: 1, Skipped: 1}, result, "conversation reply limit")
+	require.Len(t, api.replies, 2)
+}
+
+func TestPollDryRunKeepsStateUnwritten(t *testing.T) {
+	api := &fakeAPI{}
+	statePath := filepath.Join(t.TempDir(), "mentions.json")
+	handler := HandlerFunc(func(ctx context.Context, mention Mention) (string, error) {
+		return "hello " + mention.Tweet.ID, nil
+	})
+	b := New(Config{StatePath: statePath}, api, api, handler, "7", nil)
+	b.DryRun = true
+
+	_, _, err := b.Poll(context.Background())
+	require.NoError(t, err)
+	api.mention("20", "100", "20")
+	result, _, err := b.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Replies)
+	require.Empty(t, api.replies)
+	require.NoFileExists(t, statePath)
+}
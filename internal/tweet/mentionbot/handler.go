@@ -0,0 +1,116 @@
+// Package mentionbot replies to tweets that mention the authenticated user.
+package mentionbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/utils"
+)
+
+// Mention is a tweet mentioning the bot, with its author when expanded. It is
+// the data a template sees and the JSON a command or webhook receives.
+type Mention struct {
+	Tweet  model.Tweet `json:"tweet"`
+	Author *model.User `json:"author,omitempty"`
+}
+
+// Handler decides the reply to a mention. An empty reply sends nothing.
+type Handler interface {
+	Reply(ctx context.Context, mention Mention) (string, error)
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, mention Mention) (string, error)
+
+// Reply calls f.
+func (f HandlerFunc) Reply(ctx context.Context, mention Mention) (string, error) {
+	return f(ctx, mention)
+}
+
+// NewTemplateHandler replies with a text/template rendered against the
+// Mention, e.g. "Thanks @{{.Author.Username}}!".
+func NewTemplateHandler(text string) (Handler, error) {
+	tmpl, err := template.New("reply").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("mentionbot: parse template: %w", err)
+	}
+	return HandlerFunc(func(ctx context.Context, mention Mention) (string, error) {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, mention); err != nil {
+			return "", fmt.Errorf("mentionbot: render template: %w", err)
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}), nil
+}
+
+// NewCommandHandler runs command for each mention with the Mention as JSON on
+// stdin and CTW_TWEET_ID, CTW_AUTHOR_ID and CTW_AUTHOR_USERNAME in its
+// environment. Its standard output is the reply.
+func NewCommandHandler(command []string) (Handler, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, errors.New("mentionbot: command is empty")
+	}
+	return HandlerFunc(func(ctx context.Context, mention Mention) (string, error) {
+		input, err := json.Marshal(mention)
+		if err != nil {
+			return "", err
+		}
+
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Env = append(os.Environ(),
+			"CTW_TWEET_ID="+mention.Tweet.ID,
+			"CTW_AUTHOR_ID="+mention.Tweet.AuthorID,
+		)
+		if mention.Author != nil {
+			cmd.Env = append(cmd.Env, "CTW_AUTHOR_USERNAME="+mention.Author.Username)
+		}
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("mentionbot: command: %w: %s", err, msg)
+			}
+			return "", fmt.Errorf("mentionbot: command: %w", err)
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	}), nil
+}
+
+// NewWebhookHandler POSTs the Mention as JSON to url. The reply is the
+// response body, or its "text" field when the response is JSON. A 204 or
+// empty body means no reply.
+func NewWebhookHandler(url string, httpClient *http.Client) (Handler, error) {
+	if url == "" {
+		return nil, errors.New("mentionbot: webhook url is empty")
+	}
+	return HandlerFunc(func(ctx context.Context, mention Mention) (string, error) {
+		data, contentType, err := utils.PostJSON(ctx, httpClient, url, mention)
+		if err != nil {
+			return "", fmt.Errorf("mentionbot: webhook: %w", err)
+		}
+
+		if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/json" && len(bytes.TrimSpace(data)) > 0 {
+			var payload struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(data, &payload); err != nil {
+				return "", fmt.Errorf("mentionbot: webhook: decode response: %w", err)
+			}
+			return strings.TrimSpace(payload.Text), nil
+		}
+		return strings.TrimSpace(string(data)), nil
+	}), nil
+}
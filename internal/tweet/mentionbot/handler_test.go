@@ -0,0 +1,61 @@
+package mentionbot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var mention Mention
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&mention))
+		switch mention.Tweet.ID {
+		case "1":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"text":"hi @` + mention.Author.Username + `"}`))
+		case "2":
+			_, _ = w.Write([]byte("plain reply\n"))
+		case "3":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	handler, err := NewWebhookHandler(server.URL, server.Client())
+	require.NoError(t, err)
+
+	reply := func(id string) (string, error) {
+		return handler.Reply(context.Background(), Mention{Tweet: model.Tweet{ID: id}, Author: &model.User{Username: "alice"}})
+	}
+	text, err := reply("1")
+	require.NoError(t, err)
+	require.Equal(t, "hi @alice", text)
+	text, err = reply("2")
+	require.NoError(t, err)
+	require.Equal(t, "plain reply", text)
+	text, err = reply("3")
+	require.NoError(t, err)
+	require.Empty(t, text)
+	_, err = reply("4")
+	require.ErrorContains(t, err, "status 500")
+}
+
+func TestCommandHandler(t *testing.T) {
+	handler, err := NewCommandHandler([]string{"sh", "-c", `echo "got $CTW_TWEET_ID from $CTW_AUTHOR_USERNAME"`})
+	require.NoError(t, err)
+
+	text, err := handler.Reply(context.Background(), Mention{Tweet: model.Tweet{ID: "9"}, Author: &model.User{Username: "bob"}})
+	require.NoError(t, err)
+	require.Equal(t, "got 9 from bob", text)
+
+	_, err = NewCommandHandler(nil)
+	require.Error(t, err)
+}
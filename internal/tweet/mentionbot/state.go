@@ -0,0 +1,96 @@
+package mentionbot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0dayfall/ctw/internal/utils"
+)
+
+// stateRetention is how long per-author and per-conversation bookkeeping is
+// kept after the last reply.
+const stateRetention = 7 * 24 * time.Hour
+
+// thread counts the replies sent into one conversation.
+type thread struct {
+	Replies int       `json:"replies"`
+	Last    time.Time `json:"last"`
+}
+
+// reply records the reply sent to one mention.
+type reply struct {
+	ReplyID string    `json:"reply_id"`
+	At      time.Time `json:"at"`
+}
+
+// state is what the bot remembers between polls and restarts.
+type state struct {
+	// Started is set by the first poll; before it, every mention is backlog.
+	Started time.Time `json:"started,omitzero"`
+	// SinceID is the newest mention already handled.
+	SinceID       string               `json:"since_id,omitempty"`
+	Replied       map[string]reply     `json:"replied"`
+	LastReply     map[string]time.Time `json:"last_reply"`
+	Conversations map[string]thread    `json:"conversations"`
+}
+
+func loadState(path string) (*state, error) {
+	s := &state{}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("mentionbot: read state: %w", err)
+	default:
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("mentionbot: decode state %s: %w", path, err)
+		}
+	}
+	if s.Replied == nil {
+		s.Replied = map[string]reply{}
+	}
+	if s.LastReply == nil {
+		s.LastReply = map[string]time.Time{}
+	}
+	if s.Conversations == nil {
+		s.Conversations = map[string]thread{}
+	}
+	return s, nil
+}
+
+// empty reports whether the bot has never polled with this state.
+func (s *state) empty() bool {
+	return s.Started.IsZero()
+}
+
+// save prunes bookkeeping older than stateRetention and writes the state
+// atomically.
+func (s *state) save(path string, now time.Time) error {
+	for id, r := range s.Replied {
+		if now.Sub(r.At) > stateRetention {
+			delete(s.Replied, id)
+		}
+	}
+	for author, at := range s.LastReply {
+		if now.Sub(at) > stateRetention {
+			delete(s.LastReply, author)
+		}
+	}
+	for id, t := range s.Conversations {
+		if now.Sub(t.Last) > stateRetention {
+			delete(s.Conversations, id)
+		}
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("mentionbot: encode state: %w", err)
+	}
+	if err := utils.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("mentionbot: write state: %w", err)
+	}
+	return nil
+}
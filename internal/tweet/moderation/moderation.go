@@ -0,0 +1,118 @@
+// Package moderation picks the replies of a conversation to hide.
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/0dayfall/ctw/internal/tweet/conversation"
+)
+
+// Blocklist matches replies by author or by text.
+type Blocklist struct {
+	// usernames holds lower-case usernames.
+	usernames map[string]bool
+	ids       map[string]bool
+	patterns  []*regexp.Regexp
+}
+
+// ParseBlocklist builds a Blocklist from entries. An entry starting with @
+// blocks that author; "id:" followed by a user ID blocks that account; any
+// other entry is a regular expression matched against the reply text, e.g.
+// (?i)free crypto. Blank entries are ignored.
+func ParseBlocklist(entries []string) (Blocklist, error) {
+	b := Blocklist{usernames: make(map[string]bool), ids: make(map[string]bool)}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "@"):
+			name := strings.ToLower(strings.TrimPrefix(entry, "@"))
+			if name == "" {
+				return Blocklist{}, fmt.Errorf("moderation: empty author %q", entry)
+			}
+			b.usernames[name] = true
+		case strings.HasPrefix(entry, "id:"):
+			id := strings.TrimSpace(strings.TrimPrefix(entry, "id:"))
+			if id == "" {
+				return Blocklist{}, fmt.Errorf("moderation: empty author id %q", entry)
+			}
+			b.ids[id] = true
+		default:
+			pattern, err := regexp.Compile(entry)
+			if err != nil {
+				return Blocklist{}, fmt.Errorf("moderation: pattern %q: %w", entry, err)
+			}
+			b.patterns = append(b.patterns, pattern)
+		}
+	}
+	return b, nil
+}
+
+// Empty reports whether b blocks nothing.
+func (b Blocklist) Empty() bool {
+	return len(b.usernames) == 0 && len(b.ids) == 0 && len(b.patterns) == 0
+}
+
+// Match reports why node is blocked, or "" when it is not.
+func (b Blocklist) Match(node *conversation.Node) string {
+	if node.Username != "" && b.usernames[strings.ToLower(node.Username)] {
+		return "author @" + node.Username
+	}
+	if node.AuthorID != "" && b.ids[node.AuthorID] {
+		return "author id:" + node.AuthorID
+	}
+	text := node.FullText()
+	for _, pattern := range b.patterns {
+		if pattern.MatchString(text) {
+			return "pattern " + pattern.String()
+		}
+	}
+	return ""
+}
+
+// Candidate is a reply the blocklist matched.
+type Candidate struct {
+	ID       string `json:"id"`
+	AuthorID string `json:"author_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Text     string `json:"text"`
+	Reason   string `json:"reason"`
+}
+
+// Candidates walks the replies of thread and returns those b matches, oldest
+// first within each branch. Tweets by the conversation's author are never
+// returned: they cannot be hidden.
+func Candidates(thread conversation.Thread, b Blocklist) []Candidate {
+	owner := ""
+	if thread.Root != nil && !thread.Root.Unavailable {
+		owner = thread.Root.AuthorID
+	}
+
+	var candidates []Candidate
+	var walk func(node *conversation.Node, root bool)
+	walk = func(node *conversation.Node, root bool) {
+		if !root && !node.Unavailable && (owner == "" || node.AuthorID != owner) {
+			if reason := b.Match(node); reason != "" {
+				candidates = append(candidates, Candidate{
+					ID:       node.ID,
+					AuthorID: node.AuthorID,
+					Username: node.Username,
+					Text:     node.FullText(),
+					Reason:   reason,
+				})
+			}
+		}
+		for _, reply := range node.Replies {
+			walk(reply, false)
+		}
+	}
+	if thread.Root != nil {
+		walk(thread.Root, true)
+	}
+	for _, node := range thread.Detached {
+		walk(node, false)
+	}
+	return candidates
+}
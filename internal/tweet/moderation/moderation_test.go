@@ -0,0 +1,64 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/0dayfall/ctw/internal/model"
+	"github.com/0dayfall/ctw/internal/tweet/conversation"
+	"github.com/stretchr/testify/require"
+)
+
+func node(id, authorID, username, text string, replies ...*conversation.Node) *conversation.Node {
+	return &conversation.Node{
+		Tweet:    model.Tweet{ID: id, AuthorID: authorID, Text: text},
+		Username: username,
+		Replies:  replies,
+	}
+}
+
+func TestParseBlocklist(t *testing.T) {
+	b, err := ParseBlocklist([]string{"@Spammer", "id:42", "(?i)free crypto", " "})
+	require.NoError(t, err)
+	require.False(t, b.Empty())
+	require.Equal(t, "author @spammer", b.Match(node("1", "7", "spammer", "hi")))
+	require.Equal(t, "author id:42", b.Match(node("1", "42", "", "hi")))
+	require.Equal(t, "pattern (?i)free crypto", b.Match(node("1", "7", "alice", "FREE CRYPTO here")))
+	require.Empty(t, b.Match(node("1", "7", "alice", "congrats")))
+
+	// Usernames and IDs are matched only against their own field.
+	b, err = ParseBlocklist([]string{"@12345", "id:jack"})
+	require.NoError(t, err)
+	require.Empty(t, b.Match(node("1", "12345", "alice", "hi")))
+	require.Empty(t, b.Match(node("1", "7", "jack", "hi")))
+	require.Equal(t, "author @12345", b.Match(node("1", "7", "12345", "hi")))
+
+	_, err = ParseBlocklist([]string{"(unclosed"})
+	require.ErrorContains(t, err, `moderation: pattern "(unclosed"`)
+
+	empty, err := ParseBlocklist(nil)
+	require.NoError(t, err)
+	require.True(t, empty.Empty())
+}
+
+func TestCandidates(t *testing.T) {
+	thread := conversation.Thread{
+		Root: node("1", "owner", "us", "Announcing free crypto... just kidding",
+			node("2", "a", "alice", "congrats!",
+				node("3", "s", "spammer", "buy now"),
+				node("4", "owner", "us", "free crypto is a scam, thanks"),
+			),
+			node("5", "b", "bob", "Free Crypto giveaway"),
+		),
+		Detached: []*conversation.Node{
+			{Tweet: model.Tweet{ID: "9"}, Unavailable: true, Replies: []*conversation.Node{node("10", "s", "spammer", "again")}},
+		},
+	}
+	b, err := ParseBlocklist([]string{"@spammer", "(?i)free crypto"})
+	require.NoError(t, err)
+
+	require.Equal(t, []Candidate{
+		{ID: "3", AuthorID: "s", Username: "spammer", Text: "buy now", Reason: "author @spammer"},
+		{ID: "5", AuthorID: "b", Username: "bob", Text: "Free Crypto giveaway", Reason: "pattern (?i)free crypto"},
+		{ID: "10", AuthorID: "s", Username: "spammer", Text: "again", Reason: "author @spammer"},
+	}, Candidates(thread, b))
+}
@@ -4,6 +4,12 @@ package publish
 type CreateTweetRequest struct {
 	Text  string `json:"text,omitempty"`
 	Media *Media `json:"media,omitempty"`
+	Reply *Reply `json:"reply,omitempty"`
+}
+
+// Reply makes the new tweet a reply in the thread of another tweet.
+type Reply struct {
+	InReplyToTweetID string `json:"in_reply_to_tweet_id"`
 }
 
 // Media represents media attachments for a tweet.
@@ -31,3 +37,18 @@ type DeleteTweetResponse struct {
 type DeleteData struct {
 	Deleted bool `json:"deleted"`
 }
+
+// HideReplyRequest is the payload of PUT /2/tweets/:id/hidden.
+type HideReplyRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// HideReplyResponse captures the response payload from PUT /2/tweets/:id/hidden.
+type HideReplyResponse struct {
+	Data HiddenData `json:"data"`
+}
+
+// HiddenData reports whether the reply is now hidden.
+type HiddenData struct {
+	Hidden bool `json:"hidden"`
+}
@@ -11,9 +11,10 @@ import (
 const (
 	createTweetPath = "/2/tweets"
 	deleteTweetFmt  = "/2/tweets/%s"
+	hideReplyFmt    = "/2/tweets/%s/hidden"
 )
 
-// Service coordinates tweet create/delete operations.
+// Service coordinates tweet create/delete operations and hiding replies.
 // Prefer constructing a single instance via NewService and reusing it across commands.
 type Service struct {
 	client *client.Client
@@ -77,3 +78,33 @@ func (s *Service) DeleteTweet(ctx context.Context, tweetID string) (DeleteTweetR
 
 	return payload, rateLimits, nil
 }
+
+// HideReply hides or, with hidden false, unhides tweetID, a reply in a
+// conversation the authenticated user started. It needs a user-context token.
+func (s *Service) HideReply(ctx context.Context, tweetID string, hidden bool) (HideReplyResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return HideReplyResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("publish: nil service")
+	}
+	if tweetID == "" {
+		return HideReplyResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("publish: tweet id is required")
+	}
+
+	path := fmt.Sprintf(hideReplyFmt, tweetID)
+	resp, err := s.client.Put(ctx, path, HideReplyRequest{Hidden: hidden}, nil)
+	if err != nil {
+		return HideReplyResponse{}, client.RateLimitSnapshot{}, err
+	}
+	defer client.SafeClose(resp.Body)
+
+	rateLimits := client.ParseRateLimits(resp)
+	if err := client.CheckResponse(resp); err != nil {
+		return HideReplyResponse{}, rateLimits, err
+	}
+
+	var payload HideReplyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return HideReplyResponse{}, rateLimits, fmt.Errorf("publish: decode hide reply response: %w", err)
+	}
+
+	return payload, rateLimits, nil
+}
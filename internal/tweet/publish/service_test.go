@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -35,6 +36,22 @@ func TestCreateTweet(t *testing.T) {
 	require.Equal(t, 42, rateLimits.Reset)
 }
 
+func TestCreateReply(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"text":"thanks!","reply":{"in_reply_to_tweet_id":"99"}}`, string(body))
+
+		res.WriteHeader(http.StatusCreated)
+		err = json.NewEncoder(res).Encode(CreateTweetResponse{Data: TweetData{ID: "2", Text: "thanks!"}})
+		require.NoError(t, err)
+	})
+
+	response, _, err := service.CreateTweet(context.Background(), CreateTweetRequest{Text: "thanks!", Reply: &Reply{InReplyToTweetID: "99"}})
+	require.NoError(t, err)
+	require.Equal(t, "2", response.Data.ID)
+}
+
 func TestDeleteTweet(t *testing.T) {
 	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
 		require.Equal(t, http.MethodDelete, req.Method)
@@ -48,3 +65,22 @@ func TestDeleteTweet(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, response.Data.Deleted)
 }
+
+func TestHideReply(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/2/tweets/7/hidden", req.URL.Path)
+		var body HideReplyRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		_, err := res.Write([]byte(`{"data":{"hidden":` + strconv.FormatBool(body.Hidden) + `}}`))
+		require.NoError(t, err)
+	})
+
+	response, _, err := service.HideReply(context.Background(), "7", true)
+	require.NoError(t, err)
+	require.True(t, response.Data.Hidden)
+
+	response, _, err = service.HideReply(context.Background(), "7", false)
+	require.NoError(t, err)
+	require.False(t, response.Data.Hidden)
+}
@@ -12,5 +12,6 @@ type Data struct {
 	TweetCount int       `json:"tweet_count"`
 }
 type Meta struct {
-	TotalTweetCount int `json:"total_tweet_count"`
+	TotalTweetCount int    `json:"total_tweet_count"`
+	NextToken       string `json:"next_token,omitempty"`
 }
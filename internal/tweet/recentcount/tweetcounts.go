@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/0dayfall/ctw/internal/client"
 )
@@ -11,6 +12,7 @@ import (
 const (
 	recentCountsPath = "/2/tweets/counts/recent"
 	allCountsPath    = "/2/tweets/counts/all"
+	nextTokenParam   = "next_token"
 )
 
 // Service coordinates tweet count operations.
@@ -93,3 +95,28 @@ func (s *Service) GetAllCount(ctx context.Context, query, granularity string, pa
 
 	return payload, rateLimits, nil
 }
+
+// GetAllCountPages follows the full-archive endpoint's next_token until every
+// bucket is fetched or maxPages pages were read (0 means no limit). The
+// buckets are returned oldest first and the totals are summed. Meta.NextToken
+// is only set when maxPages stopped the walk early.
+func (s *Service) GetAllCountPages(ctx context.Context, query, granularity string, params map[string]string, maxPages int) (CountResponse, client.RateLimitSnapshot, error) {
+	var (
+		all        CountResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	next, err := client.Paginate(params, nextTokenParam, maxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := s.GetAllCount(ctx, query, granularity, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Data = append(all.Data, resp.Data...)
+		all.Meta.TotalTweetCount += resp.Meta.TotalTweetCount
+		return resp.Meta.NextToken, nil
+	})
+	all.Meta.NextToken = next
+	// Pages run from the newest buckets back in time.
+	sort.SliceStable(all.Data, func(i, j int) bool { return all.Data[i].Start.Before(all.Data[j].Start) })
+	return all, rateLimits, err
+}
@@ -34,3 +34,34 @@ func TestGetRecentCount(t *testing.T) {
 	require.NoError(t, err)
 	require.Zero(t, response.Meta.TotalTweetCount)
 }
+
+func TestGetAllCountPagesFollowsNextToken(t *testing.T) {
+	var tokens []string
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/2/tweets/counts/all", req.URL.Path)
+		require.Equal(t, "hour", req.URL.Query().Get("granularity"))
+		token := req.URL.Query().Get("next_token")
+		tokens = append(tokens, token)
+
+		payload := `{"data":[{"start":"2021-06-15T02:00:00.000Z","end":"2021-06-15T03:00:00.000Z","tweet_count":5}],"meta":{"total_tweet_count":5,"next_token":"p2"}}`
+		if token == "p2" {
+			payload = `{"data":[{"start":"2021-06-15T01:00:00.000Z","end":"2021-06-15T02:00:00.000Z","tweet_count":3}],"meta":{"total_tweet_count":3}}`
+		}
+		_, err := res.Write([]byte(payload))
+		require.NoError(t, err)
+	})
+
+	response, _, err := service.GetAllCountPages(context.Background(), "golang", "hour", nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "p2"}, tokens)
+	require.Equal(t, 8, response.Meta.TotalTweetCount)
+	require.Empty(t, response.Meta.NextToken)
+	require.Len(t, response.Data, 2)
+	require.Equal(t, 3, response.Data[0].TweetCount)
+
+	tokens = nil
+	response, _, err = service.GetAllCountPages(context.Background(), "golang", "hour", nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, tokens)
+	require.Equal(t, "p2", response.Meta.NextToken)
+}
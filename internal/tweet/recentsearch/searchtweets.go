@@ -6,11 +6,15 @@ import (
 	"fmt"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
-const recentSearchPath = "/2/tweets/search/recent"
+const (
+	recentSearchPath = "/2/tweets/search/recent"
+	allSearchPath    = "/2/tweets/search/all"
+)
 
-// Service exposes helpers for the recent search endpoints.
+// Service exposes helpers for the recent and full-archive search endpoints.
 type Service struct {
 	client *client.Client
 }
@@ -26,9 +30,19 @@ func NewService(c *client.Client) *Service {
 // SearchRecent queries the recent search endpoint with optional query parameters.
 // The query string is always applied while the params map can be used for
 // pagination or additional expansions.
-func (s *Service) SearchRecent(ctx context.Context, query string, params map[string]string) (SearchRecentResponse, client.RateLimitSnapshot, error) {
+func (s *Service) SearchRecent(ctx context.Context, query string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	return s.search(ctx, recentSearchPath, query, params)
+}
+
+// SearchAll queries the full-archive search endpoint, which needs Pro or
+// Enterprise access, with optional query parameters.
+func (s *Service) SearchAll(ctx context.Context, query string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	return s.search(ctx, allSearchPath, query, params)
+}
+
+func (s *Service) search(ctx context.Context, path, query string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return SearchRecentResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("recentsearch: nil service")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("recentsearch: nil service")
 	}
 
 	qp := map[string]string{"query": query}
@@ -39,27 +53,27 @@ func (s *Service) SearchRecent(ctx context.Context, query string, params map[str
 		qp[key] = value
 	}
 
-	resp, err := s.client.Get(ctx, recentSearchPath, qp)
+	resp, err := s.client.Get(ctx, path, qp)
 	if err != nil {
-		return SearchRecentResponse{}, client.RateLimitSnapshot{}, err
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return SearchRecentResponse{}, rateLimits, err
+		return model.TweetsResponse{}, rateLimits, err
 	}
 
-	var payload SearchRecentResponse
+	var payload model.TweetsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return SearchRecentResponse{}, rateLimits, fmt.Errorf("recentsearch: decode response: %w", err)
+		return model.TweetsResponse{}, rateLimits, fmt.Errorf("recentsearch: decode response: %w", err)
 	}
 
 	return payload, rateLimits, nil
 }
 
 // SearchRecentNextToken is a convenience wrapper that applies a pagination token.
-func (s *Service) SearchRecentNextToken(ctx context.Context, query, token string) (SearchRecentResponse, client.RateLimitSnapshot, error) {
+func (s *Service) SearchRecentNextToken(ctx context.Context, query, token string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	params := map[string]string{"pagination_token": token}
 	return s.SearchRecent(ctx, query, params)
 }
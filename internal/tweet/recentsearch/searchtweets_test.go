@@ -64,3 +64,17 @@ func TestSearchRecentNextToken(t *testing.T) {
 	require.NoError(t, err)
 	require.Zero(t, response.Meta.ResultCount)
 }
+
+func TestSearchAll(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/2/tweets/search/all", req.URL.Path)
+		require.Equal(t, "conversation_id:99", req.URL.Query().Get("query"))
+		require.Equal(t, "2020-01-01T00:00:00Z", req.URL.Query().Get("start_time"))
+		_, err := res.Write([]byte(`{"data":[{"id":"1","text":"old reply"}],"meta":{"result_count":1}}`))
+		require.NoError(t, err)
+	})
+
+	response, _, err := service.SearchAll(context.Background(), "conversation_id:99", map[string]string{"start_time": "2020-01-01T00:00:00Z"})
+	require.NoError(t, err)
+	require.Len(t, response.Data, 1)
+}
@@ -6,15 +6,18 @@ import (
 	"fmt"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
 const (
 	retweetPathFormat    = "/2/users/%s/retweets"
 	unretweetPathFormat  = "/2/users/%s/retweets/%s"
 	retweetersPathFormat = "/2/tweets/%s/retweeted_by"
+	quotesPathFormat     = "/2/tweets/%s/quote_tweets"
 )
 
-// Service coordinates Twitter retweet operations.
+// Service coordinates Twitter retweet operations. Retweet and Unretweet act
+// for the authenticated user when userID is empty.
 type Service struct {
 	client *client.Client
 }
@@ -32,8 +35,9 @@ func (s *Service) Retweet(ctx context.Context, userID, tweetID string) (RetweetR
 	if s == nil {
 		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: nil service")
 	}
-	if userID == "" {
-		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: user id is required")
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: %w", err)
 	}
 	if tweetID == "" {
 		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: tweet id is required")
@@ -66,8 +70,9 @@ func (s *Service) Unretweet(ctx context.Context, userID, tweetID string) (Retwee
 	if s == nil {
 		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: nil service")
 	}
-	if userID == "" {
-		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: user id is required")
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: %w", err)
 	}
 	if tweetID == "" {
 		return RetweetResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: tweet id is required")
@@ -94,32 +99,102 @@ func (s *Service) Unretweet(ctx context.Context, userID, tweetID string) (Retwee
 	return result, rateLimits, nil
 }
 
-// ListRetweeters fetches users who retweeted the specified tweet.
-func (s *Service) ListRetweeters(ctx context.Context, tweetID string, params map[string]string) (RetweetersResponse, client.RateLimitSnapshot, error) {
+// ListRetweeters fetches users who retweeted the specified tweet. Pass the
+// previous page's Meta.NextToken as pagination_token to continue.
+func (s *Service) ListRetweeters(ctx context.Context, tweetID string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return RetweetersResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: nil service")
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: nil service")
 	}
 	if tweetID == "" {
-		return RetweetersResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: tweet id is required")
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: tweet id is required")
 	}
 
 	path := fmt.Sprintf(retweetersPathFormat, tweetID)
 
 	resp, err := s.client.Get(ctx, path, params)
 	if err != nil {
-		return RetweetersResponse{}, client.RateLimitSnapshot{}, err
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return RetweetersResponse{}, rateLimits, err
+		return model.UsersResponse{}, rateLimits, err
 	}
 
-	var result RetweetersResponse
+	var result model.UsersResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return RetweetersResponse{}, rateLimits, fmt.Errorf("retweets: decode response: %w", err)
+		return model.UsersResponse{}, rateLimits, fmt.Errorf("retweets: decode response: %w", err)
 	}
 
 	return result, rateLimits, nil
 }
+
+// AllRetweeters follows pagination until every retweeter is fetched or
+// maxPages pages were read (0 means no limit).
+func (s *Service) AllRetweeters(ctx context.Context, tweetID string, params map[string]string, maxPages int) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	var (
+		all        model.UsersResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(params, client.PaginationTokenParam, maxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := s.ListRetweeters(ctx, tweetID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Append(resp)
+		return resp.Meta.NextToken, nil
+	})
+	return all, rateLimits, err
+}
+
+// ListQuoteTweets fetches one page of the tweets quoting tweetID. Pass the
+// previous page's Meta.NextToken as pagination_token to continue.
+func (s *Service) ListQuoteTweets(ctx context.Context, tweetID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	if s == nil {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: nil service")
+	}
+	if tweetID == "" {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("retweets: tweet id is required")
+	}
+
+	path := fmt.Sprintf(quotesPathFormat, tweetID)
+
+	resp, err := s.client.Get(ctx, path, params)
+	if err != nil {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, err
+	}
+	defer client.SafeClose(resp.Body)
+
+	rateLimits := client.ParseRateLimits(resp)
+	if err := client.CheckResponse(resp); err != nil {
+		return model.TweetsResponse{}, rateLimits, err
+	}
+
+	var result model.TweetsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return model.TweetsResponse{}, rateLimits, fmt.Errorf("retweets: decode quote tweets response: %w", err)
+	}
+
+	return result, rateLimits, nil
+}
+
+// AllQuoteTweets follows pagination until every quote tweet is fetched or
+// maxPages pages were read (0 means no limit).
+func (s *Service) AllQuoteTweets(ctx context.Context, tweetID string, params map[string]string, maxPages int) (model.TweetsResponse, client.RateLimitSnapshot, error) {
+	var (
+		all        model.TweetsResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(params, client.PaginationTokenParam, maxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := s.ListQuoteTweets(ctx, tweetID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Append(resp)
+		return resp.Meta.NextToken, nil
+	})
+	return all, rateLimits, err
+}
@@ -64,3 +64,37 @@ func TestListRetweeters(t *testing.T) {
 	require.Equal(t, "User One", resp.Data[0].Name)
 	require.Equal(t, 75, rateLimits.Limit)
 }
+
+func TestAllRetweetersFollowsPagination(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/2/tweets/tweet-3/retweeted_by", r.URL.Path)
+		if r.URL.Query().Get("pagination_token") == "" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"u1","username":"user1"}],"meta":{"result_count":1,"next_token":"p2"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"u2","username":"user2"}],"meta":{"result_count":1}}`))
+	})
+
+	resp, _, err := service.AllRetweeters(context.Background(), "tweet-3", nil, 0)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	require.Equal(t, "u2", resp.Data[1].ID)
+}
+
+func TestAllQuoteTweets(t *testing.T) {
+	pages := 0
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/2/tweets/tweet-3/quote_tweets", r.URL.Path)
+		require.Equal(t, "author_id", r.URL.Query().Get("expansions"))
+		pages++
+		_, _ = w.Write([]byte(`{"data":[{"id":"q1","text":"so true","author_id":"u1"}],"includes":{"users":[{"id":"u1","username":"user1"}]},"meta":{"result_count":1,"next_token":"more"}}`))
+	})
+
+	resp, _, err := service.AllQuoteTweets(context.Background(), "tweet-3", map[string]string{"expansions": "author_id"}, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, pages)
+	require.Len(t, resp.Data, 2)
+	author, ok := resp.AuthorOf(resp.Data[0])
+	require.True(t, ok)
+	require.Equal(t, "user1", author.Username)
+}
@@ -1,3 +1,4 @@
+// Package timelines provides helpers for interacting with Twitter timeline endpoints.
 package timelines
 
 import (
@@ -6,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
 const (
@@ -28,12 +30,12 @@ func NewService(c *client.Client) *Service {
 }
 
 // GetUserTweets fetches tweets posted by the specified user.
-func (s *Service) GetUserTweets(ctx context.Context, userID string, params map[string]string) (TimelineResponse, client.RateLimitSnapshot, error) {
+func (s *Service) GetUserTweets(ctx context.Context, userID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return TimelineResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: nil service")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: nil service")
 	}
 	if userID == "" {
-		return TimelineResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: user id is required")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: user id is required")
 	}
 
 	path := fmt.Sprintf(userTweetsPathFormat, userID)
@@ -41,12 +43,12 @@ func (s *Service) GetUserTweets(ctx context.Context, userID string, params map[s
 }
 
 // GetUserMentions fetches tweets that mention the specified user.
-func (s *Service) GetUserMentions(ctx context.Context, userID string, params map[string]string) (TimelineResponse, client.RateLimitSnapshot, error) {
+func (s *Service) GetUserMentions(ctx context.Context, userID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return TimelineResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: nil service")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: nil service")
 	}
 	if userID == "" {
-		return TimelineResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: user id is required")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: user id is required")
 	}
 
 	path := fmt.Sprintf(userMentionsPathFormat, userID)
@@ -54,33 +56,35 @@ func (s *Service) GetUserMentions(ctx context.Context, userID string, params map
 }
 
 // GetReverseChronological fetches the reverse chronological home timeline for the authenticated user.
-func (s *Service) GetReverseChronological(ctx context.Context, userID string, params map[string]string) (TimelineResponse, client.RateLimitSnapshot, error) {
+// userID may be empty, in which case it is looked up from the token.
+func (s *Service) GetReverseChronological(ctx context.Context, userID string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	if s == nil {
-		return TimelineResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: nil service")
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: nil service")
 	}
-	if userID == "" {
-		return TimelineResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: user id is required")
+	userID, err := s.client.ResolveUserID(ctx, userID)
+	if err != nil {
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("timelines: %w", err)
 	}
 
 	path := fmt.Sprintf(reverseChronoPathFormat, userID)
 	return s.fetch(ctx, path, params)
 }
 
-func (s *Service) fetch(ctx context.Context, path string, params map[string]string) (TimelineResponse, client.RateLimitSnapshot, error) {
+func (s *Service) fetch(ctx context.Context, path string, params map[string]string) (model.TweetsResponse, client.RateLimitSnapshot, error) {
 	resp, err := s.client.Get(ctx, path, params)
 	if err != nil {
-		return TimelineResponse{}, client.RateLimitSnapshot{}, err
+		return model.TweetsResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return TimelineResponse{}, rateLimits, err
+		return model.TweetsResponse{}, rateLimits, err
 	}
 
-	var payload TimelineResponse
+	var payload model.TweetsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return TimelineResponse{}, rateLimits, fmt.Errorf("timelines: decode response: %w", err)
+		return model.TweetsResponse{}, rateLimits, fmt.Errorf("timelines: decode response: %w", err)
 	}
 
 	return payload, rateLimits, nil
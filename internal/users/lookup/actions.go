@@ -9,7 +9,14 @@ import (
 )
 
 // Block prevents the target user from interacting with the source user.
+// Like every relationship action here, an empty sourceID acts for the
+// authenticated user.
 func (s *Service) Block(ctx context.Context, sourceID, targetID string) (RelationshipResponse, client.RateLimitSnapshot, error) {
+	sourceID, err := s.client.ResolveUserID(ctx, sourceID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("userslookup: %w", err)
+	}
+
 	path := fmt.Sprintf("/2/users/%s/blocking", sourceID)
 	payload := map[string]string{"target_user_id": targetID}
 	resp, err := s.client.Post(ctx, path, payload, nil)
@@ -33,6 +40,11 @@ func (s *Service) Block(ctx context.Context, sourceID, targetID string) (Relatio
 
 // Unblock removes an existing block relationship.
 func (s *Service) Unblock(ctx context.Context, sourceID, targetID string) (RelationshipResponse, client.RateLimitSnapshot, error) {
+	sourceID, err := s.client.ResolveUserID(ctx, sourceID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("userslookup: %w", err)
+	}
+
 	path := fmt.Sprintf("/2/users/%s/blocking/%s", sourceID, targetID)
 	resp, err := s.client.Delete(ctx, path, nil)
 	if err != nil {
@@ -55,6 +67,11 @@ func (s *Service) Unblock(ctx context.Context, sourceID, targetID string) (Relat
 
 // Follow creates a follow relationship from sourceID to targetID.
 func (s *Service) Follow(ctx context.Context, sourceID, targetID string) (RelationshipResponse, client.RateLimitSnapshot, error) {
+	sourceID, err := s.client.ResolveUserID(ctx, sourceID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("userslookup: %w", err)
+	}
+
 	path := fmt.Sprintf("/2/users/%s/following", sourceID)
 	payload := map[string]string{"target_user_id": targetID}
 	resp, err := s.client.Post(ctx, path, payload, nil)
@@ -78,6 +95,11 @@ func (s *Service) Follow(ctx context.Context, sourceID, targetID string) (Relati
 
 // Unfollow removes a follow relationship.
 func (s *Service) Unfollow(ctx context.Context, sourceID, targetID string) (RelationshipResponse, client.RateLimitSnapshot, error) {
+	sourceID, err := s.client.ResolveUserID(ctx, sourceID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("userslookup: %w", err)
+	}
+
 	path := fmt.Sprintf("/2/users/%s/following/%s", sourceID, targetID)
 	resp, err := s.client.Delete(ctx, path, nil)
 	if err != nil {
@@ -98,6 +120,61 @@ func (s *Service) Unfollow(ctx context.Context, sourceID, targetID string) (Rela
 	return relationship, rateLimits, nil
 }
 
+// Mute hides the target user's tweets from the source user's timelines.
+func (s *Service) Mute(ctx context.Context, sourceID, targetID string) (RelationshipResponse, client.RateLimitSnapshot, error) {
+	sourceID, err := s.client.ResolveUserID(ctx, sourceID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("userslookup: %w", err)
+	}
+
+	path := fmt.Sprintf("/2/users/%s/muting", sourceID)
+	payload := map[string]string{"target_user_id": targetID}
+	resp, err := s.client.Post(ctx, path, payload, nil)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, err
+	}
+	defer client.SafeClose(resp.Body)
+
+	rateLimits := client.ParseRateLimits(resp)
+	if err := client.CheckResponse(resp); err != nil {
+		return RelationshipResponse{}, rateLimits, err
+	}
+
+	var relationship RelationshipResponse
+	if err := json.NewDecoder(resp.Body).Decode(&relationship); err != nil {
+		return RelationshipResponse{}, rateLimits, fmt.Errorf("userslookup: decode mute response: %w", err)
+	}
+
+	return relationship, rateLimits, nil
+}
+
+// Unmute removes an existing mute.
+func (s *Service) Unmute(ctx context.Context, sourceID, targetID string) (RelationshipResponse, client.RateLimitSnapshot, error) {
+	sourceID, err := s.client.ResolveUserID(ctx, sourceID)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("userslookup: %w", err)
+	}
+
+	path := fmt.Sprintf("/2/users/%s/muting/%s", sourceID, targetID)
+	resp, err := s.client.Delete(ctx, path, nil)
+	if err != nil {
+		return RelationshipResponse{}, client.RateLimitSnapshot{}, err
+	}
+	defer client.SafeClose(resp.Body)
+
+	rateLimits := client.ParseRateLimits(resp)
+	if err := client.CheckResponse(resp); err != nil {
+		return RelationshipResponse{}, rateLimits, err
+	}
+
+	var relationship RelationshipResponse
+	if err := json.NewDecoder(resp.Body).Decode(&relationship); err != nil {
+		return RelationshipResponse{}, rateLimits, fmt.Errorf("userslookup: decode unmute response: %w", err)
+	}
+
+	return relationship, rateLimits, nil
+}
+
 // RelationshipResponse captures the relationship mutation payloads.
 type RelationshipResponse struct {
 	Data RelationshipData `json:"data"`
@@ -107,5 +184,6 @@ type RelationshipResponse struct {
 type RelationshipData struct {
 	Blocking      bool `json:"blocking,omitempty"`
 	Following     bool `json:"following,omitempty"`
+	Muting        bool `json:"muting,omitempty"`
 	PendingFollow bool `json:"pending_follow,omitempty"`
 }
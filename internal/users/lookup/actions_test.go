@@ -71,3 +71,53 @@ func TestUnfollow(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, resp.Data.Following)
 }
+
+func TestMute(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/2/users/1/muting", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"target_user_id":"2"}`, string(body))
+		res.WriteHeader(http.StatusOK)
+		_, err = res.Write([]byte(`{"data": {"muting": true}}`))
+		require.NoError(t, err)
+	})
+
+	resp, _, err := service.Mute(context.Background(), "1", "2")
+	require.NoError(t, err)
+	require.True(t, resp.Data.Muting)
+}
+
+func TestUnmute(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/2/users/1/muting/2", req.URL.Path)
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(`{"data": {"muting": false}}`))
+		require.NoError(t, err)
+	})
+
+	resp, _, err := service.Unmute(context.Background(), "1", "2")
+	require.NoError(t, err)
+	require.False(t, resp.Data.Muting)
+}
+
+func TestFollowDefaultsToAuthenticatedUser(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/2/users/me":
+			_, err := res.Write([]byte(`{"data": {"id": "1", "name": "Me", "username": "me"}}`))
+			require.NoError(t, err)
+		case "/2/users/1/following":
+			_, err := res.Write([]byte(`{"data": {"following": true}}`))
+			require.NoError(t, err)
+		default:
+			t.Fatalf("unexpected path %s", req.URL.Path)
+		}
+	})
+
+	resp, _, err := service.Follow(context.Background(), "", "2")
+	require.NoError(t, err)
+	require.True(t, resp.Data.Following)
+}
@@ -9,7 +9,7 @@ import (
 	"strings"
 
 	"github.com/0dayfall/ctw/internal/client"
-	common "github.com/0dayfall/ctw/internal/data"
+	"github.com/0dayfall/ctw/internal/model"
 )
 
 const (
@@ -17,6 +17,7 @@ const (
 	userByIDPath       = "/2/users/%s"
 	usersByUsername    = "/2/users/by"
 	userByUsernamePath = "/2/users/by/username/%s"
+	mePath             = "/2/users/me"
 )
 
 // Service coordinates user lookup operations.
@@ -33,19 +34,25 @@ func NewService(c *client.Client) *Service {
 }
 
 // LookupID fetches a single user by ID.
-func (s *Service) LookupID(ctx context.Context, id string, params map[string]string) (User, client.RateLimitSnapshot, error) {
+func (s *Service) LookupID(ctx context.Context, id string, params map[string]string) (model.UserResponse, client.RateLimitSnapshot, error) {
 	path := fmt.Sprintf(userByIDPath, id)
 	return s.fetchSingle(ctx, path, params)
 }
 
 // LookupUsername fetches a single user by username.
-func (s *Service) LookupUsername(ctx context.Context, username string, params map[string]string) (User, client.RateLimitSnapshot, error) {
+func (s *Service) LookupUsername(ctx context.Context, username string, params map[string]string) (model.UserResponse, client.RateLimitSnapshot, error) {
 	path := fmt.Sprintf(userByUsernamePath, username)
 	return s.fetchSingle(ctx, path, params)
 }
 
+// Me fetches the user the bearer token acts for. It needs user-context
+// authentication; app-only tokens are rejected by the API.
+func (s *Service) Me(ctx context.Context, params map[string]string) (model.UserResponse, client.RateLimitSnapshot, error) {
+	return s.fetchSingle(ctx, mePath, params)
+}
+
 // LookupIDs fetches multiple users by comma-separated IDs.
-func (s *Service) LookupIDs(ctx context.Context, ids []string, params map[string]string) ([]User, client.RateLimitSnapshot, error) {
+func (s *Service) LookupIDs(ctx context.Context, ids []string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
 	qp := map[string]string{
 		"ids": strings.Join(ids, ","),
 	}
@@ -58,25 +65,25 @@ func (s *Service) LookupIDs(ctx context.Context, ids []string, params map[string
 
 	resp, err := s.client.Get(ctx, usersPath, qp)
 	if err != nil {
-		return nil, client.RateLimitSnapshot{}, err
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return nil, rateLimits, err
+		return model.UsersResponse{}, rateLimits, err
 	}
 
-	var payload UsersResponse
+	var payload model.UsersResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, rateLimits, fmt.Errorf("userslookup: decode ids response: %w", err)
+		return model.UsersResponse{}, rateLimits, fmt.Errorf("userslookup: decode ids response: %w", err)
 	}
 
-	return payload.Data, rateLimits, nil
+	return payload, rateLimits, nil
 }
 
 // LookupUsernames fetches multiple users by username.
-func (s *Service) LookupUsernames(ctx context.Context, usernames []string, params map[string]string) ([]User, client.RateLimitSnapshot, error) {
+func (s *Service) LookupUsernames(ctx context.Context, usernames []string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
 	qp := map[string]string{
 		"usernames": strings.Join(usernames, ","),
 	}
@@ -89,81 +96,39 @@ func (s *Service) LookupUsernames(ctx context.Context, usernames []string, param
 
 	resp, err := s.client.Get(ctx, usersByUsername, qp)
 	if err != nil {
-		return nil, client.RateLimitSnapshot{}, err
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return nil, rateLimits, err
+		return model.UsersResponse{}, rateLimits, err
 	}
 
-	var payload UsersResponse
+	var payload model.UsersResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, rateLimits, fmt.Errorf("userslookup: decode usernames response: %w", err)
+		return model.UsersResponse{}, rateLimits, fmt.Errorf("userslookup: decode usernames response: %w", err)
 	}
 
-	return payload.Data, rateLimits, nil
+	return payload, rateLimits, nil
 }
 
-func (s *Service) fetchSingle(ctx context.Context, path string, params map[string]string) (User, client.RateLimitSnapshot, error) {
+func (s *Service) fetchSingle(ctx context.Context, path string, params map[string]string) (model.UserResponse, client.RateLimitSnapshot, error) {
 	resp, err := s.client.Get(ctx, path, params)
 	if err != nil {
-		return User{}, client.RateLimitSnapshot{}, err
+		return model.UserResponse{}, client.RateLimitSnapshot{}, err
 	}
 	defer client.SafeClose(resp.Body)
 
 	rateLimits := client.ParseRateLimits(resp)
 	if err := client.CheckResponse(resp); err != nil {
-		return User{}, rateLimits, err
+		return model.UserResponse{}, rateLimits, err
 	}
 
-	var payload UserResponse
+	var payload model.UserResponse
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return User{}, rateLimits, fmt.Errorf("userslookup: decode response: %w", err)
+		return model.UserResponse{}, rateLimits, fmt.Errorf("userslookup: decode response: %w", err)
 	}
 
-	return payload.Data, rateLimits, nil
-}
-
-// User describes the Twitter user payload returned by the API.
-type User struct {
-	ID              string          `json:"id"`
-	Name            string          `json:"name"`
-	UserName        string          `json:"username"`
-	CreatedAt       string          `json:"created_at"`
-	Description     string          `json:"description"`
-	Entities        common.Entities `json:"entities"`
-	Location        string          `json:"location"`
-	PinnedTweetID   string          `json:"pinned_tweet_id"`
-	ProfileImageURL string          `json:"profile_image_url"`
-	Protected       bool            `json:"protected"`
-	PublicMetrics   UserMetrics     `json:"public_metrics"`
-	URL             string          `json:"url"`
-	Verified        bool            `json:"verified"`
-	WithHeld        WithHeld        `json:"withheld"`
-}
-
-// WithHeld captures withheld information for a user.
-type WithHeld struct {
-	Copyright    bool     `json:"copyright"`
-	CountryCodes []string `json:"country_codes"`
-}
-
-// UserMetrics contains activity metrics.
-type UserMetrics struct {
-	Followers int `json:"followers_count"`
-	Following int `json:"following_count"`
-	Tweets    int `json:"tweet_count"`
-	Listed    int `json:"listed_count"`
-}
-
-// UserResponse wraps a single user payload.
-type UserResponse struct {
-	Data User `json:"data"`
-}
-
-// UsersResponse wraps multiple user payloads.
-type UsersResponse struct {
-	Data []User `json:"data"`
+	return payload, rateLimits, nil
 }
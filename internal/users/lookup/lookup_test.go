@@ -19,7 +19,7 @@ func TestLookupID(t *testing.T) {
 
 	user, _, err := service.LookupID(context.Background(), "123", nil)
 	require.NoError(t, err)
-	require.Equal(t, "123", user.ID)
+	require.Equal(t, "123", user.Data.ID)
 }
 
 func TestLookupUsername(t *testing.T) {
@@ -32,7 +32,7 @@ func TestLookupUsername(t *testing.T) {
 
 	user, _, err := service.LookupUsername(context.Background(), "jane", nil)
 	require.NoError(t, err)
-	require.Equal(t, "jane", user.UserName)
+	require.Equal(t, "jane", user.Data.Username)
 }
 
 func TestLookupIDs(t *testing.T) {
@@ -46,7 +46,7 @@ func TestLookupIDs(t *testing.T) {
 
 	users, _, err := service.LookupIDs(context.Background(), []string{"1", "2"}, nil)
 	require.NoError(t, err)
-	require.Len(t, users, 2)
+	require.Len(t, users.Data, 2)
 }
 
 func TestLookupUsernames(t *testing.T) {
@@ -60,5 +60,18 @@ func TestLookupUsernames(t *testing.T) {
 
 	users, _, err := service.LookupUsernames(context.Background(), []string{"alice", "bob"}, nil)
 	require.NoError(t, err)
-	require.Len(t, users, 1)
+	require.Len(t, users.Data, 1)
+}
+
+func TestMe(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/2/users/me", req.URL.Path)
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(`{"data": {"id": "7", "name": "Me", "username": "me"}}`))
+		require.NoError(t, err)
+	})
+
+	user, _, err := service.Me(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "7", user.Data.ID)
 }
@@ -0,0 +1,79 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/0dayfall/ctw/internal/client"
+	"github.com/0dayfall/ctw/internal/model"
+)
+
+const (
+	mutingPathFormat   = "/2/users/%s/muting"
+	blockingPathFormat = "/2/users/%s/blocking"
+)
+
+// ListMuting fetches one page of the users userID has muted. Pass the
+// previous page's Meta.NextToken as pagination_token to continue.
+func (s *Service) ListMuting(ctx context.Context, userID string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	return s.listRelation(ctx, mutingPathFormat, userID, params)
+}
+
+// ListBlocking fetches one page of the users userID has blocked.
+func (s *Service) ListBlocking(ctx context.Context, userID string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	return s.listRelation(ctx, blockingPathFormat, userID, params)
+}
+
+// AllMuting follows pagination until every muted user is fetched or maxPages
+// pages were read (0 means no limit).
+func (s *Service) AllMuting(ctx context.Context, userID string, params map[string]string, maxPages int) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	return s.allRelation(ctx, mutingPathFormat, userID, params, maxPages)
+}
+
+// AllBlocking follows pagination until every blocked user is fetched or
+// maxPages pages were read (0 means no limit).
+func (s *Service) AllBlocking(ctx context.Context, userID string, params map[string]string, maxPages int) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	return s.allRelation(ctx, blockingPathFormat, userID, params, maxPages)
+}
+
+func (s *Service) listRelation(ctx context.Context, format, userID string, params map[string]string) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	if userID == "" {
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, fmt.Errorf("userslookup: user id is required")
+	}
+
+	resp, err := s.client.Get(ctx, fmt.Sprintf(format, userID), params)
+	if err != nil {
+		return model.UsersResponse{}, client.RateLimitSnapshot{}, err
+	}
+	defer client.SafeClose(resp.Body)
+
+	rateLimits := client.ParseRateLimits(resp)
+	if err := client.CheckResponse(resp); err != nil {
+		return model.UsersResponse{}, rateLimits, err
+	}
+
+	var payload model.UsersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return model.UsersResponse{}, rateLimits, fmt.Errorf("userslookup: decode relation response: %w", err)
+	}
+
+	return payload, rateLimits, nil
+}
+
+func (s *Service) allRelation(ctx context.Context, format, userID string, params map[string]string, maxPages int) (model.UsersResponse, client.RateLimitSnapshot, error) {
+	var (
+		all        model.UsersResponse
+		rateLimits client.RateLimitSnapshot
+	)
+	_, err := client.Paginate(params, client.PaginationTokenParam, maxPages, func(page map[string]string) (string, error) {
+		resp, limits, err := s.listRelation(ctx, format, userID, page)
+		rateLimits = limits
+		if err != nil {
+			return "", err
+		}
+		all.Append(resp)
+		return resp.Meta.NextToken, nil
+	})
+	return all, rateLimits, err
+}
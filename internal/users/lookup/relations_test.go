@@ -0,0 +1,55 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListBlocking(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/2/users/1/blocking", req.URL.Path)
+		require.Equal(t, "100", req.URL.Query().Get("max_results"))
+		_, err := res.Write([]byte(`{"data":[{"id":"2","name":"Spam","username":"spam"}],"meta":{"result_count":1,"next_token":"n1"}}`))
+		require.NoError(t, err)
+	})
+
+	resp, _, err := service.ListBlocking(context.Background(), "1", map[string]string{"max_results": "100"})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, "n1", resp.Meta.NextToken)
+}
+
+func TestAllMutingFollowsPagination(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/2/users/1/muting", req.URL.Path)
+		var body string
+		switch req.URL.Query().Get("pagination_token") {
+		case "":
+			body = `{"data":[{"id":"2","name":"A","username":"a"}],"meta":{"result_count":1,"next_token":"n1"}}`
+		case "n1":
+			body = `{"data":[{"id":"3","name":"B","username":"b"}],"meta":{"result_count":1}}`
+		default:
+			t.Fatalf("unexpected token %q", req.URL.Query().Get("pagination_token"))
+		}
+		_, err := res.Write([]byte(body))
+		require.NoError(t, err)
+	})
+
+	resp, _, err := service.AllMuting(context.Background(), "1", nil, 0)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	require.Equal(t, 2, resp.Meta.ResultCount)
+}
+
+func TestListMutingRequiresUserID(t *testing.T) {
+	service := newTestService(t, func(res http.ResponseWriter, req *http.Request) {
+		t.Fatal("unexpected request")
+	})
+
+	_, _, err := service.ListMuting(context.Background(), "", nil)
+	require.EqualError(t, err, "userslookup: user id is required")
+}
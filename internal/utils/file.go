@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to path through a temporary file in the same
+// directory, so readers see the old file or the new one and never a partial
+// write. Missing parent directories are created private to the user.
+func WriteFileAtomic(path string, data []byte) error {
+	return CopyFileAtomic(path, bytes.NewReader(data))
+}
+
+// CopyFileAtomic is WriteFileAtomic for the contents of r.
+func CopyFileAtomic(path string, r io.Reader) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
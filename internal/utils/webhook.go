@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxWebhookResponse bounds how much of a webhook response is read.
+const MaxWebhookResponse = 64 << 10
+
+// PostJSON posts payload as JSON to url and returns the response body, up to
+// MaxWebhookResponse bytes, with its Content-Type. A status outside 2xx is an
+// error. A nil httpClient uses http.DefaultClient.
+func PostJSON(ctx context.Context, httpClient *http.Client, url string, payload any) ([]byte, string, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxWebhookResponse))
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}